@@ -1,42 +1,41 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
 
-	"github.com/taurusgroup/multi-party-sig/internal/test"
 	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
 	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
 	"github.com/taurusgroup/multi-party-sig/protocols/example"
 	"github.com/taurusgroup/multi-party-sig/protocols/frost"
 )
 
-func XOR(id party.ID, ids party.IDSlice, n *test.Network) error {
+func XOR(id party.ID, ids party.IDSlice, t transport.Transport) error {
 	h, err := protocol.NewMultiHandler(example.StartXOR(id, ids), nil)
 	if err != nil {
 		return err
 	}
-	test.HandlerLoop(id, h, n)
-	_, err = h.Result()
+	_, err = transport.Run(context.Background(), h, t)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func CMPKeygen(id party.ID, ids party.IDSlice, threshold int, n *test.Network, pl *pool.Pool) (*cmp.Config, error) {
+func CMPKeygen(id party.ID, ids party.IDSlice, threshold int, t transport.Transport, pl *pool.Pool) (*cmp.Config, error) {
 	h, err := protocol.NewMultiHandler(cmp.Keygen(curve.Secp256k1{}, id, ids, threshold, pl), nil)
 	if err != nil {
 		return nil, err
 	}
-	test.HandlerLoop(id, h, n)
-	r, err := h.Result()
+	r, err := transport.Run(context.Background(), h, t)
 	if err != nil {
 		return nil, err
 	}
@@ -44,14 +43,12 @@ func CMPKeygen(id party.ID, ids party.IDSlice, threshold int, n *test.Network, p
 	return r.(*cmp.Config), nil
 }
 
-func CMPRefresh(c *cmp.Config, n *test.Network, pl *pool.Pool) (*cmp.Config, error) {
+func CMPRefresh(c *cmp.Config, t transport.Transport, pl *pool.Pool) (*cmp.Config, error) {
 	hRefresh, err := protocol.NewMultiHandler(cmp.Refresh(c, pl), nil)
 	if err != nil {
 		return nil, err
 	}
-	test.HandlerLoop(c.ID, hRefresh, n)
-
-	r, err := hRefresh.Result()
+	r, err := transport.Run(context.Background(), hRefresh, t)
 	if err != nil {
 		return nil, err
 	}
@@ -59,14 +56,12 @@ func CMPRefresh(c *cmp.Config, n *test.Network, pl *pool.Pool) (*cmp.Config, err
 	return r.(*cmp.Config), nil
 }
 
-func CMPSign(c *cmp.Config, m []byte, signers party.IDSlice, n *test.Network, pl *pool.Pool) error {
+func CMPSign(c *cmp.Config, m []byte, signers party.IDSlice, t transport.Transport, pl *pool.Pool) error {
 	h, err := protocol.NewMultiHandler(cmp.Sign(c, signers, m, pl), nil)
 	if err != nil {
 		return err
 	}
-	test.HandlerLoop(c.ID, h, n)
-
-	signResult, err := h.Result()
+	signResult, err := transport.Run(context.Background(), h, t)
 	if err != nil {
 		return err
 	}
@@ -77,15 +72,13 @@ func CMPSign(c *cmp.Config, m []byte, signers party.IDSlice, n *test.Network, pl
 	return nil
 }
 
-func CMPPreSign(c *cmp.Config, signers party.IDSlice, n *test.Network, pl *pool.Pool) (*ecdsa.PreSignature, error) {
+func CMPPreSign(c *cmp.Config, signers party.IDSlice, t transport.Transport, pl *pool.Pool) (*ecdsa.PreSignature, error) {
 	h, err := protocol.NewMultiHandler(cmp.Presign(c, signers, pl), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	test.HandlerLoop(c.ID, h, n)
-
-	signResult, err := h.Result()
+	signResult, err := transport.Run(context.Background(), h, t)
 	if err != nil {
 		return nil, err
 	}
@@ -97,14 +90,12 @@ func CMPPreSign(c *cmp.Config, signers party.IDSlice, n *test.Network, pl *pool.
 	return preSignature, nil
 }
 
-func CMPPreSignOnline(c *cmp.Config, preSignature *ecdsa.PreSignature, m []byte, n *test.Network, pl *pool.Pool) error {
+func CMPPreSignOnline(c *cmp.Config, preSignature *ecdsa.PreSignature, m []byte, t transport.Transport, pl *pool.Pool) error {
 	h, err := protocol.NewMultiHandler(cmp.PresignOnline(c, preSignature, m, pl), nil)
 	if err != nil {
 		return err
 	}
-	test.HandlerLoop(c.ID, h, n)
-
-	signResult, err := h.Result()
+	signResult, err := transport.Run(context.Background(), h, t)
 	if err != nil {
 		return err
 	}
@@ -115,13 +106,12 @@ func CMPPreSignOnline(c *cmp.Config, preSignature *ecdsa.PreSignature, m []byte,
 	return nil
 }
 
-func FrostKeygen(id party.ID, ids party.IDSlice, threshold int, n *test.Network) (*frost.Config, error) {
+func FrostKeygen(id party.ID, ids party.IDSlice, threshold int, t transport.Transport) (*frost.Config, error) {
 	h, err := protocol.NewMultiHandler(frost.Keygen(curve.Secp256k1{}, id, ids, threshold), nil)
 	if err != nil {
 		return nil, err
 	}
-	test.HandlerLoop(id, h, n)
-	r, err := h.Result()
+	r, err := transport.Run(context.Background(), h, t)
 	if err != nil {
 		return nil, err
 	}
@@ -129,13 +119,12 @@ func FrostKeygen(id party.ID, ids party.IDSlice, threshold int, n *test.Network)
 	return r.(*frost.Config), nil
 }
 
-func FrostSign(c *frost.Config, id party.ID, m []byte, signers party.IDSlice, n *test.Network) error {
+func FrostSign(c *frost.Config, id party.ID, m []byte, signers party.IDSlice, t transport.Transport) error {
 	h, err := protocol.NewMultiHandler(frost.Sign(c, signers, m), nil)
 	if err != nil {
 		return err
 	}
-	test.HandlerLoop(id, h, n)
-	r, err := h.Result()
+	r, err := transport.Run(context.Background(), h, t)
 	if err != nil {
 		return err
 	}
@@ -147,26 +136,24 @@ func FrostSign(c *frost.Config, id party.ID, m []byte, signers party.IDSlice, n
 	return nil
 }
 
-func FrostKeygenTaproot(id party.ID, ids party.IDSlice, threshold int, n *test.Network) (*frost.TaprootConfig, error) {
+func FrostKeygenTaproot(id party.ID, ids party.IDSlice, threshold int, t transport.Transport) (*frost.TaprootConfig, error) {
 	h, err := protocol.NewMultiHandler(frost.KeygenTaproot(id, ids, threshold), nil)
 	if err != nil {
 		return nil, err
 	}
-	test.HandlerLoop(id, h, n)
-	r, err := h.Result()
+	r, err := transport.Run(context.Background(), h, t)
 	if err != nil {
 		return nil, err
 	}
 
 	return r.(*frost.TaprootConfig), nil
 }
-func FrostSignTaproot(c *frost.TaprootConfig, id party.ID, m []byte, signers party.IDSlice, n *test.Network) error {
+func FrostSignTaproot(c *frost.TaprootConfig, id party.ID, m []byte, signers party.IDSlice, t transport.Transport) error {
 	h, err := protocol.NewMultiHandler(frost.SignTaproot(c, signers, m), nil)
 	if err != nil {
 		return err
 	}
-	test.HandlerLoop(id, h, n)
-	r, err := h.Result()
+	r, err := transport.Run(context.Background(), h, t)
 	if err != nil {
 		return err
 	}
@@ -178,71 +165,70 @@ func FrostSignTaproot(c *frost.TaprootConfig, id party.ID, m []byte, signers par
 	return nil
 }
 
-func All(id party.ID, ids party.IDSlice, threshold int, message []byte, n *test.Network, wg *sync.WaitGroup, pl *pool.Pool) error {
+func All(id party.ID, ids party.IDSlice, threshold int, message []byte, net *transport.Memory, wg *sync.WaitGroup, pl *pool.Pool) error {
 	defer wg.Done()
 
 	// XOR
-	err := XOR(id, ids, n)
+	err := XOR(id, ids, net.Transport("xor", id, ids))
 	if err != nil {
 		return err
 	}
 
 	// CMP KEYGEN
-	keygenConfig, err := CMPKeygen(id, ids, threshold, n, pl)
+	keygenConfig, err := CMPKeygen(id, ids, threshold, net.Transport("cmp-keygen", id, ids), pl)
 	if err != nil {
 		return err
 	}
 
 	// CMP REFRESH
-	refreshConfig, err := CMPRefresh(keygenConfig, n, pl)
+	refreshConfig, err := CMPRefresh(keygenConfig, net.Transport("cmp-refresh", id, ids), pl)
 	if err != nil {
 		return err
 	}
 
 	// FROST KEYGEN
-	frostResult, err := FrostKeygen(id, ids, threshold, n)
+	frostResult, err := FrostKeygen(id, ids, threshold, net.Transport("frost-keygen", id, ids))
 	if err != nil {
 		return err
 	}
 
 	// FROST KEYGEN TAPROOT
-	frostResultTaproot, err := FrostKeygenTaproot(id, ids, threshold, n)
+	frostResultTaproot, err := FrostKeygenTaproot(id, ids, threshold, net.Transport("frost-keygen-taproot", id, ids))
 	if err != nil {
 		return err
 	}
 
 	signers := ids[:threshold+1]
 	if !signers.Contains(id) {
-		n.Quit(id)
 		return nil
 	}
 
 	// CMP SIGN
-	err = CMPSign(refreshConfig, message, signers, n, pl)
+	err = CMPSign(refreshConfig, message, signers, net.Transport("cmp-sign", id, signers), pl)
 	if err != nil {
 		return err
 	}
 
 	// CMP PRESIGN
-	preSignature, err := CMPPreSign(refreshConfig, signers, n, pl)
+	preSignature, err := CMPPreSign(refreshConfig, signers, net.Transport("cmp-presign", id, signers), pl)
 	if err != nil {
 		return err
 	}
 
 	// CMP PRESIGN ONLINE
-	err = CMPPreSignOnline(refreshConfig, preSignature, message, n, pl)
+	err = CMPPreSignOnline(refreshConfig, preSignature, message, net.Transport("cmp-presign-online", id, signers), pl)
 	if err != nil {
 		return err
 	}
 
 	// FROST SIGN
-	err = FrostSign(frostResult, id, message, signers, n)
+	err = FrostSign(frostResult, id, message, signers, net.Transport("frost-sign", id, signers))
 	if err != nil {
 		return err
 	}
 
 	// FROST SIGN TAPROOT
-	err = FrostSignTaproot(frostResultTaproot, id, message, signers, n)
+	err = FrostSignTaproot(frostResultTaproot, id, message, signers, net.Transport("frost-sign-taproot", id, signers))
 	if err != nil {
 		return err
 	}
@@ -256,7 +242,7 @@ func main() {
 	threshold := 4
 	messageToSign := []byte("hello")
 
-	net := test.NewNetwork(ids)
+	net := transport.NewMemory()
 
 	var wg sync.WaitGroup
 	for _, id := range ids {
@@ -0,0 +1,83 @@
+// Package base58 implements the base58 encoding used by Bitcoin, for keys and addresses.
+package base58
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Encode encodes data using the Bitcoin base58 alphabet.
+func Encode(data []byte) string {
+	x := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	out := make([]byte, 0, len(data)*138/100+1)
+	for x.Sign() > 0 {
+		x.DivMod(x, radix, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	// leading zero bytes are encoded as leading '1's
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// CheckEncode appends the first 4 bytes of SHA-256(SHA-256(data)) to data, and encodes the result.
+func CheckEncode(data []byte) string {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	out := make([]byte, 0, len(data)+4)
+	out = append(out, data...)
+	out = append(out, second[:4]...)
+	return Encode(out)
+}
+
+// Decode decodes a string encoded with the Bitcoin base58 alphabet.
+func Decode(s string) ([]byte, error) {
+	x := new(big.Int)
+	radix := big.NewInt(58)
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(alphabet, s[i])
+		if digit < 0 {
+			return nil, errors.New("base58: invalid character")
+		}
+		x.Mul(x, radix)
+		x.Add(x, big.NewInt(int64(digit)))
+	}
+	// leading '1's are decoded as leading zero bytes
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), x.Bytes()...), nil
+}
+
+// CheckDecode decodes s, and checks and removes the checksum appended by CheckEncode.
+func CheckDecode(s string) ([]byte, error) {
+	data, err := Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, errors.New("base58: missing checksum")
+	}
+	data, checksum := data[:len(data)-4], data[len(data)-4:]
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(checksum, second[:4]) {
+		return nil, errors.New("base58: invalid checksum")
+	}
+	return data, nil
+}
@@ -0,0 +1,31 @@
+package base58
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	assert.Equal(t, "", Encode(nil))
+	assert.Equal(t, "11", Encode([]byte{0, 0}))
+	assert.Equal(t, "StV1DL6CwTryKyV", Encode([]byte("hello world")))
+}
+
+func TestDecode(t *testing.T) {
+	for _, data := range [][]byte{nil, {0, 0}, []byte("hello world"), {0, 1, 2, 255}} {
+		decoded, err := Decode(Encode(data))
+		require.NoError(t, err)
+		assert.Equal(t, len(data), len(decoded))
+		assert.Equal(t, string(data), string(decoded))
+
+		decoded, err = CheckDecode(CheckEncode(data))
+		require.NoError(t, err)
+		assert.Equal(t, string(data), string(decoded))
+	}
+	_, err := Decode("0OIl")
+	assert.Error(t, err)
+	_, err = CheckDecode(Encode([]byte("hello world")))
+	assert.Error(t, err)
+}
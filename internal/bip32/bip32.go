@@ -4,11 +4,25 @@ import (
 	"crypto/hmac"
 	"crypto/sha512"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/taurusgroup/multi-party-sig/internal/base58"
+	"github.com/taurusgroup/multi-party-sig/internal/params"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 )
 
+// HardenedOffset is the first index of a hardened child key.
+const HardenedOffset uint32 = 1 << 31
+
+// Version bytes of extended public keys.
+const (
+	versionMainnet uint32 = 0x0488B21E
+	versionTestnet uint32 = 0x043587CF
+)
+
 // DeriveScalar uses a public point, chaining value, and index, to derive a scalar and chaining value.
 //
 // This scalar should be added to the secret key.
@@ -16,12 +30,12 @@ import (
 // If an error is returned, this means that this index will not be useable, and another
 // index should be used instead.
 //
-// This function will panic if an index for a hardened key is used.
+// Hardened indices can't be derived without the full secret key, so an error is returned for those.
 //
 // See: https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki
 func DeriveScalar(public *curve.Secp256k1Point, chaining []byte, i uint32) (*curve.Secp256k1Scalar, []byte, error) {
-	if i>>31 != 0 {
-		panic("DeriveScalar doesn't work with hardened keys.")
+	if i >= HardenedOffset {
+		return nil, nil, fmt.Errorf("hardened index %d can't be derived from shares", i-HardenedOffset)
 	}
 
 	h := hmac.New(sha512.New, chaining)
@@ -40,3 +54,94 @@ func DeriveScalar(public *curve.Secp256k1Point, chaining []byte, i uint32) (*cur
 
 	return scalar, out[32:], nil
 }
+
+// ParsePath parses a derivation path such as "m/44/60/0/0/5" into a list of indices.
+//
+// The leading "m" is optional. Since derivation happens on shares, hardened segments,
+// such as "44'" or "44h", are rejected with an error.
+func ParsePath(path string) ([]uint32, error) {
+	path = strings.TrimSpace(path)
+	segments := strings.Split(path, "/")
+	if segments[0] == "m" || segments[0] == "M" {
+		segments = segments[1:]
+	}
+	if len(segments) == 1 && segments[0] == "" {
+		return nil, nil
+	}
+	indices := make([]uint32, 0, len(segments))
+	for _, segment := range segments {
+		if strings.HasSuffix(segment, "'") || strings.HasSuffix(segment, "h") || strings.HasSuffix(segment, "H") {
+			return nil, fmt.Errorf("bip32: path %q: hardened segment %q can't be derived from shares", path, segment)
+		}
+		i, err := strconv.ParseUint(segment, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("bip32: path %q: invalid segment %q", path, segment)
+		}
+		if uint32(i) >= HardenedOffset {
+			return nil, fmt.Errorf("bip32: path %q: hardened segment %q can't be derived from shares", path, segment)
+		}
+		indices = append(indices, uint32(i))
+	}
+	return indices, nil
+}
+
+// DerivePath parses a derivation path such as "m/44/60/0/0/5" with ParsePath,
+// and applies deriveChild to key for each of its segments.
+//
+// Only unhardened segments are supported, an error is returned for hardened ones,
+// since they can't be derived from shares.
+func DerivePath[K any](key K, path string, deriveChild func(K, uint32) (K, error)) (K, error) {
+	var zero K
+	indices, err := ParsePath(path)
+	if err != nil {
+		return zero, err
+	}
+	for _, i := range indices {
+		if key, err = deriveChild(key, i); err != nil {
+			return zero, fmt.Errorf("path %q: %w", path, err)
+		}
+	}
+	return key, nil
+}
+
+// ExtendedPublicKey holds the data encoded in an xpub.
+type ExtendedPublicKey struct {
+	// Public is the public key.
+	Public *curve.Secp256k1Point
+	// ChainKey is the 32 byte chain code.
+	ChainKey []byte
+	// Depth is 0 for master keys, 1 for their children, and so on.
+	Depth byte
+	// ParentFingerprint is the first 4 bytes of HASH160 of the parent public key, or zero for master keys.
+	ParentFingerprint [4]byte
+	// ChildIndex is the index of this key in its parent, or zero for master keys.
+	ChildIndex uint32
+}
+
+// Encode returns the base58 encoding of the extended key.
+//
+// The version "xpub" is used if mainnet is true, otherwise "tpub".
+func (k *ExtendedPublicKey) Encode(mainnet bool) (string, error) {
+	if k.Public == nil || k.Public.IsIdentity() {
+		return "", errors.New("bip32: invalid public key")
+	}
+	if len(k.ChainKey) != params.SecBytes {
+		return "", fmt.Errorf("bip32: expected %d bytes for chain key, found %d", params.SecBytes, len(k.ChainKey))
+	}
+	version := versionTestnet
+	if mainnet {
+		version = versionMainnet
+	}
+	data := make([]byte, 0, 78)
+	data = binary.BigEndian.AppendUint32(data, version)
+	data = append(data, k.Depth)
+	data = append(data, k.ParentFingerprint[:]...)
+	data = binary.BigEndian.AppendUint32(data, k.ChildIndex)
+	data = append(data, k.ChainKey...)
+	compressed, err := k.Public.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	data = append(data, compressed...)
+	return base58.CheckEncode(data), nil
+}
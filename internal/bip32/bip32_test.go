@@ -0,0 +1,86 @@
+package bip32
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck
+)
+
+func TestParsePath(t *testing.T) {
+	indices, err := ParsePath("m/44/60/0/0/5")
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{44, 60, 0, 0, 5}, indices)
+
+	indices, err = ParsePath("1/2")
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{1, 2}, indices)
+
+	indices, err = ParsePath("m")
+	require.NoError(t, err)
+	assert.Empty(t, indices)
+
+	for _, path := range []string{"m/44'/60", "m/44h", "m/2147483648", "m/a", "m//1", "m/-1"} {
+		_, err = ParsePath(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestDerivePath(t *testing.T) {
+	var visited []uint32
+	deriveChild := func(key []uint32, i uint32) ([]uint32, error) {
+		if i == 7 {
+			return nil, errors.New("invalid child")
+		}
+		visited = append(visited, i)
+		return append(key, i), nil
+	}
+
+	key, err := DerivePath([]uint32{}, "m/44/60/0", deriveChild)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{44, 60, 0}, key)
+	assert.Equal(t, []uint32{44, 60, 0}, visited)
+
+	_, err = DerivePath([]uint32{}, "m/1/7/2", deriveChild)
+	assert.Error(t, err)
+	_, err = DerivePath([]uint32{}, "m/1'", deriveChild)
+	assert.Error(t, err)
+}
+
+func TestDeriveHardened(t *testing.T) {
+	_, _, err := DeriveScalar(curve.Secp256k1{}.NewBasePoint().(*curve.Secp256k1Point), make([]byte, 32), HardenedOffset)
+	assert.Error(t, err)
+}
+
+// Test vector 2 of BIP-32, using the public derivation of m/0.
+func TestExtendedPublicKey(t *testing.T) {
+	publicBytes, _ := hex.DecodeString("03cbcaa9c98c877a26977d00825c956a238e8dddfbd322cce4f74b0b5bd6ace4a7")
+	chainKey, _ := hex.DecodeString("60499f801b896d83179a4374aeb7822aaeaceaa0db1f85ee3e904c4defbd9689")
+	public := new(curve.Secp256k1Point)
+	require.NoError(t, public.UnmarshalBinary(publicBytes))
+
+	master := &ExtendedPublicKey{Public: public, ChainKey: chainKey}
+	xpub, err := master.Encode(true)
+	require.NoError(t, err)
+	assert.Equal(t, "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB", xpub)
+
+	scalar, childChainKey, err := DeriveScalar(public, chainKey, 0)
+	require.NoError(t, err)
+	child := &ExtendedPublicKey{
+		Public:   public.Add(scalar.ActOnBase()).(*curve.Secp256k1Point),
+		ChainKey: childChainKey,
+		Depth:    1,
+	}
+	sha := sha256.Sum256(publicBytes)
+	h := ripemd160.New()
+	_, _ = h.Write(sha[:])
+	copy(child.ParentFingerprint[:], h.Sum(nil))
+	xpub, err = child.Encode(true)
+	require.NoError(t, err)
+	assert.Equal(t, "xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH", xpub)
+}
@@ -0,0 +1,98 @@
+package bip32
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// DeriveScalarGeneric is like DeriveScalar, but works for any curve.
+//
+// For curve.Secp256k1 and curve.P256, this is the public derivation of BIP-32 and SLIP-10 respectively,
+// and produces the same keys:
+//
+//	I = HMAC-SHA512(Key = chaining, Data = P || i)
+//
+// where P is the binary encoding of public. If I_L isn't smaller than the order of the group,
+// or if the derived public key would be the identity, I is recomputed as
+//
+//	I = HMAC-SHA512(Key = chaining, Data = 0x01 || I_R || i)
+//
+// For curve.Ed25519, see DeriveScalarEd25519.
+//
+// See: https://github.com/satoshilabs/slips/blob/master/slip-0010.md
+func DeriveScalarGeneric(public curve.Point, chaining []byte, i uint32) (curve.Scalar, []byte, error) {
+	if i >= HardenedOffset {
+		return nil, nil, fmt.Errorf("hardened index %d can't be derived from shares", i-HardenedOffset)
+	}
+	if public, ok := public.(*curve.Ed25519Point); ok {
+		return DeriveScalarEd25519(public, chaining, i)
+	}
+	group := public.Curve()
+	data, err := public.MarshalBinary()
+	if err != nil {
+		return nil, nil, fmt.Errorf("bip32: failed to encode public key: %w", err)
+	}
+	iBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(iBytes, i)
+
+	// This loop only repeats with negligible probability.
+	for {
+		h := hmac.New(sha512.New, chaining)
+		_, _ = h.Write(data)
+		_, _ = h.Write(iBytes)
+		out := h.Sum(nil)
+
+		nat := new(saferith.Nat).SetBytes(out[:32])
+		if _, _, lt := nat.CmpMod(group.Order()); lt == 1 {
+			scalar := group.NewScalar().SetNat(nat)
+			if !public.Add(scalar.ActOnBase()).IsIdentity() {
+				return scalar, out[32:], nil
+			}
+		}
+		data = append([]byte{0x01}, out[32:]...)
+	}
+}
+
+// DeriveScalarEd25519 is the unhardened derivation used for curve.Ed25519, where SLIP-10 only defines
+// hardened derivation, which can't be done on shares. Like BIP32-Ed25519, it computes
+//
+//	Z = HMAC-SHA512(Key = chaining, Data = 0x02 || P || i)
+//	C = HMAC-SHA512(Key = chaining, Data = 0x03 || P || i)
+//
+// where P is the 32 byte encoding of public of RFC 8032. The scalar is Z, read as a 64 byte little-endian
+// integer and reduced modulo the order of the group, and the new chaining value is C_R.
+// Unlike BIP32-Ed25519, i is encoded in big-endian, and the scalar isn't a multiple of 8,
+// since shares aren't clamped. This derivation is specific to this library, and the keys
+// it derives differ from those of other Ed25519 wallets.
+func DeriveScalarEd25519(public *curve.Ed25519Point, chaining []byte, i uint32) (*curve.Ed25519Scalar, []byte, error) {
+	if i >= HardenedOffset {
+		return nil, nil, fmt.Errorf("hardened index %d can't be derived from shares", i-HardenedOffset)
+	}
+	data, err := public.MarshalBinary()
+	if err != nil {
+		return nil, nil, fmt.Errorf("bip32: failed to encode public key: %w", err)
+	}
+	iBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(iBytes, i)
+	mac := func(prefix byte) []byte {
+		h := hmac.New(sha512.New, chaining)
+		_, _ = h.Write([]byte{prefix})
+		_, _ = h.Write(data)
+		_, _ = h.Write(iBytes)
+		return h.Sum(nil)
+	}
+
+	scalar, err := new(curve.Ed25519Scalar).SetBytesWide(mac(0x02))
+	if err != nil {
+		return nil, nil, err
+	}
+	if public.Add(scalar.ActOnBase()).IsIdentity() {
+		return nil, nil, fmt.Errorf("bad index: %d", i)
+	}
+	return scalar, mac(0x03)[32:], nil
+}
@@ -0,0 +1,162 @@
+package bip32
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+type genericVector struct {
+	public, chainKey string
+	i                uint32
+	// expected values
+	childPublic, childChainKey string
+}
+
+func checkGenericVectors(t *testing.T, group curve.Curve, vectors []genericVector) {
+	for _, v := range vectors {
+		publicBytes, _ := hex.DecodeString(v.public)
+		chainKey, _ := hex.DecodeString(v.chainKey)
+		public := group.NewPoint()
+		require.NoError(t, public.UnmarshalBinary(publicBytes))
+
+		scalar, childChainKey, err := DeriveScalarGeneric(public, chainKey, v.i)
+		require.NoError(t, err)
+		childPublic, err := public.Add(scalar.ActOnBase()).MarshalBinary()
+		require.NoError(t, err)
+		assert.Equal(t, v.childPublic, hex.EncodeToString(childPublic))
+		assert.Equal(t, v.childChainKey, hex.EncodeToString(childChainKey))
+	}
+}
+
+// Public derivation steps from the nist256p1 test vectors of SLIP-10.
+func TestDeriveScalarGenericP256(t *testing.T) {
+	checkGenericVectors(t, curve.P256{}, []genericVector{
+		// Test vector 1, m/0H -> m/0H/1
+		{
+			public:        "0384610f5ecffe8fda089363a41f56a5c7ffc1d81b59a612d0d649b2d22355590c",
+			chainKey:      "3460cea53e6a6bb5fb391eeef3237ffd8724bf0a40e94943c98b83825342ee11",
+			i:             1,
+			childPublic:   "03526c63f8d0b4bbbf9c80df553fe66742df4676b241dabefdef67733e070f6844",
+			childChainKey: "4187afff1aafa8445010097fb99d23aee9f599450c7bd140b6826ac22ba21d0c",
+		},
+		// Test vector 1, m/0H/1/2H -> m/0H/1/2H/2
+		{
+			public:        "0359cf160040778a4b14c5f4d7b76e327ccc8c4a6086dd9451b7482b5a4972dda0",
+			chainKey:      "98c7514f562e64e74170cc3cf304ee1ce54d6b6da4f880f313e8204c2a185318",
+			i:             2,
+			childPublic:   "029f871f4cb9e1c97f9f4de9ccd0d4a2f2a171110c61178f84430062230833ff20",
+			childChainKey: "ba96f776a5c3907d7fd48bde5620ee374d4acfd540378476019eab70790c63a0",
+		},
+		// Test vector 1, m/0H/1/2H/2 -> m/0H/1/2H/2/1000000000
+		{
+			public:        "029f871f4cb9e1c97f9f4de9ccd0d4a2f2a171110c61178f84430062230833ff20",
+			chainKey:      "ba96f776a5c3907d7fd48bde5620ee374d4acfd540378476019eab70790c63a0",
+			i:             1000000000,
+			childPublic:   "02216cd26d31147f72427a453c443ed2cde8a1e53c9cc44e5ddf739725413fe3f4",
+			childChainKey: "b9b7b82d326bb9cb5b5b121066feea4eb93d5241103c9e7a18aad40f1dde8059",
+		},
+		// Derivation retry, m/28578H -> m/28578H/33941
+		{
+			public:        "02519b5554a4872e8c9c1c847115363051ec43e93400e030ba3c36b52a3e70a5b7",
+			chainKey:      "e94c8ebe30c2250a14713212f6449b20f3329105ea15b652ca5bdfc68f6c65c2",
+			i:             33941,
+			childPublic:   "0235bfee614c0d5b2cae260000bb1d0d84b270099ad790022c1ae0b2e782efe120",
+			childChainKey: "9e87fe95031f14736774cd82f25fd885065cb7c358c1edf813c72af535e83071",
+		},
+	})
+}
+
+// There are no published vectors for DeriveScalarEd25519, since the derivation is specific to this library.
+// These regression vectors start from the public keys of TEST 1 and TEST 2 in RFC 8032,
+// and are reproduced by the separate implementation in testdata/ed25519_derive.py.
+func TestDeriveScalarGenericEd25519(t *testing.T) {
+	public := "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"
+	public2 := "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c"
+	chainKey := "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	checkGenericVectors(t, curve.Ed25519{}, []genericVector{
+		{
+			public:        public,
+			chainKey:      chainKey,
+			i:             0,
+			childPublic:   "6efdd87ab7724dcaa1ffe1d09ebbbecb95badb84a6defe796d02a078d965a1b1",
+			childChainKey: "e56d5ecae89925036c00793f191a89ed1b7a6c74530200112b4f652fcb12a809",
+		},
+		{
+			public:        public,
+			chainKey:      chainKey,
+			i:             1,
+			childPublic:   "f33cc1b6b4e69cbe7856245e72a1b79f79dd4a55bc3e4b9b10fb0d861245a5e7",
+			childChainKey: "e50fa49e5e31d229bbbae7633bed4930354eeed666ae6c468ecc36623145aa9e",
+		},
+		{
+			public:        public,
+			chainKey:      chainKey,
+			i:             7,
+			childPublic:   "2fe67c9ec19780d8635b9cd4bd50827b72d7be2a8cd4f338718bcc2f0aff73c9",
+			childChainKey: "9e7416d1d60b7e37a57dc0b557361bebbec69c0af26560798745e7826b9658e3",
+		},
+		{
+			public:        public,
+			chainKey:      chainKey,
+			i:             1000000000,
+			childPublic:   "e21df34911be728ded2b75e4683e6daefca17b38563618e3f081a51063de2764",
+			childChainKey: "58bfba7d53b7d87308a70c97bc5859697a98cefca963b05a68565d0fcccccf79",
+		},
+		{
+			public:        public2,
+			chainKey:      chainKey,
+			i:             0,
+			childPublic:   "4ec022fe5119efd3dc0ac82d6cc0f8a0312c86b8460d5119e851656aadaba4e1",
+			childChainKey: "ef0fd3f742e7e74ae1c966ac01152e7513275f0c0bdb76dd81b609e8a932455e",
+		},
+		{
+			public:        public2,
+			chainKey:      chainKey,
+			i:             1,
+			childPublic:   "907f1a19469518aef75f2ca3f14d8c986095fbe9a1d8eb8cd62993efaa22c1b1",
+			childChainKey: "1f31e7f0e94bb940685aa4a4c48be48232089e7a63f16543dd9b1ad960da03aa",
+		},
+		{
+			public:        public2,
+			chainKey:      chainKey,
+			i:             7,
+			childPublic:   "440d3e972272eb6c356e5c1c620377c09f8c6a7c12064a8108c4723bb958500e",
+			childChainKey: "9efe586b24cdcc0ac91bb9d6b10fe93f691a93fa8a973f14a0a2274d99b09aea",
+		},
+		{
+			public:        public2,
+			chainKey:      chainKey,
+			i:             1000000000,
+			childPublic:   "c43b57701bd78b7c9744d748527e5edf3cd0b02ea4caf7292c3d8336f7f7e00c",
+			childChainKey: "0979007534b134a240fea69d694d54faa78df8d2c09eebf3cbfacbac57c8f819",
+		},
+		// m/0 -> m/0/1
+		{
+			public:        "6efdd87ab7724dcaa1ffe1d09ebbbecb95badb84a6defe796d02a078d965a1b1",
+			chainKey:      "e56d5ecae89925036c00793f191a89ed1b7a6c74530200112b4f652fcb12a809",
+			i:             1,
+			childPublic:   "4c464573163ceea4896932cd3f3ed1ff2459fb13281449c197d8408fabac6aa0",
+			childChainKey: "1749ee34bb0cb53f811fcf8b7601922b095fffb17860617214400f6390ff9406",
+		},
+	})
+}
+
+func TestDeriveScalarGenericSecp256k1(t *testing.T) {
+	// Test vector 2 of BIP-32, m -> m/0
+	checkGenericVectors(t, curve.Secp256k1{}, []genericVector{
+		{
+			public:        "03cbcaa9c98c877a26977d00825c956a238e8dddfbd322cce4f74b0b5bd6ace4a7",
+			chainKey:      "60499f801b896d83179a4374aeb7822aaeaceaa0db1f85ee3e904c4defbd9689",
+			i:             0,
+			childPublic:   "02fc9e5af0ac8d9b3cecfe2a888e2117ba3d089d8585886c9c826b6b22a98d12ea",
+			childChainKey: "f0909affaa7ee7abe5dd4e100598d4dc53cd709d5a5c2cac40e7412f232f7c9c",
+		},
+	})
+
+	_, _, err := DeriveScalarGeneric(curve.Ed25519{}.NewBasePoint(), make([]byte, 32), HardenedOffset+1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+#!/usr/bin/env python3
+"""Separate implementation of DeriveScalarEd25519, in Python.
+
+Prints the vectors of TestDeriveScalarGenericEd25519, using only the Python
+standard library, and the Ed25519 arithmetic of the reference code in RFC 8032.
+"""
+import hashlib
+import hmac
+
+p = 2**255 - 19
+L = 2**252 + 27742317777372353535851937790883648493
+d = -121665 * pow(121666, p - 2, p) % p
+sqrt_m1 = pow(2, (p - 1) // 4, p)
+
+
+def inv(x):
+    return pow(x, p - 2, p)
+
+
+def add(P, Q):
+    x1, y1, z1, t1 = P
+    x2, y2, z2, t2 = Q
+    A = (y1 - x1) * (y2 - x2) % p
+    B = (y1 + x1) * (y2 + x2) % p
+    C = t1 * 2 * d * t2 % p
+    D = z1 * 2 * z2 % p
+    E, F, G, H = B - A, D - C, D + C, B + A
+    return (E * F % p, G * H % p, F * G % p, E * H % p)
+
+
+def mul(s, P):
+    Q = (0, 1, 1, 0)
+    while s > 0:
+        if s & 1:
+            Q = add(Q, P)
+        P = add(P, P)
+        s >>= 1
+    return Q
+
+
+def recover_x(y, sign):
+    x2 = (y * y - 1) * inv(d * y * y + 1)
+    if x2 == 0:
+        return None if sign else 0
+    x = pow(x2, (p + 3) // 8, p)
+    if (x * x - x2) % p != 0:
+        x = x * sqrt_m1 % p
+    if (x * x - x2) % p != 0:
+        return None
+    if (x & 1) != sign:
+        x = p - x
+    return x
+
+
+def compress(P):
+    x, y, z, _ = P
+    zi = inv(z)
+    x, y = x * zi % p, y * zi % p
+    return int.to_bytes(y | ((x & 1) << 255), 32, "little")
+
+
+def decompress(s):
+    y = int.from_bytes(s, "little")
+    sign = y >> 255
+    y &= (1 << 255) - 1
+    x = recover_x(y, sign)
+    return (x, y, 1, x * y % p)
+
+
+def is_identity(P):
+    x, y, z, _ = P
+    return x % p == 0 and (y - z) % p == 0
+
+
+gy = 4 * inv(5) % p
+gx = recover_x(gy, 0)
+base = (gx, gy, 1, gx * gy % p)
+
+
+def derive(public, chain_key, i):
+    P = decompress(public)
+    index = i.to_bytes(4, "big")
+    z = hmac.new(chain_key, b"\x02" + public + index, hashlib.sha512).digest()
+    c = hmac.new(chain_key, b"\x03" + public + index, hashlib.sha512).digest()
+    k = int.from_bytes(z, "little") % L
+    child = add(P, mul(k, base))
+    assert not is_identity(child)
+    return compress(child), c[32:]
+
+
+if __name__ == "__main__":
+    chain_key = bytes(range(32))
+    # public keys of TEST 1 and TEST 2 in RFC 8032
+    for public in ["d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+                   "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c"]:
+        for i in [0, 1, 7, 1000000000]:
+            child, child_chain_key = derive(bytes.fromhex(public), chain_key, i)
+            print(public, i, child.hex(), child_chain_key.hex())
+    child, child_chain_key = derive(bytes.fromhex("d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"), chain_key, 0)
+    child, child_chain_key = derive(child, child_chain_key, 1)
+    print("m/0/1", child.hex(), child_chain_key.hex())
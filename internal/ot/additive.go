@@ -1,6 +1,8 @@
 package ot
 
 import (
+	"io"
+
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -119,8 +121,8 @@ type AdditiveOTReceiveRound1Message struct {
 }
 
 // Round1 executes the Receiver's first round of an Additive OT.
-func (r *AdditiveOTReceiver) Round1() *AdditiveOTReceiveRound1Message {
-	msg, result := ExtendedOTReceive(r.ctxHash, r.setup, r.choices)
+func (r *AdditiveOTReceiver) Round1(rand io.Reader) *AdditiveOTReceiveRound1Message {
+	msg, result := ExtendedOTReceive(rand, r.ctxHash, r.setup, r.choices)
 	r.result = result
 	return &AdditiveOTReceiveRound1Message{Msg: msg}
 }
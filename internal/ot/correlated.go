@@ -1,8 +1,8 @@
 package ot
 
 import (
-	"crypto/rand"
 	"errors"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
@@ -51,14 +51,14 @@ type CorreOTSetupSendRound1Message struct {
 }
 
 // Round1 executes the Sender's first round of the Correlated OT setup.
-func (r *CorreOTSetupSender) Round1(msg *CorreOTSetupReceiveRound1Message) (*CorreOTSetupSendRound1Message, error) {
+func (r *CorreOTSetupSender) Round1(rand io.Reader, msg *CorreOTSetupReceiveRound1Message) (*CorreOTSetupSendRound1Message, error) {
 	var err error
 	r.setup, err = RandomOTSetupReceive(r.hash, &msg.Msg)
 	if err != nil {
 		return nil, err
 	}
 
-	_, _ = rand.Read(r._Delta[:])
+	_, _ = io.ReadFull(rand, r._Delta[:])
 
 	randomOTNonces := r.hash.Fork(&hash.BytesWithDomain{
 		TheDomain: "CorreOT Random OT Nonces",
@@ -74,7 +74,7 @@ func (r *CorreOTSetupSender) Round1(msg *CorreOTSetupReceiveRound1Message) (*Cor
 	outMsg := new(CorreOTSetupSendRound1Message)
 	errors := r.pl.Parallelize(params.OTParam, func(i int) interface{} {
 		var err error
-		outMsg.Msgs[i], err = r.randomOTReceivers[i].Round1()
+		outMsg.Msgs[i], err = r.randomOTReceivers[i].Round1(rand)
 		return err
 	})
 	for _, err := range errors {
@@ -156,8 +156,8 @@ func EmptyCorreOTSetupReceiveRound1Message(group curve.Curve) *CorreOTSetupRecei
 }
 
 // Round1 runs the first round of a Receiver's correlated OT Setup.
-func (r *CorreOTSetupReceiver) Round1() *CorreOTSetupReceiveRound1Message {
-	msg, setup := RandomOTSetupSend(r.hash, r.group)
+func (r *CorreOTSetupReceiver) Round1(rand io.Reader) *CorreOTSetupReceiveRound1Message {
+	msg, setup := RandomOTSetupSend(rand, r.hash, r.group)
 	r.setup = setup
 
 	randomOTNonces := r.hash.Fork(&hash.BytesWithDomain{
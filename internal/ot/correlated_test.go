@@ -14,8 +14,8 @@ import (
 func runCorreOTSetup(pl *pool.Pool, hash *hash.Hash) (*CorreOTSendSetup, *CorreOTReceiveSetup, error) {
 	sender := NewCorreOTSetupSender(pl, hash.Clone())
 	receiver := NewCorreOTSetupReceiver(pl, hash.Clone(), testGroup)
-	msgR1 := receiver.Round1()
-	msgS1, err := sender.Round1(msgR1)
+	msgR1 := receiver.Round1(rand.Reader)
+	msgS1, err := sender.Round1(rand.Reader, msgR1)
 	if err != nil {
 		fmt.Println(err)
 		return nil, nil, err
@@ -1,9 +1,9 @@
 package ot
 
 import (
-	"crypto/rand"
 	"encoding/binary"
 	"fmt"
+	"io"
 
 	"github.com/taurusgroup/multi-party-sig/internal/params"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
@@ -145,6 +145,8 @@ func ExtendedOTSend(ctxHash *hash.Hash, setup *CorreOTSendSetup, batchSize int,
 // We receive the random vectors corresponding to our choice bits.
 type ExtendedOTReceiveResult struct {
 	_VChoices [][params.OTBytes]byte
+	// The vectors written to the context hash, kept to restore it.
+	_U [params.OTParam][]byte
 }
 
 // ExtendedOTReceiveMessage is the Receiver's first message for an Extended OT.
@@ -162,11 +164,11 @@ type ExtendedOTReceiveMessage struct {
 //
 // A single setup can be used for many invocations of this protocol, so long as the
 // hash is initialized with some kind of nonce.
-func ExtendedOTReceive(ctxHash *hash.Hash, setup *CorreOTReceiveSetup, choices []byte) (*ExtendedOTReceiveMessage, *ExtendedOTReceiveResult) {
+func ExtendedOTReceive(rand io.Reader, ctxHash *hash.Hash, setup *CorreOTReceiveSetup, choices []byte) (*ExtendedOTReceiveMessage, *ExtendedOTReceiveResult) {
 	inflatedBatchSize := 8*len(choices) + params.OTParam + params.StatParam
 	extraChoices := make([]byte, inflatedBatchSize/8)
 	copy(extraChoices, choices)
-	_, _ = rand.Read(extraChoices[len(choices):])
+	_, _ = io.ReadFull(rand, extraChoices[len(choices):])
 
 	correMsg, correResult := CorreOTReceive(ctxHash, setup, extraChoices)
 
@@ -204,5 +206,5 @@ func ExtendedOTReceive(ctxHash *hash.Hash, setup *CorreOTReceiveSetup, choices [
 		_, _ = hasher.Digest().Read(VChoices[i][:])
 	}
 
-	return outMsg, &ExtendedOTReceiveResult{_VChoices: VChoices}
+	return outMsg, &ExtendedOTReceiveResult{_VChoices: VChoices, _U: correMsg.U}
 }
@@ -11,7 +11,7 @@ import (
 )
 
 func runExtendedOT(hash *hash.Hash, choices []byte, sendSetup *CorreOTSendSetup, receiveSetup *CorreOTReceiveSetup) (*ExtendedOTSendResult, *ExtendedOTReceiveResult, error) {
-	msg, receiveResult := ExtendedOTReceive(hash.Clone(), receiveSetup, choices)
+	msg, receiveResult := ExtendedOTReceive(rand.Reader, hash.Clone(), receiveSetup, choices)
 	sendResult, err := ExtendedOTSend(hash.Clone(), sendSetup, 8*len(choices), msg)
 	if err != nil {
 		return nil, nil, err
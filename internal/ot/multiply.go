@@ -1,8 +1,8 @@
 package ot
 
 import (
-	"crypto/rand"
 	"errors"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
@@ -21,13 +21,13 @@ func scalarBytes(group curve.Curve) int {
 // The noise should be public, but the encoding will be unpredictable, but still decodable.
 //
 // The noise vector should have a length that's a multiple of 8
-func encode(beta curve.Scalar, noise []curve.Scalar) ([]byte, error) {
+func encode(rand io.Reader, beta curve.Scalar, noise []curve.Scalar) ([]byte, error) {
 	// This follows Algorithm 4 in Doerner's paper:
 	//   https://eprint.iacr.org/2018/499
 	group := beta.Curve()
 
 	gamma := make([]byte, len(noise)/8)
-	_, _ = rand.Read(gamma)
+	_, _ = io.ReadFull(rand, gamma)
 
 	acc := group.NewScalar().Set(beta)
 	mulNat := new(saferith.Nat)
@@ -87,12 +87,12 @@ type MultiplySender struct {
 // sharing of alpha * beta.
 //
 // This follows Protocol 5 of https://eprint.iacr.org/2018/4990.
-func NewMultiplySender(ctxHash *hash.Hash, setup *CorreOTSendSetup, alpha curve.Scalar) *MultiplySender {
+func NewMultiplySender(rand io.Reader, ctxHash *hash.Hash, setup *CorreOTSendSetup, alpha curve.Scalar) *MultiplySender {
 	group := alpha.Curve()
 	gadget := makeGadget(ctxHash, group)
 	var doubleAlpha [2]curve.Scalar
 	doubleAlpha[0] = alpha
-	doubleAlpha[1] = sample.Scalar(rand.Reader, group)
+	doubleAlpha[1] = sample.Scalar(rand, group)
 	return &MultiplySender{
 		ctxHash:     ctxHash,
 		group:       group,
@@ -174,10 +174,10 @@ type MultiplyReceiver struct {
 // sharing of alpha * beta.
 //
 // This follows Protocol 5 of https://eprint.iacr.org/2018/4990.
-func NewMultiplyReceiver(ctxHash *hash.Hash, setup *CorreOTReceiveSetup, beta curve.Scalar) (*MultiplyReceiver, error) {
+func NewMultiplyReceiver(rand io.Reader, ctxHash *hash.Hash, setup *CorreOTReceiveSetup, beta curve.Scalar) (*MultiplyReceiver, error) {
 	group := beta.Curve()
 	gadget := makeGadget(ctxHash, group)
-	choices, err := encode(beta, gadget[scalarBytes(group):])
+	choices, err := encode(rand, beta, gadget[scalarBytes(group):])
 	if err != nil {
 		return nil, err
 	}
@@ -198,8 +198,8 @@ type MultiplyReceiveRound1Message struct {
 }
 
 // Round1 runs the first round for the Receiver in the multiplication protocol.
-func (r *MultiplyReceiver) Round1() *MultiplyReceiveRound1Message {
-	msg := r.receiver.Round1()
+func (r *MultiplyReceiver) Round1(rand io.Reader) *MultiplyReceiveRound1Message {
+	msg := r.receiver.Round1(rand)
 	return &MultiplyReceiveRound1Message{msg}
 }
 
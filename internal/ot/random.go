@@ -1,9 +1,9 @@
 package ot
 
 import (
-	"crypto/rand"
 	"crypto/subtle"
 	"fmt"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
@@ -44,10 +44,10 @@ type RandomOTSendSetup struct {
 // if that's desired.
 //
 // This setup can be done once and then used for multiple executions.
-func RandomOTSetupSend(hash *hash.Hash, group curve.Curve) (*RandomOTSetupSendMessage, *RandomOTSendSetup) {
-	b := sample.Scalar(rand.Reader, group)
+func RandomOTSetupSend(rand io.Reader, hash *hash.Hash, group curve.Curve) (*RandomOTSetupSendMessage, *RandomOTSendSetup) {
+	b := sample.Scalar(rand, group)
 	B := b.ActOnBase()
-	BProof := zksch.NewProof(hash, B, b, nil)
+	BProof := zksch.NewProof(rand, hash, B, b, nil)
 	return &RandomOTSetupSendMessage{B: B, BProof: BProof}, &RandomOTSendSetup{_B: B, b: b, _bB: b.Act(B)}
 }
 
@@ -76,6 +76,7 @@ func RandomOTSetupReceive(hash *hash.Hash, msg *RandomOTSetupSendMessage) (*Rand
 // This should be created from a saved setup, for each execution.
 type RandomOTReceiever struct {
 	// After setup
+	nonce []byte
 	hash  *blake3.Hasher
 	group curve.Curve
 	// Which random message we want to receive.
@@ -106,6 +107,7 @@ func NewRandomOTReceiver(nonce []byte, result *RandomOTReceiveSetup, choice safe
 	if err != nil {
 		panic(err)
 	}
+	out.nonce = nonce
 	out.group = result._B.Curve()
 	out.choice = choice
 	out._B = result._B
@@ -121,11 +123,11 @@ type RandomOTReceiveRound1Message struct {
 // Round1 executes the receiver's side of round 1 of a Random OT.
 //
 // This is the starting point for a Random OT.
-func (r *RandomOTReceiever) Round1() (outMsg RandomOTReceiveRound1Message, err error) {
+func (r *RandomOTReceiever) Round1(rand io.Reader) (outMsg RandomOTReceiveRound1Message, err error) {
 	// We sample a <- Z_q, and then compute
 	//   A = a * G + w * B
 	//   randChoice = H(a * B)
-	a := sample.Scalar(rand.Reader, r.group)
+	a := sample.Scalar(rand, r.group)
 	A := a.ActOnBase()
 	outMsg.ABytes, err = A.MarshalBinary()
 	if err != nil {
@@ -218,6 +220,7 @@ func (r *RandomOTReceiever) Round3(msg *RandomOTSendRound2Message) ([params.OTBy
 // This should be created from a saved setup, for each execution.
 type RandomOTSender struct {
 	// After setup
+	nonce []byte
 	hash  *blake3.Hasher
 	group curve.Curve
 	b     curve.Scalar
@@ -243,6 +246,7 @@ func NewRandomOTSender(nonce []byte, result *RandomOTSendSetup) (out RandomOTSen
 	if err != nil {
 		panic(err)
 	}
+	out.nonce = nonce
 	out.group = result.b.Curve()
 	out.b = result.b
 	out._B = result._B
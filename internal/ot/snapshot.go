@@ -0,0 +1,249 @@
+package ot
+
+import (
+	"errors"
+
+	"github.com/cronokirby/saferith"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/zeebo/blake3"
+)
+
+// correOTSendSetupMarshal is the serialized form of CorreOTSendSetup.
+type correOTSendSetupMarshal struct {
+	Delta  [params.OTBytes]byte
+	KDelta [params.OTParam][params.OTBytes]byte
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (r *CorreOTSendSetup) MarshalBinary() ([]byte, error) {
+	return cbor.Marshal(&correOTSendSetupMarshal{Delta: r._Delta, KDelta: r._K_Delta})
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (r *CorreOTSendSetup) UnmarshalBinary(data []byte) error {
+	var m correOTSendSetupMarshal
+	if err := cbor.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	r._Delta = m.Delta
+	r._K_Delta = m.KDelta
+	return nil
+}
+
+// correOTReceiveSetupMarshal is the serialized form of CorreOTReceiveSetup.
+type correOTReceiveSetupMarshal struct {
+	K0, K1 [params.OTParam][params.OTBytes]byte
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (r *CorreOTReceiveSetup) MarshalBinary() ([]byte, error) {
+	return cbor.Marshal(&correOTReceiveSetupMarshal{K0: r._K_0, K1: r._K_1})
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (r *CorreOTReceiveSetup) UnmarshalBinary(data []byte) error {
+	var m correOTReceiveSetupMarshal
+	if err := cbor.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	r._K_0 = m.K0
+	r._K_1 = m.K1
+	return nil
+}
+
+// correOTSetupSenderMarshal is the serialized form of CorreOTSetupSender.
+//
+// The Random OT receivers are only saved after Round1, at which point their public setup is no longer needed.
+type correOTSetupSenderMarshal struct {
+	Started   bool
+	Delta     [params.OTBytes]byte
+	Receivers [params.OTParam]randomOTReceiverMarshal
+}
+
+type randomOTReceiverMarshal struct {
+	Nonce             []byte
+	RandChoice        [params.OTBytes]byte
+	ReceivedChallenge [params.OTBytes]byte
+	HHRandChoice      [params.OTBytes]byte
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// This saves the state reached after the previous rounds, so that the setup can be continued later.
+func (r *CorreOTSetupSender) MarshalBinary() ([]byte, error) {
+	m := correOTSetupSenderMarshal{Started: r.setup != nil, Delta: r._Delta}
+	for i := range r.randomOTReceivers {
+		receiver := &r.randomOTReceivers[i]
+		m.Receivers[i] = randomOTReceiverMarshal{
+			Nonce:             receiver.nonce,
+			RandChoice:        receiver.randChoice,
+			ReceivedChallenge: receiver.receivedChallenge,
+			HHRandChoice:      receiver.hh_randChoice,
+		}
+	}
+	return cbor.Marshal(&m)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+//
+// r should have been created by NewCorreOTSetupSender, with the same parameters as the original.
+func (r *CorreOTSetupSender) UnmarshalBinary(data []byte) error {
+	var m correOTSetupSenderMarshal
+	if err := cbor.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	if !m.Started {
+		return nil
+	}
+	r.setup = new(RandomOTReceiveSetup)
+	r._Delta = m.Delta
+	for i := range r.randomOTReceivers {
+		hasher, err := blake3.NewKeyed(m.Receivers[i].Nonce)
+		if err != nil {
+			return err
+		}
+		r.randomOTReceivers[i] = RandomOTReceiever{
+			nonce:             m.Receivers[i].Nonce,
+			hash:              hasher,
+			choice:            saferith.Choice(bitAt(i, r._Delta[:])),
+			randChoice:        m.Receivers[i].RandChoice,
+			receivedChallenge: m.Receivers[i].ReceivedChallenge,
+			hh_randChoice:     m.Receivers[i].HHRandChoice,
+		}
+	}
+	return nil
+}
+
+// correOTSetupReceiverMarshal is the serialized form of CorreOTSetupReceiver.
+type correOTSetupReceiverMarshal struct {
+	// B is the secret key of the Random OT setup, or nil before Round1.
+	B       []byte
+	Senders [params.OTParam]randomOTSenderMarshal
+}
+
+type randomOTSenderMarshal struct {
+	Nonce      []byte
+	Rand0      [params.OTBytes]byte
+	Rand1      [params.OTBytes]byte
+	Decommit0  [params.OTBytes]byte
+	Decommit1  [params.OTBytes]byte
+	HDecommit0 [params.OTBytes]byte
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// This saves the state reached after the previous rounds, so that the setup can be continued later.
+func (r *CorreOTSetupReceiver) MarshalBinary() ([]byte, error) {
+	var m correOTSetupReceiverMarshal
+	if r.setup != nil {
+		var err error
+		if m.B, err = r.setup.b.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	for i := range r.randomOTSenders {
+		sender := &r.randomOTSenders[i]
+		m.Senders[i] = randomOTSenderMarshal{
+			Nonce:      sender.nonce,
+			Rand0:      sender.rand0,
+			Rand1:      sender.rand1,
+			Decommit0:  sender.decommit0,
+			Decommit1:  sender.decommit1,
+			HDecommit0: sender.h_decommit0,
+		}
+	}
+	return cbor.Marshal(&m)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+//
+// r should have been created by NewCorreOTSetupReceiver, with the same parameters as the original.
+func (r *CorreOTSetupReceiver) UnmarshalBinary(data []byte) error {
+	var m correOTSetupReceiverMarshal
+	if err := cbor.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	if m.B == nil {
+		return nil
+	}
+	b := r.group.NewScalar()
+	if err := b.UnmarshalBinary(m.B); err != nil {
+		return err
+	}
+	B := b.ActOnBase()
+	r.setup = &RandomOTSendSetup{b: b, _B: B, _bB: b.Act(B)}
+	for i := range r.randomOTSenders {
+		r.randomOTSenders[i] = NewRandomOTSender(m.Senders[i].Nonce, r.setup)
+		sender := &r.randomOTSenders[i]
+		sender.rand0 = m.Senders[i].Rand0
+		sender.rand1 = m.Senders[i].Rand1
+		sender.decommit0 = m.Senders[i].Decommit0
+		sender.decommit1 = m.Senders[i].Decommit1
+		sender.h_decommit0 = m.Senders[i].HDecommit0
+	}
+	return nil
+}
+
+// multiplyReceiverMarshal is the serialized form of MultiplyReceiver.
+type multiplyReceiverMarshal struct {
+	Beta     []byte
+	Choices  []byte
+	VChoices [][params.OTBytes]byte
+	U        [params.OTParam][]byte
+}
+
+// EmptyMultiplyReceiver creates a MultiplyReceiver ready to be unmarshalled.
+//
+// ctxHash and setup should be the same as those passed to NewMultiplyReceiver.
+func EmptyMultiplyReceiver(ctxHash *hash.Hash, setup *CorreOTReceiveSetup, group curve.Curve) *MultiplyReceiver {
+	return &MultiplyReceiver{
+		ctxHash: ctxHash,
+		group:   group,
+		setup:   setup,
+		gadget:  makeGadget(ctxHash, group),
+	}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (r *MultiplyReceiver) MarshalBinary() ([]byte, error) {
+	beta, err := r.beta.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	m := multiplyReceiverMarshal{Beta: beta, Choices: r.choices}
+	if r.receiver.result != nil {
+		m.VChoices = r.receiver.result._VChoices
+		m.U = r.receiver.result._U
+	}
+	return cbor.Marshal(&m)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (r *MultiplyReceiver) UnmarshalBinary(data []byte) error {
+	if r.group == nil {
+		return errors.New("MultiplyReceiver.UnmarshalBinary: must be created with EmptyMultiplyReceiver")
+	}
+	var m multiplyReceiverMarshal
+	if err := cbor.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	r.beta = r.group.NewScalar()
+	if err := r.beta.UnmarshalBinary(m.Beta); err != nil {
+		return err
+	}
+	r.choices = m.Choices
+	r.receiver = NewAdditiveOTReceiver(r.ctxHash, r.setup, r.group, r.choices)
+	if m.VChoices != nil {
+		r.receiver.result = &ExtendedOTReceiveResult{_VChoices: m.VChoices, _U: m.U}
+		// Round1 wrote these to the context hash, which is shared with the Receiver
+		for i := 0; i < params.OTParam; i++ {
+			if err := r.ctxHash.WriteAny(m.U[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package ot
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+)
+
+func restoreCorreOTSetup(pl *pool.Pool, hash *hash.Hash, sender *CorreOTSetupSender, receiver *CorreOTSetupReceiver) (*CorreOTSetupSender, *CorreOTSetupReceiver, error) {
+	senderData, err := sender.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	receiverData, err := receiver.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	sender = NewCorreOTSetupSender(pl, hash.Clone())
+	if err = sender.UnmarshalBinary(senderData); err != nil {
+		return nil, nil, err
+	}
+	receiver = NewCorreOTSetupReceiver(pl, hash.Clone(), testGroup)
+	if err = receiver.UnmarshalBinary(receiverData); err != nil {
+		return nil, nil, err
+	}
+	return sender, receiver, nil
+}
+
+func TestCorreOTSetupSnapshot(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	H := hash.New()
+	sender := NewCorreOTSetupSender(pl, H.Clone())
+	receiver := NewCorreOTSetupReceiver(pl, H.Clone(), testGroup)
+	msgR1 := receiver.Round1(rand.Reader)
+	msgS1, err := sender.Round1(rand.Reader, msgR1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender, receiver, err = restoreCorreOTSetup(pl, H, sender, receiver); err != nil {
+		t.Fatal(err)
+	}
+	msgR2, err := receiver.Round2(msgS1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgS2 := sender.Round2(msgR2)
+	if sender, receiver, err = restoreCorreOTSetup(pl, H, sender, receiver); err != nil {
+		t.Fatal(err)
+	}
+	msgR3, receiveSetup, err := receiver.Round3(msgS2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendSetup, err := sender.Round3(msgR3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the setups themselves are saved in configs
+	data, err := sendSetup.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendSetup = new(CorreOTSendSetup)
+	if err = sendSetup.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if data, err = receiveSetup.MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+	receiveSetup = new(CorreOTReceiveSetup)
+	if err = receiveSetup.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	alpha := sample.Scalar(rand.Reader, testGroup)
+	beta := sample.Scalar(rand.Reader, testGroup)
+	a, b, err := runMultiply(H, sendSetup, receiveSetup, alpha, beta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !testGroup.NewScalar().Set(alpha).Mul(beta).Equal(a.Add(b)) {
+		t.Error("multiply failed to produce valid shares")
+	}
+}
+
+func TestMultiplyReceiverSnapshot(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	sendSetup, receiveSetup, err := runCorreOTSetup(pl, hash.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	H := hash.New()
+	alpha := sample.Scalar(rand.Reader, testGroup)
+	beta := sample.Scalar(rand.Reader, testGroup)
+	sender := NewMultiplySender(rand.Reader, H.Clone(), sendSetup, alpha)
+	receiver, err := NewMultiplyReceiver(rand.Reader, H.Clone(), receiveSetup, beta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgR1 := receiver.Round1(rand.Reader)
+
+	data, err := receiver.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver = EmptyMultiplyReceiver(H.Clone(), receiveSetup, testGroup)
+	if err = receiver.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	msgS1, a, err := sender.Round1(msgR1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := receiver.Round2(msgS1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !testGroup.NewScalar().Set(alpha).Mul(beta).Equal(a.Add(b)) {
+		t.Error("multiply failed to produce valid shares")
+	}
+}
@@ -7,3 +7,19 @@ var (
 	ErrInvalidContent = errors.New("content is not the right type")
 	ErrOutChanFull    = errors.New("content is not the right type")
 )
+
+// Kinds of misbehavior, which rounds wrap in the errors returned by VerifyMessage or given to AbortRound,
+// so that they can be identified once the protocol aborts.
+var (
+	// ErrMalformed is a message which could not be decoded, or contains values which are out of range.
+	ErrMalformed = errors.New("malformed message")
+	// ErrInvalidProof is a zero-knowledge proof which failed to verify.
+	ErrInvalidProof = errors.New("invalid zero-knowledge proof")
+	// ErrInvalidCommitment is a decommitment which doesn't match the commitment sent earlier.
+	ErrInvalidCommitment = errors.New("invalid decommitment")
+	// ErrInvalidShare is a share which doesn't match the public values it should be consistent with,
+	// or which results in an invalid output.
+	ErrInvalidShare = errors.New("invalid share")
+	// ErrInconsistent means that parties disagree on values which should be the same for all.
+	ErrInconsistent = errors.New("inconsistent values")
+)
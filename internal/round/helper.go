@@ -1,8 +1,11 @@
 package round
 
 import (
+	"bytes"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"sync"
 
@@ -21,6 +24,9 @@ type Helper struct {
 	// Pool allows us to parallelize certain operations
 	Pool *pool.Pool
 
+	// rand is Info.Rand, which may be read from several goroutines of Pool.
+	rand io.Reader
+
 	// partyIDs is a sorted slice of Info.PartyIDs.
 	partyIDs party.IDSlice
 	// otherPartyIDs is the same as partyIDs without selfID
@@ -30,6 +36,8 @@ type Helper struct {
 	ssid []byte
 
 	hash *hash.Hash
+	// hashUpdates is the data written to hash by UpdateHashState, in order.
+	hashUpdates []hash.BytesWithDomain
 
 	mtx sync.Mutex
 }
@@ -109,6 +117,7 @@ func NewSession(info Info, sessionID []byte, pl *pool.Pool, auxInfo ...hash.Writ
 	return &Helper{
 		info:          info,
 		Pool:          pl,
+		rand:          lockedRand(info.Rand),
 		partyIDs:      partyIDs,
 		otherPartyIDs: partyIDs.Remove(info.SelfID),
 		ssid:          h.Clone().Sum(),
@@ -133,7 +142,40 @@ func (h *Helper) HashForID(id party.ID) *hash.Hash {
 func (h *Helper) UpdateHashState(value hash.WriterToWithDomain) {
 	h.mtx.Lock()
 	defer h.mtx.Unlock()
-	_ = h.hash.WriteAny(value)
+	// record the data as written, so that the hash state can be restored later
+	var buf bytes.Buffer
+	if _, err := value.WriteTo(&buf); err != nil {
+		return
+	}
+	update := hash.BytesWithDomain{TheDomain: value.Domain(), Bytes: buf.Bytes()}
+	h.hashUpdates = append(h.hashUpdates, update)
+	_ = h.hash.WriteAny(&update)
+}
+
+// HashUpdates returns the data written by UpdateHashState since the session was created.
+//
+// Together with the session's initial parameters, this is enough to recreate the current hash state.
+func (h *Helper) HashUpdates() []hash.BytesWithDomain {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return append([]hash.BytesWithDomain(nil), h.hashUpdates...)
+}
+
+// RestoreHashUpdates replays updates returned by HashUpdates on a newly created session.
+func (h *Helper) RestoreHashUpdates(updates []hash.BytesWithDomain) error {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if len(h.hashUpdates) != 0 {
+		return errors.New("session: hash state was already updated")
+	}
+	for i := range updates {
+		update := updates[i]
+		if err := h.hash.WriteAny(&update); err != nil {
+			return fmt.Errorf("session: %w", err)
+		}
+		h.hashUpdates = append(h.hashUpdates, update)
+	}
+	return nil
 }
 
 // BroadcastMessage constructs a Message from the broadcast Content, and sets the header correctly.
@@ -222,3 +264,14 @@ func (h *Helper) N() int { return len(h.info.PartyIDs) }
 
 // Group returns the curve used for this protocol.
 func (h *Helper) Group() curve.Curve { return h.info.Group }
+
+// Rand returns the source of randomness of this party, which is safe for concurrent use.
+func (h *Helper) Rand() io.Reader { return h.rand }
+
+// lockedRand returns crypto/rand.Reader if r is nil, or a wrapper around r which is safe for concurrent use.
+func lockedRand(r io.Reader) io.Reader {
+	if r == nil || r == rand.Reader {
+		return rand.Reader
+	}
+	return pool.NewLockedReader(r)
+}
@@ -1,6 +1,8 @@
 package round
 
 import (
+	"io"
+
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
@@ -19,6 +21,8 @@ type Info struct {
 	Threshold int
 	// Group returns the group used for this protocol execution.
 	Group curve.Curve
+	// Rand is the source of randomness of this party, or nil to use crypto/rand.Reader.
+	Rand io.Reader
 }
 
 // Session represents the current execution of a round-based protocol.
@@ -28,6 +32,8 @@ type Session interface {
 	Round
 	// Group returns the group used for this protocol execution.
 	Group() curve.Curve
+	// Rand returns the source of randomness of this party.
+	Rand() io.Reader
 	// Hash returns a cloned hash function with the current hash state.
 	Hash() *hash.Hash
 	// ProtocolID is an identifier for this protocol.
@@ -0,0 +1,20 @@
+package round
+
+import "errors"
+
+// ErrNoSnapshot is returned when a protocol doesn't support saving and resuming its execution.
+var ErrNoSnapshot = errors.New("round: protocol doesn't support snapshots")
+
+// Restorer is implemented by the first round of protocols whose execution can be saved and resumed later.
+//
+// Since every round of a protocol embeds the previous one, the later rounds implement it as well.
+type Restorer interface {
+	// Snapshot serializes the state of r, which must be a round of the protocol execution this round started.
+	Snapshot(r Session) ([]byte, error)
+
+	// Restore recreates the round with the given number from data returned by Snapshot.
+	//
+	// It must only be called on a newly created first round, which provides the parameters
+	// that are not part of the snapshot, like the config and the pool.
+	Restore(number Number, data []byte) (Session, error)
+}
@@ -0,0 +1,407 @@
+// Package snapshot helps protocols serialize the state of their rounds.
+//
+// Rounds hold values like curve.Scalar and curve.Point, which can only be decoded once the group is known.
+// An Encoder turns them into bytes, and a Decoder turns them back into values of a given group.
+// Both remember the first error they encounter, so that a whole state can be converted before checking Err.
+package snapshot
+
+import (
+	"encoding"
+	"fmt"
+
+	"github.com/cronokirby/saferith"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/elgamal"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+)
+
+// Pedersen holds the public parameters of pedersen.Parameters.
+type Pedersen struct {
+	N    *saferith.Modulus
+	S, T *saferith.Nat
+}
+
+// Encoder converts values to bytes. Nil values are encoded as nil.
+type Encoder struct {
+	err error
+}
+
+// Err returns the first error encountered by the Encoder.
+func (e *Encoder) Err() error {
+	return e.err
+}
+
+func (e *Encoder) marshal(v interface{}) []byte {
+	data, err := cbor.Marshal(v)
+	if err != nil && e.err == nil {
+		e.err = err
+	}
+	return data
+}
+
+// Binary encodes a value with its own binary encoding.
+func (e *Encoder) Binary(m encoding.BinaryMarshaler) []byte {
+	data, err := m.MarshalBinary()
+	if err != nil && e.err == nil {
+		e.err = err
+	}
+	return data
+}
+
+// Scalar encodes s.
+func (e *Encoder) Scalar(s curve.Scalar) []byte {
+	if s == nil {
+		return nil
+	}
+	return e.marshal(s)
+}
+
+// Point encodes p.
+func (e *Encoder) Point(p curve.Point) []byte {
+	if p == nil {
+		return nil
+	}
+	return e.marshal(p)
+}
+
+// Scalars encodes a map of scalars.
+func (e *Encoder) Scalars(m map[party.ID]curve.Scalar) map[party.ID][]byte {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID][]byte, len(m))
+	for id, s := range m {
+		out[id] = e.Scalar(s)
+	}
+	return out
+}
+
+// Points encodes a map of points.
+func (e *Encoder) Points(m map[party.ID]curve.Point) map[party.ID][]byte {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID][]byte, len(m))
+	for id, p := range m {
+		out[id] = e.Point(p)
+	}
+	return out
+}
+
+// Polynomial encodes a secret polynomial.
+func (e *Encoder) Polynomial(p *polynomial.Polynomial) []byte {
+	if p == nil {
+		return nil
+	}
+	return e.marshal(p)
+}
+
+// Exponents encodes a map of polynomials in the exponent.
+func (e *Encoder) Exponents(m map[party.ID]*polynomial.Exponent) map[party.ID][]byte {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID][]byte, len(m))
+	for id, p := range m {
+		if p != nil {
+			out[id] = e.marshal(p)
+		}
+	}
+	return out
+}
+
+// SchnorrRandomness encodes the randomness of a Schnorr proof.
+func (e *Encoder) SchnorrRandomness(r *zksch.Randomness) []byte {
+	if r == nil {
+		return nil
+	}
+	return e.marshal(r)
+}
+
+// SchnorrCommitments encodes a map of Schnorr commitments.
+func (e *Encoder) SchnorrCommitments(m map[party.ID]*zksch.Commitment) map[party.ID][]byte {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID][]byte, len(m))
+	for id, c := range m {
+		if c != nil {
+			out[id] = e.Point(c.C)
+		}
+	}
+	return out
+}
+
+// SchnorrProof encodes a Schnorr proof.
+func (e *Encoder) SchnorrProof(p *zksch.Proof) []byte {
+	if p == nil {
+		return nil
+	}
+	return e.marshal(p)
+}
+
+// ElGamalCiphertexts encodes a map of ElGamal ciphertexts.
+func (e *Encoder) ElGamalCiphertexts(m map[party.ID]*elgamal.Ciphertext) map[party.ID][]byte {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID][]byte, len(m))
+	for id, c := range m {
+		if c != nil {
+			out[id] = e.marshal(c)
+		}
+	}
+	return out
+}
+
+// PreSignature encodes an ECDSA pre-signature.
+func (e *Encoder) PreSignature(p *ecdsa.PreSignature) []byte {
+	if p == nil {
+		return nil
+	}
+	return e.marshal(p)
+}
+
+// PaillierSecret returns the primes of a Paillier secret key.
+func (e *Encoder) PaillierSecret(sk *paillier.SecretKey) []*saferith.Nat {
+	if sk == nil {
+		return nil
+	}
+	return []*saferith.Nat{sk.P(), sk.Q()}
+}
+
+// PaillierPublic returns the moduli of a map of Paillier public keys.
+func (e *Encoder) PaillierPublic(m map[party.ID]*paillier.PublicKey) map[party.ID]*saferith.Modulus {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID]*saferith.Modulus, len(m))
+	for id, pk := range m {
+		if pk != nil {
+			out[id] = pk.N()
+		}
+	}
+	return out
+}
+
+// Pedersen returns the parameters of a map of pedersen.Parameters.
+func (e *Encoder) Pedersen(m map[party.ID]*pedersen.Parameters) map[party.ID]*Pedersen {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID]*Pedersen, len(m))
+	for id, p := range m {
+		if p != nil {
+			out[id] = &Pedersen{N: p.N(), S: p.S(), T: p.T()}
+		}
+	}
+	return out
+}
+
+// Decoder converts bytes produced by an Encoder back to values of a given group.
+type Decoder struct {
+	group curve.Curve
+	err   error
+}
+
+// NewDecoder returns a Decoder for values of the given group.
+func NewDecoder(group curve.Curve) *Decoder {
+	return &Decoder{group: group}
+}
+
+// Err returns the first error encountered by the Decoder.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+func (d *Decoder) unmarshal(data []byte, v interface{}, what string) {
+	if d.err != nil {
+		return
+	}
+	if err := cbor.Unmarshal(data, v); err != nil {
+		d.err = fmt.Errorf("snapshot: %s: %w", what, err)
+	}
+}
+
+// Binary decodes data into u, which should be prepared by the caller, usually with the static parameters of the value.
+func (d *Decoder) Binary(data []byte, u encoding.BinaryUnmarshaler, what string) {
+	if d.err != nil {
+		return
+	}
+	if err := u.UnmarshalBinary(data); err != nil {
+		d.err = fmt.Errorf("snapshot: %s: %w", what, err)
+	}
+}
+
+// Scalar decodes a scalar.
+func (d *Decoder) Scalar(data []byte) curve.Scalar {
+	if data == nil {
+		return nil
+	}
+	s := d.group.NewScalar()
+	d.unmarshal(data, s, "scalar")
+	return s
+}
+
+// Point decodes a point.
+func (d *Decoder) Point(data []byte) curve.Point {
+	if data == nil {
+		return nil
+	}
+	p := d.group.NewPoint()
+	d.unmarshal(data, p, "point")
+	return p
+}
+
+// Scalars decodes a map of scalars.
+func (d *Decoder) Scalars(m map[party.ID][]byte) map[party.ID]curve.Scalar {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID]curve.Scalar, len(m))
+	for id, data := range m {
+		out[id] = d.Scalar(data)
+	}
+	return out
+}
+
+// Points decodes a map of points.
+func (d *Decoder) Points(m map[party.ID][]byte) map[party.ID]curve.Point {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID]curve.Point, len(m))
+	for id, data := range m {
+		out[id] = d.Point(data)
+	}
+	return out
+}
+
+// Polynomial decodes a secret polynomial.
+func (d *Decoder) Polynomial(data []byte) *polynomial.Polynomial {
+	if data == nil {
+		return nil
+	}
+	p := polynomial.EmptyPolynomial(d.group)
+	d.unmarshal(data, p, "polynomial")
+	return p
+}
+
+// Exponents decodes a map of polynomials in the exponent.
+func (d *Decoder) Exponents(m map[party.ID][]byte) map[party.ID]*polynomial.Exponent {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID]*polynomial.Exponent, len(m))
+	for id, data := range m {
+		p := polynomial.EmptyExponent(d.group)
+		d.unmarshal(data, p, "exponent")
+		out[id] = p
+	}
+	return out
+}
+
+// SchnorrRandomness decodes the randomness of a Schnorr proof.
+func (d *Decoder) SchnorrRandomness(data []byte) *zksch.Randomness {
+	if data == nil {
+		return nil
+	}
+	r := zksch.EmptyRandomness(d.group)
+	d.unmarshal(data, r, "schnorr randomness")
+	return r
+}
+
+// SchnorrCommitments decodes a map of Schnorr commitments.
+func (d *Decoder) SchnorrCommitments(m map[party.ID][]byte) map[party.ID]*zksch.Commitment {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID]*zksch.Commitment, len(m))
+	for id, data := range m {
+		out[id] = &zksch.Commitment{C: d.Point(data)}
+	}
+	return out
+}
+
+// SchnorrProof decodes a Schnorr proof.
+func (d *Decoder) SchnorrProof(data []byte) *zksch.Proof {
+	if data == nil {
+		return nil
+	}
+	p := zksch.EmptyProof(d.group)
+	d.unmarshal(data, p, "schnorr proof")
+	return p
+}
+
+// ElGamalCiphertexts decodes a map of ElGamal ciphertexts.
+func (d *Decoder) ElGamalCiphertexts(m map[party.ID][]byte) map[party.ID]*elgamal.Ciphertext {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID]*elgamal.Ciphertext, len(m))
+	for id, data := range m {
+		c := elgamal.Empty(d.group)
+		d.unmarshal(data, c, "elgamal ciphertext")
+		out[id] = c
+	}
+	return out
+}
+
+// PreSignature decodes an ECDSA pre-signature.
+func (d *Decoder) PreSignature(data []byte) *ecdsa.PreSignature {
+	if data == nil {
+		return nil
+	}
+	p := ecdsa.EmptyPreSignature(d.group)
+	d.unmarshal(data, p, "presignature")
+	return p
+}
+
+// PaillierSecret recreates a Paillier secret key from its primes.
+func (d *Decoder) PaillierSecret(primes []*saferith.Nat) *paillier.SecretKey {
+	if primes == nil || d.err != nil {
+		return nil
+	}
+	if len(primes) != 2 {
+		d.err = fmt.Errorf("snapshot: paillier: expected 2 primes, got %d", len(primes))
+		return nil
+	}
+	for _, p := range primes {
+		if err := paillier.ValidatePrime(p); err != nil {
+			d.err = fmt.Errorf("snapshot: paillier: %w", err)
+			return nil
+		}
+	}
+	return paillier.NewSecretKeyFromPrimes(primes[0], primes[1])
+}
+
+// PaillierPublic recreates a map of Paillier public keys from their moduli.
+func (d *Decoder) PaillierPublic(m map[party.ID]*saferith.Modulus) map[party.ID]*paillier.PublicKey {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID]*paillier.PublicKey, len(m))
+	for id, n := range m {
+		out[id] = paillier.NewPublicKey(n)
+	}
+	return out
+}
+
+// Pedersen recreates a map of pedersen.Parameters.
+func (d *Decoder) Pedersen(m map[party.ID]*Pedersen) map[party.ID]*pedersen.Parameters {
+	if m == nil {
+		return nil
+	}
+	out := make(map[party.ID]*pedersen.Parameters, len(m))
+	for id, p := range m {
+		out[id] = pedersen.New(arith.ModulusFromN(p.N), p.S, p.T)
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// Curves lists the groups over which the ECDSA protocols are tested.
+var Curves = []curve.Curve{curve.Secp256k1{}, curve.P256{}}
+
+// VerifyStdlibECDSA checks a signature (R, S) against crypto/ecdsa.
+//
+// The second return value is false if the curve of X isn't supported by the standard library.
+func VerifyStdlibECDSA(X, R curve.Point, S curve.Scalar, hash []byte) (bool, bool) {
+	var c elliptic.Curve
+	switch X.Curve().(type) {
+	case curve.P256:
+		c = elliptic.P256()
+	default:
+		return false, false
+	}
+	data, err := X.MarshalBinary()
+	if err != nil {
+		return false, true
+	}
+	x, y := elliptic.UnmarshalCompressed(c, data)
+	if x == nil {
+		return false, true
+	}
+	r, err := R.XScalar().MarshalBinary()
+	if err != nil {
+		return false, true
+	}
+	s, err := S.MarshalBinary()
+	if err != nil {
+		return false, true
+	}
+	pk := &ecdsa.PublicKey{Curve: c, X: x, Y: y}
+	return ecdsa.Verify(pk, hash, new(big.Int).SetBytes(r), new(big.Int).SetBytes(s)), true
+}
@@ -1,27 +1,64 @@
 package test
 
 import (
+	"context"
+
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
 )
 
 // HandlerLoop blocks until the handler has finished. The result of the execution is given by Handler.Result().
+//
+// It runs the handler with transport.Run, over the network.
 func HandlerLoop(id party.ID, h protocol.Handler, network *Network) {
+	_, _ = transport.Run(context.Background(), h, network.transport(id))
+}
+
+// HandlerLoopRestoring is like HandlerLoop, but at the start and after each accepted message, the handler is replaced
+// by one restored from its snapshot, to check that the protocol can be resumed at any point.
+//
+// h must implement Snapshot, and restore should create a new handler from a snapshot.
+// The handler which finished the protocol is returned, or the first error encountered.
+func HandlerLoopRestoring(id party.ID, h protocol.Handler, network *Network, restore func(data []byte) (protocol.Handler, error)) (protocol.Handler, error) {
+	ctx := context.Background()
+	t := network.transport(id)
 	for {
-		select {
+		// forward the messages of the current handler before replacing it
+		if finished, err := forward(ctx, h, t); finished || err != nil {
+			return h, err
+		}
+		data, err := h.(interface{ Snapshot() ([]byte, error) }).Snapshot()
+		if err != nil {
+			return h, err
+		}
+		if h, err = restore(data); err != nil {
+			return nil, err
+		}
+		if finished, err := forward(ctx, h, t); finished || err != nil {
+			return h, err
+		}
+		msg, err := t.Receive(ctx)
+		if err != nil {
+			return h, err
+		}
+		h.Accept(msg)
+	}
+}
 
-		// outgoing messages
+// forward sends the messages h has already produced, and returns true if h has finished.
+func forward(ctx context.Context, h protocol.Handler, t transport.Transport) (bool, error) {
+	for {
+		select {
 		case msg, ok := <-h.Listen():
 			if !ok {
-				<-network.Done(id)
-				// the channel was closed, indicating that the protocol is done executing.
-				return
+				return true, nil
 			}
-			go network.Send(msg)
-
-		// incoming messages
-		case msg := <-network.Next(id):
-			h.Accept(msg)
+			if err := t.Send(ctx, msg); err != nil {
+				return false, err
+			}
+		default:
+			return false, nil
 		}
 	}
 }
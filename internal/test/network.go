@@ -1,80 +1,40 @@
 package test
 
 import (
+	"strconv"
 	"sync"
 
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
-	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
 )
 
-// Network simulates a point-to-point network between different parties using Go channels.
+// Network simulates a point-to-point network between different parties, using a transport.Memory.
 // The same network is used by all processes, and can be reused for different protocols.
-// When used with test.Handler, no interaction from the user is required beyond creating the network.
+// When used with HandlerLoop, no interaction from the user is required beyond creating the network.
+//
+// Each protocol run by a party is a new session of the transport.Memory,
+// so all parties must run the same protocols over the network, in the same order.
 type Network struct {
-	parties          party.IDSlice
-	listenChannels   map[party.ID]chan *protocol.Message
-	done             chan struct{}
-	closedListenChan chan *protocol.Message
-	mtx              sync.Mutex
+	parties party.IDSlice
+	memory  *transport.Memory
+	// sessions is the number of protocols each party has started on the network.
+	sessions map[party.ID]int
+	mtx      sync.Mutex
 }
 
 func NewNetwork(parties party.IDSlice) *Network {
-	closed := make(chan *protocol.Message)
-	close(closed)
-	c := &Network{
-		parties:          parties,
-		listenChannels:   make(map[party.ID]chan *protocol.Message, 2*len(parties)),
-		closedListenChan: closed,
+	return &Network{
+		parties:  parties,
+		memory:   transport.NewMemory(),
+		sessions: make(map[party.ID]int, len(parties)),
 	}
-	return c
 }
 
-func (n *Network) init() {
-	N := len(n.parties)
-	for _, id := range n.parties {
-		n.listenChannels[id] = make(chan *protocol.Message, N*N)
-	}
-	n.done = make(chan struct{})
-}
-
-func (n *Network) Next(id party.ID) <-chan *protocol.Message {
-	n.mtx.Lock()
-	defer n.mtx.Unlock()
-	if len(n.listenChannels) == 0 {
-		n.init()
-	}
-	c, ok := n.listenChannels[id]
-	if !ok {
-		return n.closedListenChan
-	}
-	return c
-}
-
-func (n *Network) Send(msg *protocol.Message) {
-	n.mtx.Lock()
-	defer n.mtx.Unlock()
-	for id, c := range n.listenChannels {
-		if msg.IsFor(id) && c != nil {
-			n.listenChannels[id] <- msg
-		}
-	}
-}
-
-func (n *Network) Done(id party.ID) chan struct{} {
-	n.mtx.Lock()
-	defer n.mtx.Unlock()
-	if _, ok := n.listenChannels[id]; ok {
-		close(n.listenChannels[id])
-		delete(n.listenChannels, id)
-	}
-	if len(n.listenChannels) == 0 {
-		close(n.done)
-	}
-	return n.done
-}
-
-func (n *Network) Quit(id party.ID) {
+// transport returns the Transport used by id for the next protocol it runs on the network.
+func (n *Network) transport(id party.ID) transport.Transport {
 	n.mtx.Lock()
 	defer n.mtx.Unlock()
-	n.parties = n.parties.Remove(id)
+	session := n.sessions[id]
+	n.sessions[id]++
+	return n.memory.Transport(strconv.Itoa(session), id, n.parties)
 }
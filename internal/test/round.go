@@ -154,3 +154,29 @@ func getRound(outerRound round.Session) round.Session {
 func getContent(outerContent round.Content) round.Content {
 	return outerContent
 }
+
+// Restore replaces each round by one restored from its snapshot,
+// using a first round obtained by calling create with the index of the round.
+func Restore(rounds []round.Session, create func(i int) (round.Session, error)) error {
+	for i, r := range rounds {
+		restorer, ok := r.(round.Restorer)
+		if !ok {
+			return round.ErrNoSnapshot
+		}
+		data, err := restorer.Snapshot(r)
+		if err != nil {
+			return err
+		}
+		first, err := create(i)
+		if err != nil {
+			return err
+		}
+		if restorer, ok = first.(round.Restorer); !ok {
+			return round.ErrNoSnapshot
+		}
+		if rounds[i], err = restorer.Restore(r.Number(), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
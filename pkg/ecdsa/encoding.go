@@ -0,0 +1,200 @@
+package ecdsa
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// derSignature is the ASN.1 structure of a DER encoded signature.
+type derSignature struct {
+	R, S *big.Int
+}
+
+// MarshalDER returns the ASN.1 DER encoding of (r, s), as used by Bitcoin and X.509.
+//
+// S is encoded as is, call Normalize first to obtain a low-S signature.
+func (sig Signature) MarshalDER() ([]byte, error) {
+	r, s, err := sig.rs()
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(derSignature{
+		R: new(big.Int).SetBytes(r),
+		S: new(big.Int).SetBytes(s),
+	})
+}
+
+// ParseDER parses a strict ASN.1 DER encoded signature over group.
+//
+// Since the encoding only contains the x coordinate of R, R is taken with an even y coordinate,
+// which doesn't affect Verify, but RecoverPublicKey may then return the wrong key.
+func ParseDER(group curve.Curve, data []byte) (Signature, error) {
+	var der derSignature
+	rest, err := asn1.Unmarshal(data, &der)
+	if err != nil {
+		return Signature{}, fmt.Errorf("signature: invalid DER signature: %w", err)
+	}
+	if len(rest) != 0 {
+		return Signature{}, errors.New("signature: trailing data after DER signature")
+	}
+	size := scalarSize(group)
+	if der.R.Sign() <= 0 || der.S.Sign() <= 0 || der.R.BitLen() > 8*size || der.S.BitLen() > 8*size {
+		return Signature{}, errors.New("signature: DER signature values out of range")
+	}
+	return parseRS(group, der.R.FillBytes(make([]byte, size)), der.S.FillBytes(make([]byte, size)), 0)
+}
+
+// Compact returns the 64 byte encoding r || s, where both are 32 byte big-endian integers.
+//
+// S is encoded as is, call Normalize first to obtain a low-S signature.
+func (sig Signature) Compact() ([]byte, error) {
+	r, s, err := sig.rs()
+	if err != nil {
+		return nil, err
+	}
+	return append(r, s...), nil
+}
+
+// ParseCompact parses the encoding r || s returned by Compact.
+//
+// data may also be followed by a single byte containing the RecoveryID of the signature,
+// in which case R is restored exactly. Otherwise R is taken with an even y coordinate,
+// which doesn't affect Verify, but RecoverPublicKey may then return the wrong key.
+func ParseCompact(group curve.Curve, data []byte) (Signature, error) {
+	size := scalarSize(group)
+	var recoveryID byte
+	switch len(data) {
+	case 2 * size:
+	case 2*size + 1:
+		recoveryID = data[2*size]
+		if recoveryID > 3 {
+			return Signature{}, fmt.Errorf("signature: invalid recovery id %d", recoveryID)
+		}
+	default:
+		return Signature{}, fmt.Errorf("signature: invalid length for compact signature: %d", len(data))
+	}
+	return parseRS(group, data[:size], data[size:2*size], recoveryID)
+}
+
+// Normalize returns a copy of the signature with S in the lower half of the order.
+//
+// When S is negated, R is negated as well, so that the result still verifies, and its RecoveryID is correct.
+func (sig Signature) Normalize() Signature {
+	s := sig.R.Curve().NewScalar().Set(sig.S)
+	if !s.IsOverHalfOrder() {
+		return Signature{R: sig.R, S: s}
+	}
+	return Signature{R: sig.R.Negate(), S: s.Negate()}
+}
+
+// RecoveryID returns the value in [0, 3] needed to recover the public key from (r, s).
+//
+// The lowest bit is the parity of the y coordinate of R,
+// and the second bit is set if the x coordinate of R is larger than the order of the group.
+func (sig Signature) RecoveryID() (byte, error) {
+	if sig.R.IsIdentity() {
+		return 0, errors.New("signature: R is the identity")
+	}
+	R, err := sig.R.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	r, _, err := sig.rs()
+	if err != nil {
+		return 0, err
+	}
+	if len(R) != len(r)+1 {
+		return 0, errors.New("signature: unsupported point encoding")
+	}
+	id := R[0] & 1
+	if !bytes.Equal(R[1:], r) {
+		id |= 2
+	}
+	return id, nil
+}
+
+// RecoverPublicKey returns the public key X for which the signature of hash is valid.
+//
+// It is computed as X = r⁻¹⋅(s⋅R - m⋅G).
+func (sig Signature) RecoverPublicKey(hash []byte) (curve.Point, error) {
+	group := sig.R.Curve()
+
+	r := sig.R.XScalar()
+	if r == nil {
+		return nil, fmt.Errorf("signature: unsupported curve %s", group.Name())
+	}
+	if sig.R.IsIdentity() || r.IsZero() || sig.S.IsZero() {
+		return nil, errors.New("signature: invalid signature")
+	}
+	m := curve.FromHash(group, hash)
+	rInv := group.NewScalar().Set(r).Invert()
+	X := rInv.Act(sig.S.Act(sig.R).Sub(m.ActOnBase()))
+	if X.IsIdentity() {
+		return nil, errors.New("signature: invalid signature")
+	}
+	return X, nil
+}
+
+// rs returns the big-endian encodings of r and s.
+func (sig Signature) rs() ([]byte, []byte, error) {
+	r := sig.R.XScalar()
+	if r == nil {
+		return nil, nil, fmt.Errorf("signature: unsupported curve %s", sig.R.Curve().Name())
+	}
+	if r.IsZero() || sig.S.IsZero() {
+		return nil, nil, errors.New("signature: invalid signature")
+	}
+	rBytes, err := r.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	sBytes, err := sig.S.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	return rBytes, sBytes, nil
+}
+
+// parseRS returns the signature (r, s) whose point R is determined by recoveryID.
+func parseRS(group curve.Curve, r, s []byte, recoveryID byte) (Signature, error) {
+	sig := EmptySignature(group)
+	rScalar := group.NewScalar()
+	if err := rScalar.UnmarshalBinary(r); err != nil {
+		return Signature{}, fmt.Errorf("signature: invalid r: %w", err)
+	}
+	if err := sig.S.UnmarshalBinary(s); err != nil {
+		return Signature{}, fmt.Errorf("signature: invalid s: %w", err)
+	}
+	if rScalar.IsZero() || sig.S.IsZero() {
+		return Signature{}, errors.New("signature: invalid signature")
+	}
+
+	x := r
+	if recoveryID&2 != 0 {
+		// the x coordinate of R is r + q
+		xNat := new(saferith.Nat).SetBytes(r)
+		xNat.Add(xNat, group.Order().Nat(), -1)
+		if xNat.TrueLen() > 8*len(r) {
+			return Signature{}, errors.New("signature: invalid recovery id")
+		}
+		x = xNat.FillBytes(make([]byte, len(r)))
+	}
+	R := make([]byte, 0, len(x)+1)
+	R = append(R, 2|recoveryID&1)
+	R = append(R, x...)
+	if err := sig.R.UnmarshalBinary(R); err != nil {
+		return Signature{}, fmt.Errorf("signature: invalid r: %w", err)
+	}
+	return sig, nil
+}
+
+// scalarSize returns the number of bytes of a scalar of group.
+func scalarSize(group curve.Curve) int {
+	return (group.ScalarBits() + 7) / 8
+}
@@ -0,0 +1,163 @@
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secp256k1ecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+func TestSignature_DER_Secp256k1(t *testing.T) {
+	group := curve.Secp256k1{}
+	hash := sha256.Sum256([]byte("hello"))
+
+	for i := 0; i < 10; i++ {
+		x := sample.Scalar(rand.Reader, group)
+		X := x.ActOnBase()
+		xBytes, err := x.MarshalBinary()
+		require.NoError(t, err)
+		priv := secp256k1.PrivKeyFromBytes(xBytes)
+		XBytes, err := X.MarshalBinary()
+		require.NoError(t, err)
+		pub, err := secp256k1.ParsePubKey(XBytes)
+		require.NoError(t, err)
+
+		sig := NewSignature(x, hash[:], nil).Normalize()
+		der, err := sig.MarshalDER()
+		require.NoError(t, err)
+		parsed, err := secp256k1ecdsa.ParseDERSignature(der)
+		require.NoError(t, err)
+		assert.True(t, parsed.Verify(hash[:], pub), "DER signature should verify with secp256k1")
+
+		der = secp256k1ecdsa.Sign(priv, hash[:]).Serialize()
+		sig2, err := ParseDER(group, der)
+		require.NoError(t, err)
+		assert.True(t, sig2.Verify(X, hash[:]), "parsed DER signature should verify")
+		der2, err := sig2.MarshalDER()
+		require.NoError(t, err)
+		assert.Equal(t, der, der2)
+	}
+}
+
+func TestSignature_DER_P256(t *testing.T) {
+	group := curve.P256{}
+	hash := sha256.Sum256([]byte("hello"))
+
+	for i := 0; i < 10; i++ {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		x := group.NewScalar()
+		require.NoError(t, x.UnmarshalBinary(priv.D.FillBytes(make([]byte, 32))))
+		X := x.ActOnBase()
+		XBytes, err := X.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, elliptic.MarshalCompressed(elliptic.P256(), priv.X, priv.Y), XBytes)
+
+		der, err := NewSignature(x, hash[:], nil).MarshalDER()
+		require.NoError(t, err)
+		assert.True(t, ecdsa.VerifyASN1(&priv.PublicKey, hash[:], der), "DER signature should verify with crypto/ecdsa")
+
+		der, err = ecdsa.SignASN1(rand.Reader, priv, hash[:])
+		require.NoError(t, err)
+		sig, err := ParseDER(group, der)
+		require.NoError(t, err)
+		assert.True(t, sig.Verify(X, hash[:]), "parsed DER signature should verify")
+	}
+}
+
+func TestSignature_ParseDER_Invalid(t *testing.T) {
+	group := curve.Secp256k1{}
+	hash := sha256.Sum256([]byte("hello"))
+	x := sample.Scalar(rand.Reader, group)
+	der, err := NewSignature(x, hash[:], nil).MarshalDER()
+	require.NoError(t, err)
+
+	_, err = ParseDER(group, append(der, 0))
+	assert.Error(t, err, "trailing data should be rejected")
+	_, err = ParseDER(group, der[:len(der)-1])
+	assert.Error(t, err, "truncated signature should be rejected")
+	_, err = ParseDER(group, []byte{0x30, 0x06, 0x02, 0x01, 0x00, 0x02, 0x01, 0x01})
+	assert.Error(t, err, "r = 0 should be rejected")
+	_, err = ParseDER(group, []byte{0x30, 0x07, 0x02, 0x02, 0x00, 0x01, 0x02, 0x01, 0x01})
+	assert.Error(t, err, "non minimal encoding should be rejected")
+}
+
+func TestSignature_Compact(t *testing.T) {
+	group := curve.Secp256k1{}
+	hash := sha256.Sum256([]byte("hello"))
+
+	for i := 0; i < 10; i++ {
+		x := sample.Scalar(rand.Reader, group)
+		X := x.ActOnBase()
+		xBytes, err := x.MarshalBinary()
+		require.NoError(t, err)
+		priv := secp256k1.PrivKeyFromBytes(xBytes)
+
+		sig := NewSignature(x, hash[:], nil).Normalize()
+		compact, err := sig.Compact()
+		require.NoError(t, err)
+		require.Len(t, compact, 64)
+		v, err := sig.RecoveryID()
+		require.NoError(t, err)
+
+		// the compact format of the library is 27 + 4 + v || r || s for compressed keys
+		pub, compressed, err := secp256k1ecdsa.RecoverCompact(append([]byte{27 + 4 + v}, compact...), hash[:])
+		require.NoError(t, err)
+		assert.True(t, compressed)
+		XBytes, err := X.MarshalBinary()
+		require.NoError(t, err)
+		assert.Equal(t, XBytes, pub.SerializeCompressed())
+
+		parsed, err := ParseCompact(group, compact)
+		require.NoError(t, err)
+		assert.True(t, parsed.Verify(X, hash[:]))
+		assert.True(t, parsed.S.Equal(sig.S))
+
+		expected := secp256k1ecdsa.SignCompact(priv, hash[:], true)
+		sig2, err := ParseCompact(group, append(expected[1:], expected[0]-27-4))
+		require.NoError(t, err)
+		assert.True(t, sig2.Verify(X, hash[:]))
+		recovered, err := sig2.RecoverPublicKey(hash[:])
+		require.NoError(t, err)
+		assert.True(t, recovered.Equal(X), "recovered wrong public key")
+		v, err = sig2.RecoveryID()
+		require.NoError(t, err)
+		assert.Equal(t, expected[0]-27-4, v)
+	}
+
+	_, err := ParseCompact(group, make([]byte, 63))
+	assert.Error(t, err, "wrong length should be rejected")
+	_, err = ParseCompact(group, make([]byte, 64))
+	assert.Error(t, err, "zero signature should be rejected")
+}
+
+func TestSignature_Normalize(t *testing.T) {
+	group := curve.Secp256k1{}
+	hash := sha256.Sum256([]byte("hello"))
+
+	for i := 0; i < 10; i++ {
+		x := sample.Scalar(rand.Reader, group)
+		X := x.ActOnBase()
+		sig := NewSignature(x, hash[:], nil)
+		s := group.NewScalar().Set(sig.S)
+
+		normalized := sig.Normalize()
+		assert.True(t, sig.S.Equal(s), "Normalize should not modify the signature")
+		assert.False(t, normalized.S.IsOverHalfOrder())
+		assert.True(t, normalized.Verify(X, hash[:]))
+
+		for _, sig := range []Signature{*sig, normalized} {
+			recovered, err := sig.RecoverPublicKey(hash[:])
+			require.NoError(t, err)
+			assert.True(t, recovered.Equal(X), "recovered wrong public key")
+		}
+	}
+}
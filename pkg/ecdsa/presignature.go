@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
@@ -30,6 +31,9 @@ func (sig *PreSignature) Group() curve.Curve {
 }
 
 // EmptyPreSignature returns a PreSignature with a given group, ready for unmarshalling.
+//
+// This is only needed for encodings without a curve name, otherwise UnmarshalPreSignature
+// finds the group by itself.
 func EmptyPreSignature(group curve.Curve) *PreSignature {
 	return &PreSignature{
 		R:        group.NewPoint(),
@@ -40,6 +44,84 @@ func EmptyPreSignature(group curve.Curve) *PreSignature {
 	}
 }
 
+// UnmarshalPreSignature decodes a PreSignature produced by PreSignature.MarshalBinary,
+// using the curve name embedded in the data to find the group.
+func UnmarshalPreSignature(data []byte) (*PreSignature, error) {
+	sig := new(PreSignature)
+	if err := sig.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+type preSignatureMarshal struct {
+	// Group is the name of the curve, see curve.ByName.
+	Group            string
+	ID               types.RID
+	R                curve.Point
+	RBar, S          *party.PointMap
+	KShare, ChiShare curve.Scalar
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, including the name of the curve.
+func (sig *PreSignature) MarshalBinary() ([]byte, error) {
+	return cbor.Marshal(&preSignatureMarshal{
+		Group:    sig.Group().Name(),
+		ID:       sig.ID,
+		R:        sig.R,
+		RBar:     sig.RBar,
+		S:        sig.S,
+		KShare:   sig.KShare,
+		ChiShare: sig.ChiShare,
+	})
+}
+
+// UnmarshalBinary decodes a PreSignature, using the curve name embedded in the data.
+//
+// Data produced without a curve name can still be decoded, if the group was set using EmptyPreSignature.
+func (sig *PreSignature) UnmarshalBinary(data []byte) error {
+	var header struct{ Group string }
+	if err := cbor.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("presignature: %w", err)
+	}
+	var group curve.Curve
+	if sig.R != nil {
+		group = sig.R.Curve()
+	}
+	if header.Group != "" {
+		named, err := curve.ByName(header.Group)
+		if err != nil {
+			return fmt.Errorf("presignature: %w", err)
+		}
+		if group != nil && group.Name() != named.Name() {
+			return fmt.Errorf("presignature: expected curve %s, found %s", group.Name(), named.Name())
+		}
+		group = named
+	}
+	if group == nil {
+		return errors.New("presignature: must be initialized using EmptyPreSignature")
+	}
+	sm := &preSignatureMarshal{
+		R:        group.NewPoint(),
+		RBar:     party.EmptyPointMap(group),
+		S:        party.EmptyPointMap(group),
+		KShare:   group.NewScalar(),
+		ChiShare: group.NewScalar(),
+	}
+	if err := cbor.Unmarshal(data, sm); err != nil {
+		return fmt.Errorf("presignature: %w", err)
+	}
+	*sig = PreSignature{
+		ID:       sm.ID,
+		R:        sm.R,
+		RBar:     sm.RBar,
+		S:        sm.S,
+		KShare:   sm.KShare,
+		ChiShare: sm.ChiShare,
+	}
+	return nil
+}
+
 // SignatureShare represents an individual additive share of the signature's "s" component.
 type SignatureShare = curve.Scalar
 
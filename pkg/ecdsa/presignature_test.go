@@ -5,6 +5,8 @@ import (
 	mrand "math/rand"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/taurusgroup/multi-party-sig/internal/test"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -108,3 +110,27 @@ func TestPreSignature_Fail(t *testing.T) {
 		}
 	}
 }
+
+func TestPreSignature_Marshal(t *testing.T) {
+	message := []byte("HELLO WORLD")
+	for _, group := range test.Curves {
+		t.Run(group.Name(), func(t *testing.T) {
+			_, X, preSignatures := NewPreSignatures(group, 3)
+			sigmaShares := make(map[party.ID]SignatureShare, len(preSignatures))
+			unmarshalled := make(map[party.ID]*PreSignature, len(preSignatures))
+			for id, preSignature := range preSignatures {
+				data, err := preSignature.MarshalBinary()
+				require.NoError(t, err)
+				sig, err := UnmarshalPreSignature(data)
+				require.NoError(t, err)
+				require.Equal(t, group.Name(), sig.Group().Name())
+				require.NoError(t, EmptyPreSignature(group).UnmarshalBinary(data))
+				sigmaShares[id] = sig.SignatureShare(message)
+				unmarshalled[id] = sig
+			}
+			for _, sig := range unmarshalled {
+				assert.True(t, sig.Signature(sigmaShares).Verify(X, message))
+			}
+		})
+	}
+}
@@ -15,11 +15,14 @@ func EmptySignature(group curve.Curve) Signature {
 }
 
 // Verify is a custom signature format using curve data.
+//
+// Only the x coordinate of R is checked, as in standard ECDSA,
+// so that signatures parsed without the parity of R also verify.
 func (sig Signature) Verify(X curve.Point, hash []byte) bool {
 	group := X.Curve()
 
 	r := sig.R.XScalar()
-	if r.IsZero() || sig.S.IsZero() {
+	if r == nil || r.IsZero() || sig.S.IsZero() {
 		return false
 	}
 
@@ -29,7 +32,7 @@ func (sig Signature) Verify(X curve.Point, hash []byte) bool {
 	rX := r.Act(X)
 	R2 := mG.Add(rX)
 	R2 = sInv.Act(R2)
-	return R2.Equal(sig.R)
+	return !R2.IsIdentity() && R2.XScalar().Equal(r)
 }
 
 // get a signature in ethereum format
@@ -2,7 +2,6 @@ package hash
 
 import (
 	"bytes"
-	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
@@ -67,13 +66,13 @@ func (d Decommitment) Validate() error {
 	return errors.New("decommitment: decommitment is 0")
 }
 
-// Commit creates a commitment to data, and returns a commitment hash, and a decommitment string such that
+// Commit creates a commitment to data, and returns a commitment hash, and a decommitment string read from rand such that
 // commitment = h(data, decommitment).
-func (hash *Hash) Commit(data ...interface{}) (Commitment, Decommitment, error) {
+func (hash *Hash) Commit(rand io.Reader, data ...interface{}) (Commitment, Decommitment, error) {
 	var err error
 	decommitment := Decommitment(make([]byte, params.SecBytes))
 
-	if _, err = rand.Read(decommitment); err != nil {
+	if _, err = io.ReadFull(rand, decommitment); err != nil {
 		return nil, nil, fmt.Errorf("hash.Commit: failed to generate decommitment: %w", err)
 	}
 
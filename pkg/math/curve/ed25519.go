@@ -0,0 +1,275 @@
+package curve
+
+import (
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	"github.com/cronokirby/saferith"
+)
+
+// Ed25519 is the prime order subgroup of the twisted Edwards curve equivalent to Curve25519.
+//
+// Scalars follow the convention of the Scalar interface, and are encoded as Big Endian bytes.
+// Points use the canonical 32 byte encoding from RFC 8032.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc8032
+type Ed25519 struct{}
+
+func (Ed25519) NewPoint() Point {
+	out := new(Ed25519Point)
+	out.value.Set(edwards25519.NewIdentityPoint())
+	return out
+}
+
+func (Ed25519) NewBasePoint() Point {
+	out := new(Ed25519Point)
+	out.value.Set(edwards25519.NewGeneratorPoint())
+	return out
+}
+
+func (Ed25519) NewScalar() Scalar {
+	return new(Ed25519Scalar)
+}
+
+func (Ed25519) ScalarBits() int {
+	return 253
+}
+
+func (Ed25519) SafeScalarBytes() int {
+	return 64
+}
+
+var ed25519OrderNat, _ = new(saferith.Nat).SetHex("1000000000000000000000000000000014DEF9DEA2F79CD65812631A5CF5D3ED")
+var ed25519Order = saferith.ModulusFromNat(ed25519OrderNat)
+var ed25519HalfOrderNat = new(saferith.Nat).Rsh(ed25519OrderNat, 1, -1)
+var ed25519One, _ = edwards25519.NewScalar().SetCanonicalBytes([]byte{
+	1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+})
+
+func (Ed25519) Order() *saferith.Modulus {
+	return ed25519Order
+}
+
+func (Ed25519) Name() string {
+	return "ed25519"
+}
+
+type Ed25519Scalar struct {
+	value edwards25519.Scalar
+}
+
+func ed25519CastScalar(generic Scalar) *Ed25519Scalar {
+	out, ok := generic.(*Ed25519Scalar)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to ed25519Scalar: %v", generic))
+	}
+	return out
+}
+
+// reverse returns a reversed copy of data, converting between Big and Little Endian.
+func reverse(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[len(data)-1-i] = b
+	}
+	return out
+}
+
+func (*Ed25519Scalar) Curve() Curve {
+	return Ed25519{}
+}
+
+func (s *Ed25519Scalar) MarshalBinary() ([]byte, error) {
+	return reverse(s.value.Bytes()), nil
+}
+
+func (s *Ed25519Scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != 32 {
+		return fmt.Errorf("invalid length for ed25519 scalar: %d", len(data))
+	}
+	if _, err := s.value.SetCanonicalBytes(reverse(data)); err != nil {
+		return errors.New("invalid bytes for ed25519 scalar")
+	}
+	return nil
+}
+
+// BytesLE returns the Little Endian encoding of this scalar, as used in RFC 8032.
+func (s *Ed25519Scalar) BytesLE() []byte {
+	return s.value.Bytes()
+}
+
+// SetBytesWide sets this scalar to a 64 byte Little Endian value, reduced modulo the group order.
+//
+// This is how RFC 8032 converts the output of SHA-512 into a scalar.
+func (s *Ed25519Scalar) SetBytesWide(data []byte) (*Ed25519Scalar, error) {
+	if _, err := s.value.SetUniformBytes(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Ed25519Scalar) Add(that Scalar) Scalar {
+	other := ed25519CastScalar(that)
+
+	s.value.Add(&s.value, &other.value)
+	return s
+}
+
+func (s *Ed25519Scalar) Sub(that Scalar) Scalar {
+	other := ed25519CastScalar(that)
+
+	s.value.Subtract(&s.value, &other.value)
+	return s
+}
+
+func (s *Ed25519Scalar) Mul(that Scalar) Scalar {
+	other := ed25519CastScalar(that)
+
+	s.value.Multiply(&s.value, &other.value)
+	return s
+}
+
+func (s *Ed25519Scalar) Invert() Scalar {
+	s.value.Invert(&s.value)
+	return s
+}
+
+func (s *Ed25519Scalar) Negate() Scalar {
+	s.value.Negate(&s.value)
+	return s
+}
+
+func (s *Ed25519Scalar) IsOverHalfOrder() bool {
+	gt, _, _ := new(saferith.Nat).SetBytes(reverse(s.value.Bytes())).Cmp(ed25519HalfOrderNat)
+	return gt == 1
+}
+
+func (s *Ed25519Scalar) Equal(that Scalar) bool {
+	other := ed25519CastScalar(that)
+
+	return s.value.Equal(&other.value) == 1
+}
+
+func (s *Ed25519Scalar) IsZero() bool {
+	return s.value.Equal(edwards25519.NewScalar()) == 1
+}
+
+func (s *Ed25519Scalar) Set(that Scalar) Scalar {
+	other := ed25519CastScalar(that)
+
+	s.value.Set(&other.value)
+	return s
+}
+
+func (s *Ed25519Scalar) SetNat(x *saferith.Nat) Scalar {
+	var data [32]byte
+	new(saferith.Nat).Mod(x, ed25519Order).FillBytes(data[:])
+	if _, err := s.value.SetCanonicalBytes(reverse(data[:])); err != nil {
+		panic(fmt.Sprintf("ed25519Scalar.SetNat: %v", err))
+	}
+	return s
+}
+
+func (s *Ed25519Scalar) Act(that Point) Point {
+	other := ed25519CastPoint(that)
+	out := new(Ed25519Point)
+	out.value.ScalarMult(&s.value, &other.value)
+	return out
+}
+
+func (s *Ed25519Scalar) ActOnBase() Point {
+	out := new(Ed25519Point)
+	out.value.ScalarBaseMult(&s.value)
+	return out
+}
+
+type Ed25519Point struct {
+	value edwards25519.Point
+}
+
+func ed25519CastPoint(generic Point) *Ed25519Point {
+	out, ok := generic.(*Ed25519Point)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to ed25519Point: %v", generic))
+	}
+	return out
+}
+
+func (*Ed25519Point) Curve() Curve {
+	return Ed25519{}
+}
+
+func (p *Ed25519Point) MarshalBinary() ([]byte, error) {
+	return p.value.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a point, making sure that it lies in the prime order subgroup.
+//
+// Points with a small order component are rejected, since they would break
+// the assumptions made by the protocols using this group.
+func (p *Ed25519Point) UnmarshalBinary(data []byte) error {
+	if len(data) != 32 {
+		return fmt.Errorf("invalid length for ed25519Point: %d", len(data))
+	}
+	var decoded edwards25519.Point
+	if _, err := decoded.SetBytes(data); err != nil {
+		return fmt.Errorf("ed25519Point.UnmarshalBinary: %w", err)
+	}
+	// (l - 1)⋅P + P = l⋅P, which is the identity only for points of order l.
+	minusOne := edwards25519.NewScalar().Subtract(edwards25519.NewScalar(), ed25519One)
+	check := new(edwards25519.Point).ScalarMult(minusOne, &decoded)
+	check.Add(check, &decoded)
+	if check.Equal(edwards25519.NewIdentityPoint()) != 1 {
+		return errors.New("ed25519Point.UnmarshalBinary: point is not in the prime order subgroup")
+	}
+	p.value.Set(&decoded)
+	return nil
+}
+
+func (p *Ed25519Point) Add(that Point) Point {
+	other := ed25519CastPoint(that)
+
+	out := new(Ed25519Point)
+	out.value.Add(&p.value, &other.value)
+	return out
+}
+
+func (p *Ed25519Point) Sub(that Point) Point {
+	other := ed25519CastPoint(that)
+
+	out := new(Ed25519Point)
+	out.value.Subtract(&p.value, &other.value)
+	return out
+}
+
+func (p *Ed25519Point) Set(that Point) Point {
+	other := ed25519CastPoint(that)
+
+	p.value.Set(&other.value)
+	return p
+}
+
+func (p *Ed25519Point) Negate() Point {
+	out := new(Ed25519Point)
+	out.value.Negate(&p.value)
+	return out
+}
+
+func (p *Ed25519Point) Equal(that Point) bool {
+	other := ed25519CastPoint(that)
+
+	return p.value.Equal(&other.value) == 1
+}
+
+func (p *Ed25519Point) IsIdentity() bool {
+	return p == nil || p.value.Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+// XScalar implements Point.
+//
+// ECDSA isn't defined over this curve, so this always returns nil.
+func (p *Ed25519Point) XScalar() Scalar {
+	return nil
+}
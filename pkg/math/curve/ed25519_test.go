@@ -0,0 +1,91 @@
+package curve
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/cronokirby/saferith"
+)
+
+func TestEd25519PublicKey(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	expected := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+
+	// See: https://datatracker.ietf.org/doc/html/rfc8032#section-5.1.5
+	h := sha512.Sum512(seed)
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+	secret := Ed25519{}.NewScalar().SetNat(new(saferith.Nat).SetBytes(reverse(h[:32])))
+
+	actual, err := secret.ActOnBase().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("public key mismatch: expected %x, got %x", expected, actual)
+	}
+
+	point := Ed25519{}.NewPoint()
+	if err = point.UnmarshalBinary(expected); err != nil {
+		t.Fatal(err)
+	}
+	if !point.Equal(secret.ActOnBase()) {
+		t.Error("unmarshalled point is different")
+	}
+}
+
+func TestEd25519ScalarEncoding(t *testing.T) {
+	group := Ed25519{}
+	// order - 1 is the largest canonical scalar
+	minusOne := group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1)).Negate()
+	data, err := minusOne.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := new(saferith.Nat).Sub(group.Order().Nat(), new(saferith.Nat).SetUint64(1), 256).FillBytes(make([]byte, 32))
+	if !bytes.Equal(data, expected) {
+		t.Errorf("expected big endian encoding of order - 1, got %x", data)
+	}
+	decoded := group.NewScalar()
+	if err = decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(minusOne) {
+		t.Error("decoded scalar is different")
+	}
+	if !minusOne.IsOverHalfOrder() {
+		t.Error("order - 1 should be over half the order")
+	}
+
+	// the order itself is not a canonical encoding
+	if err = decoded.UnmarshalBinary(group.Order().Bytes()); err == nil {
+		t.Error("non canonical scalar should be rejected")
+	}
+}
+
+func TestEd25519SmallOrder(t *testing.T) {
+	group := Ed25519{}
+	// (0, -1) has order 2.
+	smallOrder := []byte{
+		0xec, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f,
+	}
+	if err := group.NewPoint().UnmarshalBinary(smallOrder); err == nil {
+		t.Error("point of small order should be rejected")
+	}
+
+	identity, _ := group.NewPoint().MarshalBinary()
+	point := group.NewBasePoint()
+	if err := point.UnmarshalBinary(identity); err != nil {
+		t.Fatal(err)
+	}
+	if !point.IsIdentity() {
+		t.Error("expected identity")
+	}
+}
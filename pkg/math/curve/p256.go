@@ -0,0 +1,256 @@
+package curve
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	"filippo.io/nistec"
+	"github.com/cronokirby/saferith"
+)
+
+// P256 is the NIST P-256 curve, also known as secp256r1 or prime256v1.
+//
+// Points use the compressed SEC 1 encoding, with the identity encoded as a single 0 byte.
+type P256 struct{}
+
+func (P256) NewPoint() Point {
+	return &P256Point{value: nistec.NewP256Point()}
+}
+
+func (P256) NewBasePoint() Point {
+	return &P256Point{value: nistec.NewP256Point().SetGenerator()}
+}
+
+func (P256) NewScalar() Scalar {
+	return new(P256Scalar)
+}
+
+func (P256) ScalarBits() int {
+	return 256
+}
+
+// SafeScalarBytes is twice the size of a scalar, since the order of P-256 is not close enough
+// to 2²⁵⁶ for 32 bytes to be reduced without bias.
+func (P256) SafeScalarBytes() int {
+	return 64
+}
+
+var p256OrderNat, _ = new(saferith.Nat).SetHex("FFFFFFFF00000000FFFFFFFFFFFFFFFFBCE6FAADA7179E84F3B9CAC2FC632551")
+var p256Order = saferith.ModulusFromNat(p256OrderNat)
+var p256HalfOrderNat = new(saferith.Nat).Rsh(p256OrderNat, 1, -1)
+var p256FieldNat, _ = new(saferith.Nat).SetHex("FFFFFFFF00000001000000000000000000000000FFFFFFFFFFFFFFFFFFFFFFFF")
+var p256Field = saferith.ModulusFromNat(p256FieldNat)
+
+func (P256) Order() *saferith.Modulus {
+	return p256Order
+}
+
+func (P256) Name() string {
+	return "P-256"
+}
+
+type P256Scalar struct {
+	value saferith.Nat
+}
+
+func p256CastScalar(generic Scalar) *P256Scalar {
+	out, ok := generic.(*P256Scalar)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to p256Scalar: %v", generic))
+	}
+	return out
+}
+
+func (*P256Scalar) Curve() Curve {
+	return P256{}
+}
+
+func (s *P256Scalar) MarshalBinary() ([]byte, error) {
+	return s.value.FillBytes(make([]byte, 32)), nil
+}
+
+func (s *P256Scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != 32 {
+		return fmt.Errorf("invalid length for p256 scalar: %d", len(data))
+	}
+	var value saferith.Nat
+	value.SetBytes(data)
+	if _, _, lt := value.CmpMod(p256Order); lt != 1 {
+		return errors.New("invalid bytes for p256 scalar")
+	}
+	s.value.SetNat(&value).Resize(p256Order.BitLen())
+	return nil
+}
+
+func (s *P256Scalar) Add(that Scalar) Scalar {
+	other := p256CastScalar(that)
+
+	s.value.ModAdd(&s.value, &other.value, p256Order)
+	return s
+}
+
+func (s *P256Scalar) Sub(that Scalar) Scalar {
+	other := p256CastScalar(that)
+
+	s.value.ModSub(&s.value, &other.value, p256Order)
+	return s
+}
+
+func (s *P256Scalar) Mul(that Scalar) Scalar {
+	other := p256CastScalar(that)
+
+	s.value.ModMul(&s.value, &other.value, p256Order)
+	return s
+}
+
+func (s *P256Scalar) Invert() Scalar {
+	s.value.ModInverse(&s.value, p256Order)
+	return s
+}
+
+func (s *P256Scalar) Negate() Scalar {
+	s.value.ModNeg(&s.value, p256Order)
+	return s
+}
+
+func (s *P256Scalar) IsOverHalfOrder() bool {
+	gt, _, _ := s.value.Cmp(p256HalfOrderNat)
+	return gt == 1
+}
+
+func (s *P256Scalar) Equal(that Scalar) bool {
+	other := p256CastScalar(that)
+
+	return s.value.Eq(&other.value) == 1
+}
+
+func (s *P256Scalar) IsZero() bool {
+	return s.value.EqZero() == 1
+}
+
+func (s *P256Scalar) Set(that Scalar) Scalar {
+	other := p256CastScalar(that)
+
+	s.value.SetNat(&other.value)
+	return s
+}
+
+func (s *P256Scalar) SetNat(x *saferith.Nat) Scalar {
+	s.value.Mod(x, p256Order)
+	return s
+}
+
+func (s *P256Scalar) Act(that Point) Point {
+	other := p256CastPoint(that)
+	data, _ := s.MarshalBinary()
+	out := nistec.NewP256Point()
+	if _, err := out.ScalarMult(other.value, data); err != nil {
+		panic(fmt.Sprintf("p256Scalar.Act: %v", err))
+	}
+	return &P256Point{value: out}
+}
+
+func (s *P256Scalar) ActOnBase() Point {
+	data, _ := s.MarshalBinary()
+	out := nistec.NewP256Point()
+	if _, err := out.ScalarBaseMult(data); err != nil {
+		panic(fmt.Sprintf("p256Scalar.ActOnBase: %v", err))
+	}
+	return &P256Point{value: out}
+}
+
+type P256Point struct {
+	value *nistec.P256Point
+}
+
+func p256CastPoint(generic Point) *P256Point {
+	out, ok := generic.(*P256Point)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to p256Point: %v", generic))
+	}
+	return out
+}
+
+func (*P256Point) Curve() Curve {
+	return P256{}
+}
+
+func (p *P256Point) MarshalBinary() ([]byte, error) {
+	return p.value.BytesCompressed(), nil
+}
+
+func (p *P256Point) UnmarshalBinary(data []byte) error {
+	if len(data) != 33 && !(len(data) == 1 && data[0] == 0) {
+		return fmt.Errorf("invalid length for p256Point: %d", len(data))
+	}
+	value, err := nistec.NewP256Point().SetBytes(data)
+	if err != nil {
+		return fmt.Errorf("p256Point.UnmarshalBinary: %w", err)
+	}
+	p.value = value
+	return nil
+}
+
+// XBytes returns the 32 byte x coordinate of this point.
+//
+// This will return nil for the identity.
+func (p *P256Point) XBytes() []byte {
+	x, err := p.value.BytesX()
+	if err != nil {
+		return nil
+	}
+	return x
+}
+
+func (p *P256Point) Add(that Point) Point {
+	other := p256CastPoint(that)
+
+	return &P256Point{value: nistec.NewP256Point().Add(p.value, other.value)}
+}
+
+func (p *P256Point) Sub(that Point) Point {
+	return p.Add(that.Negate())
+}
+
+func (p *P256Point) Set(that Point) Point {
+	other := p256CastPoint(that)
+
+	p.value = nistec.NewP256Point().Set(other.value)
+	return p
+}
+
+func (p *P256Point) Negate() Point {
+	data := p.value.Bytes()
+	if len(data) == 1 {
+		return &P256Point{value: nistec.NewP256Point()}
+	}
+	// data is 0x04 || x || y, and -(x, y) = (x, -y).
+	y := new(saferith.Nat).SetBytes(data[33:])
+	y.ModNeg(y, p256Field).FillBytes(data[33:])
+	value, err := nistec.NewP256Point().SetBytes(data)
+	if err != nil {
+		panic(fmt.Sprintf("p256Point.Negate: %v", err))
+	}
+	return &P256Point{value: value}
+}
+
+func (p *P256Point) Equal(that Point) bool {
+	other := p256CastPoint(that)
+
+	return subtle.ConstantTimeCompare(p.value.Bytes(), other.value.Bytes()) == 1
+}
+
+func (p *P256Point) IsIdentity() bool {
+	return p == nil || p.value == nil || len(p.value.Bytes()) == 1
+}
+
+func (p *P256Point) XScalar() Scalar {
+	out := new(P256Scalar)
+	x, err := p.value.BytesX()
+	if err != nil {
+		return out
+	}
+	out.SetNat(new(saferith.Nat).SetBytes(x))
+	return out
+}
@@ -0,0 +1,96 @@
+package curve
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"testing"
+
+	"github.com/cronokirby/saferith"
+)
+
+func TestP256BasePoint(t *testing.T) {
+	group := P256{}
+	k := []byte("a fixed scalar for P-256 tests!!")
+	expectedX, expectedY := elliptic.P256().ScalarBaseMult(k)
+	expected := elliptic.MarshalCompressed(elliptic.P256(), expectedX, expectedY)
+
+	secret := group.NewScalar().SetNat(new(saferith.Nat).SetBytes(k))
+	actual, err := secret.ActOnBase().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("public key mismatch: expected %x, got %x", expected, actual)
+	}
+	if !secret.Act(group.NewBasePoint()).Equal(secret.ActOnBase()) {
+		t.Error("Act and ActOnBase differ")
+	}
+
+	point := group.NewPoint()
+	if err = point.UnmarshalBinary(expected); err != nil {
+		t.Fatal(err)
+	}
+	if !point.Equal(secret.ActOnBase()) {
+		t.Error("unmarshalled point is different")
+	}
+
+	x := group.NewScalar().SetNat(new(saferith.Nat).SetBig(expectedX, 256))
+	if !point.XScalar().Equal(x) {
+		t.Error("XScalar is different")
+	}
+}
+
+func TestP256Arithmetic(t *testing.T) {
+	group := P256{}
+	two := group.NewScalar().SetNat(new(saferith.Nat).SetUint64(2))
+	G := group.NewBasePoint()
+	if !G.Add(G).Equal(two.ActOnBase()) {
+		t.Error("G + G != 2⋅G")
+	}
+	if !G.Add(G.Negate()).IsIdentity() {
+		t.Error("G - G should be the identity")
+	}
+	if !G.Sub(G).IsIdentity() {
+		t.Error("G - G should be the identity")
+	}
+
+	identity, err := group.NewPoint().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	point := group.NewBasePoint()
+	if err = point.UnmarshalBinary(identity); err != nil {
+		t.Fatal(err)
+	}
+	if !point.IsIdentity() {
+		t.Error("expected identity")
+	}
+
+	minusOne := group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1)).Negate()
+	if !minusOne.IsOverHalfOrder() {
+		t.Error("order - 1 should be over half the order")
+	}
+	if two.IsOverHalfOrder() {
+		t.Error("2 should not be over half the order")
+	}
+	data, err := minusOne.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := group.NewScalar()
+	if err = decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(minusOne) {
+		t.Error("decoded scalar is different")
+	}
+	if !decoded.Mul(minusOne).Equal(group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1))) {
+		t.Error("(-1)⋅(-1) should be 1")
+	}
+	if !two.Invert().Mul(group.NewScalar().SetNat(new(saferith.Nat).SetUint64(2))).Equal(group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1))) {
+		t.Error("2⁻¹⋅2 should be 1")
+	}
+	if err = decoded.UnmarshalBinary(group.Order().Bytes()); err == nil {
+		t.Error("non canonical scalar should be rejected")
+	}
+}
@@ -0,0 +1,47 @@
+package curve
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Curve)
+)
+
+func init() {
+	Register(Secp256k1{})
+	Register(P256{})
+	Register(Ed25519{})
+}
+
+// Register makes a curve available through ByName, using its Name.
+//
+// The curves defined in this package are registered automatically.
+// This will panic if a different curve was already registered with the same name.
+func Register(group Curve) {
+	if group == nil {
+		panic("curve.Register: nil curve")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	name := group.Name()
+	if existing, ok := registry[name]; ok && existing != group {
+		panic(fmt.Sprintf("curve.Register: curve %q registered twice", name))
+	}
+	registry[name] = group
+}
+
+// ByName returns the curve registered with a given name.
+//
+// This allows decoding data containing points and scalars, without knowing the curve in advance.
+func ByName(name string) (Curve, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	group, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("curve: unknown curve %q", name)
+	}
+	return group, nil
+}
@@ -0,0 +1,20 @@
+package curve
+
+import "testing"
+
+func TestByName(t *testing.T) {
+	for _, group := range []Curve{Secp256k1{}, P256{}, Ed25519{}} {
+		found, err := ByName(group.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found != group {
+			t.Errorf("expected %s, found %s", group.Name(), found.Name())
+		}
+	}
+	if _, err := ByName("unknown"); err == nil {
+		t.Error("expected an error for an unknown curve")
+	}
+	// registering the same curve again is allowed
+	Register(Secp256k1{})
+}
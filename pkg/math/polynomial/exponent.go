@@ -179,6 +179,11 @@ func (*Exponent) Domain() string {
 	return "Exponent"
 }
 
+// ZeroExponent returns the polynomial F(X) = 0, of degree 0.
+func ZeroExponent(group curve.Curve) *Exponent {
+	return &Exponent{group: group, IsConstant: true}
+}
+
 func EmptyExponent(group curve.Curve) *Exponent {
 	// TODO create custom marshaller
 	return &Exponent{group: group}
@@ -22,7 +22,7 @@ func TestExponent_Evaluate(t *testing.T) {
 		if x%2 == 0 {
 			secret = sample.Scalar(rand.Reader, group)
 		}
-		poly := NewPolynomial(group, N, secret)
+		poly := NewPolynomial(rand.Reader, group, N, secret)
 		polyExp := NewPolynomialExponent(poly)
 
 		randomIndex := sample.Scalar(rand.Reader, group)
@@ -55,7 +55,7 @@ func TestSum(t *testing.T) {
 	polysExp := make([]*Exponent, N)
 	for i := range polys {
 		sec := sample.Scalar(rand.Reader, group)
-		polys[i] = NewPolynomial(group, Deg, sec)
+		polys[i] = NewPolynomial(rand.Reader, group, Deg, sec)
 		polysExp[i] = NewPolynomialExponent(polys[i])
 
 		evaluationScalar.Add(polys[i].Evaluate(randomIndex))
@@ -75,7 +75,7 @@ func TestMarshall(t *testing.T) {
 	group := curve.Secp256k1{}
 
 	sec := sample.Scalar(rand.Reader, group)
-	poly := NewPolynomial(group, 10, sec)
+	poly := NewPolynomial(rand.Reader, group, 10, sec)
 	polyExp := NewPolynomialExponent(poly)
 	out, err := cbor.Marshal(polyExp)
 	require.NoError(t, err, "failed to Marshal")
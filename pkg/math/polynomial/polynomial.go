@@ -1,8 +1,10 @@
 package polynomial
 
 import (
-	"crypto/rand"
+	"errors"
+	"io"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 )
@@ -14,8 +16,8 @@ type Polynomial struct {
 }
 
 // NewPolynomial generates a Polynomial f(X) = secret + a₁⋅X + … + aₜ⋅Xᵗ,
-// with coefficients in ℤₚ, and degree t.
-func NewPolynomial(group curve.Curve, degree int, constant curve.Scalar) *Polynomial {
+// with coefficients in ℤₚ sampled from rand, and degree t.
+func NewPolynomial(rand io.Reader, group curve.Curve, degree int, constant curve.Scalar) *Polynomial {
 	polynomial := &Polynomial{
 		group:        group,
 		coefficients: make([]curve.Scalar, degree+1),
@@ -28,7 +30,7 @@ func NewPolynomial(group curve.Curve, degree int, constant curve.Scalar) *Polyno
 	polynomial.coefficients[0] = constant
 
 	for i := 1; i <= degree; i++ {
-		polynomial.coefficients[i] = sample.Scalar(rand.Reader, group)
+		polynomial.coefficients[i] = sample.Scalar(rand, group)
 	}
 
 	return polynomial
@@ -59,3 +61,36 @@ func (p *Polynomial) Constant() curve.Scalar {
 func (p *Polynomial) Degree() uint32 {
 	return uint32(len(p.coefficients)) - 1
 }
+
+// EmptyPolynomial creates an empty Polynomial with a fixed group, ready for unmarshalling.
+func EmptyPolynomial(group curve.Curve) *Polynomial {
+	return &Polynomial{group: group}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (p *Polynomial) MarshalBinary() ([]byte, error) {
+	return cbor.Marshal(p.coefficients)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (p *Polynomial) UnmarshalBinary(data []byte) error {
+	if p == nil || p.group == nil {
+		return errors.New("can't unmarshal Polynomial with no group")
+	}
+	var rawCoefficients []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &rawCoefficients); err != nil {
+		return err
+	}
+	if len(rawCoefficients) == 0 {
+		return errors.New("polynomial has no coefficients")
+	}
+	coefficients := make([]curve.Scalar, len(rawCoefficients))
+	for i, raw := range rawCoefficients {
+		coefficients[i] = p.group.NewScalar()
+		if err := cbor.Unmarshal(raw, coefficients[i]); err != nil {
+			return err
+		}
+	}
+	p.coefficients = coefficients
+	return nil
+}
@@ -0,0 +1,211 @@
+// Package observer provides implementations of protocol.Observer,
+// which log the progress of protocol executions, or collect metrics about them.
+package observer
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the buckets of the round duration histogram,
+// which are the same as those of the Prometheus client.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+var _ protocol.Observer = (*Metrics)(nil)
+var _ http.Handler = (*Metrics)(nil)
+
+// Metrics is a protocol.Observer which counts the events of all the handlers it is given to,
+// and exposes them in the Prometheus text format, without depending on a Prometheus client.
+//
+// The following metrics are collected, where namespace is given to NewMetrics:
+//   - namespace_round_duration_seconds{protocol, round}: histogram of the duration of each round.
+//   - namespace_messages_sent_total{protocol, round}: counter of the messages sent.
+//   - namespace_messages_received_total{protocol, round}: counter of the messages received.
+//   - namespace_messages_rejected_total{protocol, reason}: counter of the messages ignored by the handlers.
+//   - namespace_aborts_total{protocol}: counter of the failed executions.
+//
+// Metrics can be scraped by a Prometheus server through ServeHTTP, or written to any io.Writer with WriteTo.
+type Metrics struct {
+	namespace string
+	buckets   []float64
+
+	mtx       sync.Mutex
+	durations map[labels]*histogram
+	sent      map[labels]uint64
+	received  map[labels]uint64
+	rejected  map[labels]uint64
+	aborts    map[labels]uint64
+}
+
+// labels identifies a metric in a family, by the values of its labels in the order of the family's names.
+type labels [2]string
+
+// histogram accumulates observations in cumulative buckets.
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewMetrics returns a Metrics whose names are prefixed by namespace, if it isn't empty.
+// The round durations are counted in buckets with the given finite upper bounds in seconds, or DefaultBuckets if none are given.
+func NewMetrics(namespace string, buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Metrics{
+		namespace: namespace,
+		buckets:   buckets,
+		durations: map[labels]*histogram{},
+		sent:      map[labels]uint64{},
+		received:  map[labels]uint64{},
+		rejected:  map[labels]uint64{},
+		aborts:    map[labels]uint64{},
+	}
+}
+
+// RoundStarted implements protocol.Observer.
+func (m *Metrics) RoundStarted(protocol.Execution, protocol.RoundNumber) {}
+
+// RoundFinished implements protocol.Observer.
+func (m *Metrics) RoundFinished(e protocol.Execution, number protocol.RoundNumber, d time.Duration) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	l := labels{e.Protocol, formatRound(number)}
+	h := m.durations[l]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.durations[l] = h
+	}
+	seconds := d.Seconds()
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// MessageSent implements protocol.Observer.
+func (m *Metrics) MessageSent(e protocol.Execution, msg *protocol.Message) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.sent[labels{e.Protocol, formatRound(msg.RoundNumber)}]++
+}
+
+// MessageReceived implements protocol.Observer.
+func (m *Metrics) MessageReceived(e protocol.Execution, msg *protocol.Message) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.received[labels{e.Protocol, formatRound(msg.RoundNumber)}]++
+}
+
+// MessageRejected implements protocol.Observer.
+func (m *Metrics) MessageRejected(e protocol.Execution, _ *protocol.Message, reason error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.rejected[labels{e.Protocol, reason.Error()}]++
+}
+
+// Aborted implements protocol.Observer.
+func (m *Metrics) Aborted(e protocol.Execution, _ error, _ []party.ID) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.aborts[labels{e.Protocol}]++
+}
+
+// WriteTo writes all metrics to w in the Prometheus text format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	var b strings.Builder
+	m.writeHistogram(&b, "round_duration_seconds", "Duration of the rounds of a protocol.", []string{"protocol", "round"}, m.durations)
+	m.writeCounter(&b, "messages_sent_total", "Messages sent by the handlers.", []string{"protocol", "round"}, m.sent)
+	m.writeCounter(&b, "messages_received_total", "Messages received by the handlers.", []string{"protocol", "round"}, m.received)
+	m.writeCounter(&b, "messages_rejected_total", "Messages ignored by the handlers.", []string{"protocol", "reason"}, m.rejected)
+	m.writeCounter(&b, "aborts_total", "Protocol executions which failed.", []string{"protocol"}, m.aborts)
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP implements http.Handler, so that the metrics can be scraped by a Prometheus server.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+func (m *Metrics) name(name string) string {
+	if m.namespace == "" {
+		return name
+	}
+	return m.namespace + "_" + name
+}
+
+func (m *Metrics) writeCounter(b *strings.Builder, name, help string, labelNames []string, values map[labels]uint64) {
+	name = m.name(name)
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, l := range sortedLabels(values) {
+		fmt.Fprintf(b, "%s{%s} %d\n", name, formatLabels(labelNames, l), values[l])
+	}
+}
+
+func (m *Metrics) writeHistogram(b *strings.Builder, name, help string, labelNames []string, values map[labels]*histogram) {
+	name = m.name(name)
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for _, l := range sortedLabels(values) {
+		h := values[l]
+		ls := formatLabels(labelNames, l)
+		for i, bound := range m.buckets {
+			fmt.Fprintf(b, "%s_bucket{%s,le=\"%s\"} %d\n", name, ls, formatFloat(bound), h.counts[i])
+		}
+		fmt.Fprintf(b, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, ls, h.count)
+		fmt.Fprintf(b, "%s_sum{%s} %s\n", name, ls, formatFloat(h.sum))
+		fmt.Fprintf(b, "%s_count{%s} %d\n", name, ls, h.count)
+	}
+}
+
+// sortedLabels returns the keys of values in a deterministic order.
+func sortedLabels[V any](values map[labels]V) []labels {
+	keys := make([]labels, 0, len(values))
+	for l := range values {
+		keys = append(keys, l)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+// labelEscaper escapes label values as required by the Prometheus text format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(names []string, l labels) string {
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=\"" + labelEscaper.Replace(l[i]) + "\""
+	}
+	return strings.Join(pairs, ",")
+}
+
+func formatRound(number protocol.RoundNumber) string {
+	return strconv.FormatUint(uint64(number), 10)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,85 @@
+package observer_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/observer"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+// keygen runs FROST keygen between partyIDs with o, and returns the handlers once they are done.
+func keygen(t *testing.T, partyIDs party.IDSlice, o protocol.Observer) map[party.ID]protocol.Handler {
+	handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(frost.Keygen(curve.Secp256k1{}, id, partyIDs, 1), nil, protocol.WithObserver(o))
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	done := make(chan struct{})
+	for _, h := range handlers {
+		go func(h protocol.Handler) {
+			for msg := range h.Listen() {
+				for id, other := range handlers {
+					if msg.IsFor(id) {
+						other.Accept(msg)
+					}
+				}
+			}
+			done <- struct{}{}
+		}(h)
+	}
+	for range handlers {
+		<-done
+	}
+	return handlers
+}
+
+func TestMetrics(t *testing.T) {
+	m := observer.NewMetrics("mpc", 1, 0.5)
+	handlers := keygen(t, party.IDSlice{"a", "b", "c"}, m)
+	for _, h := range handlers {
+		_, err := h.Result()
+		require.NoError(t, err)
+	}
+
+	// a failed execution, and a message it rejects
+	h, err := protocol.NewMultiHandler(frost.Keygen(curve.Secp256k1{}, "a", party.IDSlice{"a", "b"}, 1), nil, protocol.WithObserver(m))
+	require.NoError(t, err)
+	h.Stop()
+	h.Accept(&protocol.Message{From: "b", Protocol: "frost/keygen-threshold", RoundNumber: 2, Data: []byte{0}})
+
+	var b strings.Builder
+	_, err = m.WriteTo(&b)
+	require.NoError(t, err)
+	out := b.String()
+	for _, line := range []string{
+		"# TYPE mpc_round_duration_seconds histogram",
+		// the failed execution also finished its first round, and sent its broadcast and abort messages
+		`mpc_round_duration_seconds_bucket{protocol="frost/keygen-threshold",round="1",le="+Inf"} 4`,
+		`mpc_round_duration_seconds_count{protocol="frost/keygen-threshold",round="3"} 3`,
+		"# TYPE mpc_messages_sent_total counter",
+		`mpc_messages_sent_total{protocol="frost/keygen-threshold",round="0"} 1`,
+		`mpc_messages_sent_total{protocol="frost/keygen-threshold",round="2"} 4`,
+		`mpc_messages_sent_total{protocol="frost/keygen-threshold",round="3"} 9`,
+		`mpc_messages_received_total{protocol="frost/keygen-threshold",round="2"} 6`,
+		`mpc_messages_received_total{protocol="frost/keygen-threshold",round="3"} 12`,
+		`mpc_messages_rejected_total{protocol="frost/keygen-threshold",reason="message is for another session"} 1`,
+		`mpc_aborts_total{protocol="frost/keygen-threshold"} 1`,
+	} {
+		assert.Contains(t, out, line+"\n")
+	}
+	// buckets are sorted
+	assert.Less(t, strings.Index(out, `le="0.5"`), strings.Index(out, `le="1"`))
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, out, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+}
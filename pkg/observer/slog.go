@@ -0,0 +1,79 @@
+package observer
+
+import (
+	"context"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+var _ protocol.Observer = (*Logger)(nil)
+
+// Logger is a protocol.Observer which writes every event to a *slog.Logger.
+//
+// Rounds and messages are logged at the Debug level, rejected messages at the Warn level,
+// and aborts at the Error level.
+// Every record has the attributes protocol, self and ssid identifying the execution.
+type Logger struct {
+	l *slog.Logger
+}
+
+// NewLogger returns a Logger writing to l, or to slog.Default() if l is nil.
+func NewLogger(l *slog.Logger) *Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return &Logger{l: l}
+}
+
+// RoundStarted implements protocol.Observer.
+func (o *Logger) RoundStarted(e protocol.Execution, number protocol.RoundNumber) {
+	o.log(slog.LevelDebug, e, "round started", slog.Int("round", int(number)))
+}
+
+// RoundFinished implements protocol.Observer.
+func (o *Logger) RoundFinished(e protocol.Execution, number protocol.RoundNumber, d time.Duration) {
+	o.log(slog.LevelDebug, e, "round finished", slog.Int("round", int(number)), slog.Duration("duration", d))
+}
+
+// MessageSent implements protocol.Observer.
+func (o *Logger) MessageSent(e protocol.Execution, msg *protocol.Message) {
+	o.log(slog.LevelDebug, e, "message sent", messageAttrs(msg)...)
+}
+
+// MessageReceived implements protocol.Observer.
+func (o *Logger) MessageReceived(e protocol.Execution, msg *protocol.Message) {
+	o.log(slog.LevelDebug, e, "message received", messageAttrs(msg)...)
+}
+
+// MessageRejected implements protocol.Observer.
+func (o *Logger) MessageRejected(e protocol.Execution, msg *protocol.Message, reason error) {
+	o.log(slog.LevelWarn, e, "message rejected", append(messageAttrs(msg), slog.String("reason", reason.Error()))...)
+}
+
+// Aborted implements protocol.Observer.
+func (o *Logger) Aborted(e protocol.Execution, err error, culprits []party.ID) {
+	o.log(slog.LevelError, e, "protocol aborted", slog.String("error", err.Error()), slog.Any("culprits", culprits))
+}
+
+func (o *Logger) log(level slog.Level, e protocol.Execution, msg string, attrs ...slog.Attr) {
+	attrs = append([]slog.Attr{
+		slog.String("protocol", e.Protocol),
+		slog.String("self", string(e.SelfID)),
+		slog.String("ssid", hex.EncodeToString(e.SSID)),
+	}, attrs...)
+	o.l.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+func messageAttrs(msg *protocol.Message) []slog.Attr {
+	return []slog.Attr{
+		slog.Int("round", int(msg.RoundNumber)),
+		slog.String("from", string(msg.From)),
+		slog.String("to", string(msg.To)),
+		slog.Bool("broadcast", msg.Broadcast),
+		slog.Bool("echo", msg.Echo),
+	}
+}
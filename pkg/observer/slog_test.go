@@ -0,0 +1,50 @@
+package observer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/observer"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	o := observer.NewLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	handlers := keygen(t, party.IDSlice{"a", "b"}, o)
+	for _, h := range handlers {
+		_, err := h.Result()
+		require.NoError(t, err)
+	}
+	h, err := protocol.NewMultiHandler(frost.Keygen(curve.Secp256k1{}, "a", party.IDSlice{"a", "b"}, 1), nil, protocol.WithObserver(o))
+	require.NoError(t, err)
+	h.Stop()
+
+	counts := map[string]int{}
+	var last map[string]interface{}
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var record map[string]interface{}
+		require.NoError(t, dec.Decode(&record))
+		assert.Equal(t, "frost/keygen-threshold", record["protocol"])
+		counts[record["msg"].(string)]++
+		last = record
+	}
+	// the stopped handler finished its first round, and sent its broadcast and abort messages
+	assert.Equal(t, 2*3+2, counts["round started"])
+	assert.Equal(t, 2*3+1, counts["round finished"])
+	assert.Equal(t, counts["message received"]+2, counts["message sent"])
+	assert.Equal(t, 1, counts["protocol aborted"])
+
+	assert.Equal(t, "ERROR", last["level"])
+	assert.Equal(t, "a", last["self"])
+	assert.NotEmpty(t, last["ssid"])
+	assert.Equal(t, []interface{}{"a"}, last["culprits"])
+}
@@ -1,9 +1,9 @@
 package paillier
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
@@ -55,17 +55,18 @@ func (sk *SecretKey) Phi() *saferith.Nat {
 	return sk.phi
 }
 
-// KeyGen generates a new PublicKey and it's associated SecretKey.
-func KeyGen(pl *pool.Pool) (pk *PublicKey, sk *SecretKey) {
-	sk = NewSecretKey(pl)
+// KeyGen generates a new PublicKey and it's associated SecretKey, using randomness from rand.
+func KeyGen(rand io.Reader, pl *pool.Pool) (pk *PublicKey, sk *SecretKey) {
+	sk = NewSecretKey(rand, pl)
 	pk = sk.PublicKey
 	return
 }
 
 // NewSecretKey generates primes p and q suitable for the scheme, and returns the initialized SecretKey.
-func NewSecretKey(pl *pool.Pool) *SecretKey {
-	// TODO maybe we could take the reader as argument?
-	return NewSecretKeyFromPrimes(sample.Paillier(rand.Reader, pl))
+//
+// The primes found depend on the scheduling of the workers of pl, so the result is only determined by rand if pl is nil.
+func NewSecretKey(rand io.Reader, pl *pool.Pool) *SecretKey {
+	return NewSecretKeyFromPrimes(sample.Paillier(rand, pl))
 }
 
 // NewSecretKeyFromPrimes generates a new SecretKey. Assumes that P and Q are prime.
@@ -148,8 +149,9 @@ func (sk *SecretKey) DecWithRandomness(ct *Ciphertext) (*saferith.Int, *saferith
 	return m, r, nil
 }
 
-func (sk SecretKey) GeneratePedersen() (*pedersen.Parameters, *saferith.Nat) {
-	s, t, lambda := sample.Pedersen(rand.Reader, sk.phi, sk.n.Modulus)
+// GeneratePedersen samples Pedersen parameters for the modulus of sk from rand, and returns them with their secret λ.
+func (sk SecretKey) GeneratePedersen(rand io.Reader) (*pedersen.Parameters, *saferith.Nat) {
+	s, t, lambda := sample.Pedersen(rand, sk.phi, sk.n.Modulus)
 	ped := pedersen.New(sk.n, s, t)
 	return ped, lambda
 }
@@ -135,7 +135,9 @@ func (p *Pool) Search(count int, f func() interface{}) []interface{} {
 	results := make([]interface{}, count)
 
 	ctr := int64(count)
-	ctrChanged := make(chan struct{})
+	// Every success sends a signal, and each worker can overshoot by one success.
+	// Buffering these signals lets workers finish even after we've stopped listening.
+	ctrChanged := make(chan struct{}, count+p.workerCount)
 	cmd := command{
 		search:     true,
 		ctr:        &ctr,
@@ -169,7 +171,9 @@ func (p *Pool) Parallelize(count int, f func(int) interface{}) []interface{} {
 	results := make([]interface{}, count)
 
 	ctr := int64(count)
-	ctrChanged := make(chan struct{})
+	// A worker decrements ctr before signalling, so we might return before the
+	// last signal is received; buffering avoids blocking that worker forever.
+	ctrChanged := make(chan struct{}, count)
 	cmdI := 0
 	for cmdI < count {
 		cmd := command{
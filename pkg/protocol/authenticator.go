@@ -0,0 +1,104 @@
+package protocol
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// ErrInvalidSignature is the reason given to Observer.MessageRejected when a message's signature is rejected by the Authenticator.
+var ErrInvalidSignature = errors.New("invalid message signature")
+
+// Authenticator signs the messages sent by a handler, and verifies those it receives.
+//
+// Signatures are computed over Message.Hash(), which covers the headers and the content of the message.
+type Authenticator interface {
+	// Sign returns a signature of digest by this party.
+	Sign(digest []byte) ([]byte, error)
+	// Verify returns an error if sig is not a valid signature of digest by the party from.
+	Verify(from party.ID, digest, sig []byte) error
+}
+
+// WithAuthenticator signs all outgoing messages with a, and requires all incoming messages to be signed by their sender.
+//
+// A message whose signature does not verify proves nothing against its claimed sender, since anyone could have sent it.
+// It is dropped and reported to Observer.MessageRejected, and the handler keeps waiting for the genuine message.
+// The Authenticator of all parties must agree on the public keys of each other.
+func WithAuthenticator(a Authenticator) Option {
+	return func(o *options) {
+		o.auth = a
+	}
+}
+
+// Ed25519Authenticator is an Authenticator using Ed25519 signatures,
+// where each party is identified by a long term public key.
+type Ed25519Authenticator struct {
+	secret ed25519.PrivateKey
+	public map[party.ID]ed25519.PublicKey
+}
+
+// NewEd25519Authenticator returns an Authenticator which signs with secret,
+// and verifies the messages of each party against its key in public.
+func NewEd25519Authenticator(secret ed25519.PrivateKey, public map[party.ID]ed25519.PublicKey) (*Ed25519Authenticator, error) {
+	if len(secret) != ed25519.PrivateKeySize {
+		return nil, errors.New("ed25519 authenticator: invalid secret key")
+	}
+	publicCopy := make(map[party.ID]ed25519.PublicKey, len(public))
+	for id, key := range public {
+		if len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("ed25519 authenticator: invalid public key for %s", id)
+		}
+		publicCopy[id] = key
+	}
+	return &Ed25519Authenticator{
+		secret: secret,
+		public: publicCopy,
+	}, nil
+}
+
+// Sign implements Authenticator.
+func (a *Ed25519Authenticator) Sign(digest []byte) ([]byte, error) {
+	return ed25519.Sign(a.secret, digest), nil
+}
+
+// Verify implements Authenticator.
+func (a *Ed25519Authenticator) Verify(from party.ID, digest, sig []byte) error {
+	key, ok := a.public[from]
+	if !ok {
+		return fmt.Errorf("ed25519 authenticator: unknown party %s", from)
+	}
+	if !ed25519.Verify(key, digest, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// sign sets the signature of msg, if the handler has an Authenticator.
+func sign(auth Authenticator, msg *Message) error {
+	if auth == nil {
+		return nil
+	}
+	sig, err := auth.Sign(msg.Hash())
+	if err != nil {
+		return fmt.Errorf("failed to sign message: %w", err)
+	}
+	msg.Signature = sig
+	return nil
+}
+
+// authenticate returns an error wrapping ErrInvalidSignature if the signature of msg by its sender is invalid,
+// or nil if the handler has no Authenticator.
+func authenticate(auth Authenticator, msg *Message) error {
+	if auth == nil {
+		return nil
+	}
+	if err := auth.Verify(msg.From, msg.Hash(), msg.Signature); err != nil {
+		if errors.Is(err, ErrInvalidSignature) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	return nil
+}
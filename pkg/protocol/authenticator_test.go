@@ -0,0 +1,145 @@
+package protocol_test
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/doerner"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+// authenticators creates an Ed25519Authenticator for each party, sharing the same public keys.
+func authenticators(t *testing.T, partyIDs party.IDSlice) map[party.ID]*protocol.Ed25519Authenticator {
+	secrets := make(map[party.ID]ed25519.PrivateKey, len(partyIDs))
+	public := make(map[party.ID]ed25519.PublicKey, len(partyIDs))
+	for _, id := range partyIDs {
+		pk, sk, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		secrets[id], public[id] = sk, pk
+	}
+	auths := make(map[party.ID]*protocol.Ed25519Authenticator, len(partyIDs))
+	for _, id := range partyIDs {
+		a, err := protocol.NewEd25519Authenticator(secrets[id], public)
+		require.NoError(t, err)
+		auths[id] = a
+	}
+	return auths
+}
+
+func TestMultiHandlerAuthenticator(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+	auths := authenticators(t, partyIDs)
+
+	handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, partyIDs, 1), nil, protocol.WithAuthenticator(auths[id]))
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	relay(handlers)
+
+	for _, h := range handlers {
+		_, err := h.Result()
+		require.NoError(t, err)
+	}
+}
+
+func TestMultiHandlerAuthenticatorReject(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+	auths := authenticators(t, partyIDs)
+	// b signs with a key unknown to a
+	impostor := authenticators(t, partyIDs)
+
+	for _, test := range []struct {
+		name  string
+		opts  []protocol.Option
+		valid bool
+	}{
+		{"valid", []protocol.Option{protocol.WithAuthenticator(auths["b"])}, true},
+		{"wrong key", []protocol.Option{protocol.WithAuthenticator(impostor["b"])}, false},
+		{"unsigned", nil, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			observer := &recorder{}
+			a, err := protocol.NewMultiHandler(frost.Keygen(group, "a", partyIDs, 1), nil, protocol.WithAuthenticator(auths["a"]), protocol.WithObserver(observer))
+			require.NoError(t, err)
+			b, err := protocol.NewMultiHandler(frost.Keygen(group, "b", partyIDs, 1), nil, test.opts...)
+			require.NoError(t, err)
+
+			msg := <-b.Listen()
+			require.True(t, a.CanAccept(msg))
+			a.Accept(msg)
+
+			// a is never aborted, since an invalid signature doesn't prove that b sent the message
+			_, err = a.Result()
+			require.Error(t, err)
+			var protocolErr protocol.Error
+			assert.False(t, errors.As(err, &protocolErr), "a should still be waiting")
+			if test.valid {
+				assert.Equal(t, 1, observer.received)
+				assert.Empty(t, observer.rejected)
+				return
+			}
+			assert.Zero(t, observer.received)
+			require.Len(t, observer.rejected, 1)
+			assert.ErrorIs(t, observer.rejected[0], protocol.ErrInvalidSignature)
+
+			// the genuine message of b is still accepted afterwards
+			genuine, err := protocol.NewMultiHandler(frost.Keygen(group, "b", partyIDs, 1), nil, protocol.WithAuthenticator(auths["b"]))
+			require.NoError(t, err)
+			a.Accept(<-genuine.Listen())
+			assert.Equal(t, 1, observer.received)
+			assert.Len(t, observer.rejected, 1)
+		})
+	}
+}
+
+func TestTwoPartyHandlerAuthenticator(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b"}
+	auths := authenticators(t, partyIDs)
+	impostor := authenticators(t, partyIDs)
+
+	// honest parties
+	receiver, err := protocol.NewTwoPartyHandler(doerner.Keygen(group, true, "a", "b", pl), nil, true, protocol.WithAuthenticator(auths["a"]))
+	require.NoError(t, err)
+	sender, err := protocol.NewTwoPartyHandler(doerner.Keygen(group, false, "b", "a", pl), nil, false, protocol.WithAuthenticator(auths["b"]))
+	require.NoError(t, err)
+	relay(map[party.ID]protocol.Handler{"a": receiver, "b": sender})
+	_, err = receiver.Result()
+	require.NoError(t, err)
+	_, err = sender.Result()
+	require.NoError(t, err)
+
+	// the receiver's first message is signed with the wrong key, and then by the actual receiver
+	observer := &recorder{}
+	impostorReceiver, err := protocol.NewTwoPartyHandler(doerner.Keygen(group, true, "a", "b", pl), nil, true, protocol.WithAuthenticator(impostor["a"]))
+	require.NoError(t, err)
+	receiver, err = protocol.NewTwoPartyHandler(doerner.Keygen(group, true, "a", "b", pl), nil, true, protocol.WithAuthenticator(auths["a"]))
+	require.NoError(t, err)
+	sender, err = protocol.NewTwoPartyHandler(doerner.Keygen(group, false, "b", "a", pl), nil, false, protocol.WithAuthenticator(auths["b"]), protocol.WithObserver(observer))
+	require.NoError(t, err)
+	sender.Accept(<-impostorReceiver.Listen())
+	require.Len(t, observer.rejected, 1)
+	assert.ErrorIs(t, observer.rejected[0], protocol.ErrInvalidSignature)
+
+	sender.Accept(<-receiver.Listen())
+	relay(map[party.ID]protocol.Handler{"a": receiver, "b": sender})
+	_, err = receiver.Result()
+	require.NoError(t, err)
+	_, err = sender.Result()
+	require.NoError(t, err)
+	assert.Empty(t, observer.culprits)
+}
@@ -0,0 +1,185 @@
+package protocol
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// WithEchoBroadcast removes the need for a reliable broadcast channel, by adding an echo sub-round to every broadcast round
+// of a MultiHandler, as described in docs/Broadcast.md.
+//
+// Once a party has received the broadcast messages of all parties for a round, it sends the hash of each of them to all.
+// The round is only finalized once the echoes of all other parties match the messages we received,
+// otherwise the protocol aborts with ErrEquivocation.
+// This includes the final round, for which there is no next round to carry the BroadcastVerification.
+//
+// With an Authenticator, the echoes also contain the messages themselves and their signatures,
+// so that the party which broadcast different messages can be identified.
+// Without one, the culprits are both the sender of the message and the party whose echo disagrees with ours.
+//
+// All parties must use this option, since the echo messages are not understood otherwise.
+func WithEchoBroadcast() Option {
+	return func(o *options) {
+		o.echoBroadcast = true
+	}
+}
+
+// echoContent is the content of an echo message, with the broadcast messages received by a party in a round.
+type echoContent struct {
+	// Hashes[j] is Message.Hash() of the broadcast message of j.
+	Hashes map[party.ID][]byte
+	// Messages[j] is the broadcast message of j, if messages are authenticated.
+	Messages map[party.ID]*echoedMessage
+}
+
+// echoedMessage contains the parts of a broadcast message which aren't implied by the round it was received in,
+// so that its hash can be recomputed, and its signature checked.
+type echoedMessage struct {
+	Data                  []byte
+	BroadcastVerification []byte
+	Signature             []byte
+}
+
+// receivedEchoes is run after receivedAll() when using WithEchoBroadcast.
+// In a broadcast round, it sends our echo, and returns true once the echoes of all other parties were received,
+// and agree with the broadcast messages we received. If they don't, the protocol is aborted.
+func (h *MultiHandler) receivedEchoes() bool {
+	r := h.currentRound
+	number := r.Number()
+	if _, ok := r.(round.BroadcastRound); !ok || h.broadcast[number] == nil {
+		return true
+	}
+
+	ours := echoContent{
+		Hashes: make(map[party.ID][]byte, r.N()),
+	}
+	if h.auth != nil {
+		ours.Messages = make(map[party.ID]*echoedMessage, r.N())
+	}
+	for _, id := range r.PartyIDs() {
+		msg := h.broadcast[number][id]
+		ours.Hashes[id] = msg.Hash()
+		if h.auth != nil {
+			ours.Messages[id] = &echoedMessage{
+				Data:                  msg.Data,
+				BroadcastVerification: msg.BroadcastVerification,
+				Signature:             msg.Signature,
+			}
+		}
+	}
+
+	if !h.echoSent[number] {
+		if err := h.sendEcho(&ours); err != nil {
+			h.abort(err, r.SelfID())
+			return false
+		}
+		h.echoSent[number] = true
+	}
+
+	for _, id := range r.OtherPartyIDs() {
+		if h.echoes[number][id] == nil {
+			return false
+		}
+	}
+	for _, id := range r.OtherPartyIDs() {
+		if culprits, err := h.checkEcho(&ours, h.echoes[number][id]); err != nil {
+			h.abort(err, culprits...)
+			return false
+		}
+	}
+	return true
+}
+
+// sendEcho sends ours to all other parties.
+func (h *MultiHandler) sendEcho(ours *echoContent) error {
+	r := h.currentRound
+	data, err := cbor.Marshal(ours)
+	if err != nil {
+		return fmt.Errorf("failed to marshal echo: %w", err)
+	}
+	msg := &Message{
+		SSID:        r.SSID(),
+		From:        r.SelfID(),
+		Protocol:    r.ProtocolID(),
+		RoundNumber: r.Number(),
+		Data:        data,
+		Echo:        true,
+	}
+	if err = sign(h.auth, msg); err != nil {
+		return err
+	}
+	h.out <- msg
+	h.observer.MessageSent(execution(r), msg)
+	return nil
+}
+
+// checkEcho compares the echo msg of another party to ours, and returns the culprits and an error if they differ.
+//
+// With an Authenticator, the hashes are recomputed from the echoed messages, as the broadcast messages of this round,
+// so that a signature of any other message cannot be passed off as a second broadcast message.
+func (h *MultiHandler) checkEcho(ours *echoContent, msg *Message) ([]party.ID, error) {
+	r := h.currentRound
+	var theirs echoContent
+	if err := cbor.Unmarshal(msg.Data, &theirs); err != nil {
+		return []party.ID{msg.From}, fmt.Errorf("round %d: failed to unmarshal echo: %w: %w", msg.RoundNumber, ErrMalformed, err)
+	}
+	for _, id := range r.PartyIDs() {
+		hash := theirs.Hashes[id]
+		var signature []byte
+		if h.auth != nil {
+			echoed := theirs.Messages[id]
+			if echoed == nil {
+				return []party.ID{msg.From}, fmt.Errorf("round %d: echo of %s is missing the message of %s: %w", msg.RoundNumber, msg.From, id, ErrMalformed)
+			}
+			broadcast := &Message{
+				SSID:                  r.SSID(),
+				From:                  id,
+				Protocol:              r.ProtocolID(),
+				RoundNumber:           msg.RoundNumber,
+				Data:                  echoed.Data,
+				Broadcast:             true,
+				BroadcastVerification: echoed.BroadcastVerification,
+			}
+			hash, signature = broadcast.Hash(), echoed.Signature
+		}
+		if bytes.Equal(ours.Hashes[id], hash) {
+			continue
+		}
+		err := fmt.Errorf("round %d: broadcast message of %s differs for %s: %w", msg.RoundNumber, id, msg.From, ErrEquivocation)
+		switch {
+		// the party reporting its own message differently sent different messages
+		case id == msg.From:
+			return []party.ID{msg.From}, err
+		// a valid signature of another broadcast message for this round proves that id signed two different ones
+		case h.auth != nil && h.auth.Verify(id, hash, signature) == nil:
+			return []party.ID{id}, err
+		// the echo is wrong
+		case h.auth != nil:
+			return []party.ID{msg.From}, err
+		// without signatures, either could be lying
+		default:
+			return []party.ID{id, msg.From}, err
+		}
+	}
+	return nil, nil
+}
+
+// missingEchoes returns the parties whose echoes for the current round have not been received yet.
+func (h *MultiHandler) missingEchoes() []party.ID {
+	r := h.currentRound
+	number := r.Number()
+	if _, ok := r.(round.BroadcastRound); !ok || !h.echoSent[number] {
+		return nil
+	}
+	var missing []party.ID
+	for _, id := range r.OtherPartyIDs() {
+		if h.echoes[number][id] == nil {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
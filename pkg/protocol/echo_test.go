@@ -0,0 +1,221 @@
+package protocol_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func TestEchoBroadcast(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+	signers := partyIDs[:2]
+	message := []byte("hello")
+	auths := authenticators(t, partyIDs)
+
+	for _, authenticated := range []bool{false, true} {
+		opts := func(id party.ID) []protocol.Option {
+			if authenticated {
+				return []protocol.Option{protocol.WithEchoBroadcast(), protocol.WithAuthenticator(auths[id])}
+			}
+			return []protocol.Option{protocol.WithEchoBroadcast()}
+		}
+
+		handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+		for _, id := range partyIDs {
+			h, err := protocol.NewMultiHandler(frost.Keygen(group, id, partyIDs, 1), nil, opts(id)...)
+			require.NoError(t, err)
+			handlers[id] = h
+		}
+		relay(handlers)
+
+		configs := make(map[party.ID]*frost.Config, len(partyIDs))
+		for id, h := range handlers {
+			r, err := h.Result()
+			require.NoError(t, err)
+			configs[id] = r.(*frost.Config)
+		}
+
+		handlers = make(map[party.ID]protocol.Handler, len(signers))
+		for _, id := range signers {
+			h, err := protocol.NewMultiHandler(frost.Sign(configs[id], signers, message), nil, opts(id)...)
+			require.NoError(t, err)
+			handlers[id] = h
+		}
+		relay(handlers)
+
+		for id, h := range handlers {
+			r, err := h.Result()
+			require.NoError(t, err)
+			assert.True(t, r.(frost.Signature).Verify(configs[id].PublicKey, message))
+		}
+	}
+}
+
+func TestEchoBroadcastEquivocation(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+	auths := authenticators(t, partyIDs)
+
+	for _, test := range []struct {
+		name          string
+		authenticated bool
+		culprits      []party.ID
+	}{
+		{"authenticated", true, []party.ID{"c"}},
+		{"unauthenticated", false, nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			newHandler := func(id party.ID) protocol.Handler {
+				opts := []protocol.Option{protocol.WithEchoBroadcast()}
+				if test.authenticated {
+					opts = append(opts, protocol.WithAuthenticator(auths[id]))
+				}
+				h, err := protocol.NewMultiHandler(frost.Keygen(group, id, partyIDs, 1), nil, opts...)
+				require.NoError(t, err)
+				return h
+			}
+			// c runs two executions, and only talks to a with the first one, and to b with the second one,
+			// so that a and b receive different broadcast messages from c.
+			a, b, c1, c2 := newHandler("a"), newHandler("b"), newHandler("c"), newHandler("c")
+			routes := map[protocol.Handler][]protocol.Handler{
+				a:  {b, c1, c2},
+				b:  {a, c1, c2},
+				c1: {a},
+				c2: {b},
+			}
+
+			var wg sync.WaitGroup
+			for h, recipients := range routes {
+				wg.Add(1)
+				go func(h protocol.Handler, recipients []protocol.Handler) {
+					defer wg.Done()
+					for msg := range h.Listen() {
+						// c does not reveal that it detected the equivocation of b
+						if msg.From == "c" && msg.RoundNumber == 0 {
+							continue
+						}
+						for _, other := range recipients {
+							other.Accept(msg)
+						}
+					}
+				}(h, recipients)
+			}
+			wg.Wait()
+
+			// the first honest party to detect the equivocation alerts the other one, which may then abort before its own check
+			detected := 0
+			for _, h := range []protocol.Handler{a, b} {
+				_, err := h.Result()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), protocol.ErrEquivocation.Error())
+				if !errors.Is(err, protocol.ErrEquivocation) {
+					continue
+				}
+				var protocolErr protocol.Error
+				require.True(t, errors.As(err, &protocolErr))
+				culprits := protocolErr.Culprits
+				// the party which was alerted reports the culprits blamed by the other one
+				var abortErr *protocol.AbortError
+				if errors.As(err, &abortErr) {
+					culprits = abortErr.Culprits
+				} else {
+					detected++
+				}
+				if test.authenticated {
+					assert.Equal(t, test.culprits, culprits)
+				} else {
+					assert.Contains(t, culprits, party.ID("c"))
+				}
+			}
+			assert.NotZero(t, detected)
+		})
+	}
+}
+
+// echoedContent mirrors the content of echo messages, so that a test can tamper with them.
+type echoedContent struct {
+	Hashes   map[party.ID][]byte
+	Messages map[party.ID]*struct {
+		Data                  []byte
+		BroadcastVerification []byte
+		Signature             []byte
+	}
+}
+
+func TestEchoBroadcastReplayedSignature(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+	auths := authenticators(t, partyIDs)
+
+	handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, partyIDs, 1), nil, protocol.WithEchoBroadcast(), protocol.WithAuthenticator(auths[id]))
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+
+	// b echoes the point-to-point message a sent it in round 3, which a validly signed, instead of a's broadcast message
+	var mtx sync.Mutex
+	var direct *protocol.Message
+	tamper := func(msg *protocol.Message) *protocol.Message {
+		mtx.Lock()
+		defer mtx.Unlock()
+		if msg.From == "a" && msg.To == "b" && msg.RoundNumber == 3 {
+			direct = msg
+		}
+		if msg.From != "b" || !msg.Echo || msg.RoundNumber != 3 {
+			return msg
+		}
+		var content echoedContent
+		if !assert.NotNil(t, direct) || !assert.NoError(t, cbor.Unmarshal(msg.Data, &content)) {
+			return msg
+		}
+		content.Hashes["a"] = direct.Hash()
+		content.Messages["a"].Data = direct.Data
+		content.Messages["a"].BroadcastVerification = direct.BroadcastVerification
+		content.Messages["a"].Signature = direct.Signature
+		tampered := *msg
+		tampered.Data, _ = cbor.Marshal(content)
+		tampered.Signature, _ = auths["b"].Sign(tampered.Hash())
+		return &tampered
+	}
+
+	var wg sync.WaitGroup
+	for _, h := range handlers {
+		wg.Add(1)
+		go func(h protocol.Handler) {
+			defer wg.Done()
+			for msg := range h.Listen() {
+				msg = tamper(msg)
+				for id, other := range handlers {
+					if msg.IsFor(id) {
+						other.Accept(msg)
+					}
+				}
+			}
+		}(h)
+	}
+	wg.Wait()
+
+	for _, id := range []party.ID{"a", "c"} {
+		_, err := handlers[id].Result()
+		require.Error(t, err)
+		var protocolErr protocol.Error
+		require.True(t, errors.As(err, &protocolErr))
+		culprits := protocolErr.Culprits
+		var abortErr *protocol.AbortError
+		if errors.As(err, &abortErr) {
+			culprits = abortErr.Culprits
+		}
+		assert.Equal(t, []party.ID{"b"}, culprits, "a should not be blamed for its point-to-point message")
+	}
+}
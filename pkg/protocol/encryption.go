@@ -0,0 +1,145 @@
+package protocol
+
+import (
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// ErrDecryption is the cause of the abort when an authenticated message cannot be decrypted.
+var ErrDecryption = errors.New("failed to decrypt message")
+
+// Encryptor encrypts the point-to-point messages of a handler, so that their content is only visible to their recipient.
+//
+// Each party has a static X25519 key, and the messages between two parties are sealed with XChaCha20-Poly1305,
+// under a key derived from their shared secret and the direction of the message.
+// The ciphertext is bound to the SSID, protocol, round number, sender and recipient of the message.
+type Encryptor struct {
+	secret *ecdh.PrivateKey
+	public map[party.ID]*ecdh.PublicKey
+}
+
+// NewEncryptor returns an Encryptor which decrypts with secret,
+// and encrypts the messages for each party to its key in public.
+func NewEncryptor(secret *ecdh.PrivateKey, public map[party.ID]*ecdh.PublicKey) (*Encryptor, error) {
+	if secret == nil || secret.Curve() != ecdh.X25519() {
+		return nil, errors.New("encryptor: secret must be an X25519 key")
+	}
+	publicCopy := make(map[party.ID]*ecdh.PublicKey, len(public))
+	for id, key := range public {
+		if key == nil || key.Curve() != ecdh.X25519() {
+			return nil, fmt.Errorf("encryptor: public key of %s must be an X25519 key", id)
+		}
+		publicCopy[id] = key
+	}
+	return &Encryptor{
+		secret: secret,
+		public: publicCopy,
+	}, nil
+}
+
+// WithEncryption encrypts all point-to-point messages with e, and requires the ones we receive to be encrypted.
+//
+// For a MultiHandler, these are the messages with a non-empty To,
+// and for a TwoPartyHandler, all messages, since they are only ever sent to the other party.
+// Broadcast messages are not encrypted.
+//
+// A message which can't be decrypted only aborts the protocol, blaming its sender, if it was verified
+// by an Authenticator. Otherwise, anyone could have sent it, so it is dropped and reported to
+// Observer.MessageRejected, and the handler keeps waiting for the genuine message.
+func WithEncryption(e *Encryptor) Option {
+	return func(o *options) {
+		o.encryptor = e
+	}
+}
+
+// aead returns the cipher for messages sent by from to to, where peer is the other party.
+func (e *Encryptor) aead(peer, from, to party.ID) (cipher.AEAD, error) {
+	public, ok := e.public[peer]
+	if !ok {
+		return nil, fmt.Errorf("encryptor: unknown party %s", peer)
+	}
+	shared, err := e.secret.ECDH(public)
+	if err != nil {
+		return nil, fmt.Errorf("encryptor: %w", err)
+	}
+	info := hash.New(
+		hash.BytesWithDomain{TheDomain: "Encryption Key", Bytes: []byte("XChaCha20-Poly1305")},
+		from,
+		to,
+	).Sum()
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err = io.ReadFull(hkdf.New(sha256.New, shared, nil, info), key); err != nil {
+		return nil, fmt.Errorf("encryptor: %w", err)
+	}
+	return chacha20poly1305.NewX(key)
+}
+
+// seal encrypts msg.Data in place.
+func (e *Encryptor) seal(msg *Message, to party.ID) error {
+	aead, err := e.aead(to, msg.From, to)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(msg.Data)+aead.Overhead())
+	if _, err = rand.Read(nonce); err != nil {
+		return fmt.Errorf("encryptor: %w", err)
+	}
+	msg.Data = aead.Seal(nonce, nonce, msg.Data, associatedData(msg, to))
+	return nil
+}
+
+// open returns a copy of msg, with its content decrypted.
+func (e *Encryptor) open(msg *Message, to party.ID) (*Message, error) {
+	aead, err := e.aead(msg.From, msg.From, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(msg.Data) < aead.NonceSize() {
+		return nil, ErrDecryption
+	}
+	nonce, ciphertext := msg.Data[:aead.NonceSize()], msg.Data[aead.NonceSize():]
+	data, err := aead.Open(nil, nonce, ciphertext, associatedData(msg, to))
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	decrypted := *msg
+	decrypted.Data = data
+	return &decrypted, nil
+}
+
+// associatedData binds the ciphertext of msg to its headers.
+func associatedData(msg *Message, to party.ID) []byte {
+	return hash.New(
+		hash.BytesWithDomain{TheDomain: "SSID", Bytes: msg.SSID},
+		msg.From,
+		to,
+		hash.BytesWithDomain{TheDomain: "Protocol", Bytes: []byte(msg.Protocol)},
+		msg.RoundNumber,
+	).Sum()
+}
+
+// encrypt seals the content of msg for to, if the handler has an Encryptor.
+func encrypt(e *Encryptor, msg *Message, to party.ID) error {
+	if e == nil {
+		return nil
+	}
+	return e.seal(msg, to)
+}
+
+// decrypt returns msg with its content decrypted, if the handler has an Encryptor.
+func decrypt(e *Encryptor, msg *Message, to party.ID) (*Message, error) {
+	if e == nil {
+		return msg, nil
+	}
+	return e.open(msg, to)
+}
@@ -0,0 +1,113 @@
+package protocol_test
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/doerner"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+// encryptors creates an Encryptor for each party, sharing the same public keys.
+func encryptors(t *testing.T, partyIDs party.IDSlice) map[party.ID]*protocol.Encryptor {
+	secrets := make(map[party.ID]*ecdh.PrivateKey, len(partyIDs))
+	public := make(map[party.ID]*ecdh.PublicKey, len(partyIDs))
+	for _, id := range partyIDs {
+		sk, err := ecdh.X25519().GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		secrets[id], public[id] = sk, sk.PublicKey()
+	}
+	encs := make(map[party.ID]*protocol.Encryptor, len(partyIDs))
+	for _, id := range partyIDs {
+		e, err := protocol.NewEncryptor(secrets[id], public)
+		require.NoError(t, err)
+		encs[id] = e
+	}
+	return encs
+}
+
+func TestMultiHandlerEncryption(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+	auths := authenticators(t, partyIDs)
+	encs := encryptors(t, partyIDs)
+
+	handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, partyIDs, 1), nil,
+			protocol.WithAuthenticator(auths[id]), protocol.WithEncryption(encs[id]))
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	relay(handlers)
+
+	for _, h := range handlers {
+		_, err := h.Result()
+		require.NoError(t, err)
+	}
+}
+
+func TestTwoPartyHandlerEncryption(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b"}
+	encs := encryptors(t, partyIDs)
+	// b doesn't know a's key
+	other := encryptors(t, partyIDs)
+
+	receiver, err := protocol.NewTwoPartyHandler(doerner.Keygen(group, true, "a", "b", pl), nil, true, protocol.WithEncryption(encs["a"]))
+	require.NoError(t, err)
+	sender, err := protocol.NewTwoPartyHandler(doerner.Keygen(group, false, "b", "a", pl), nil, false, protocol.WithEncryption(encs["b"]))
+	require.NoError(t, err)
+	relay(map[party.ID]protocol.Handler{"a": receiver, "b": sender})
+	_, err = receiver.Result()
+	require.NoError(t, err)
+	_, err = sender.Result()
+	require.NoError(t, err)
+
+	// without authentication, anyone could have sent the message, which is dropped
+	observer := &recorder{}
+	receiver, err = protocol.NewTwoPartyHandler(doerner.Keygen(group, true, "a", "b", pl), nil, true, protocol.WithEncryption(encs["a"]))
+	require.NoError(t, err)
+	sender, err = protocol.NewTwoPartyHandler(doerner.Keygen(group, false, "b", "a", pl), nil, false,
+		protocol.WithEncryption(encs["b"]), protocol.WithObserver(observer))
+	require.NoError(t, err)
+	msg := <-receiver.Listen()
+	forged := *msg
+	forged.Data = append([]byte{}, msg.Data...)
+	forged.Data[len(forged.Data)-1] ^= 1
+	sender.Accept(&forged)
+	require.Len(t, observer.rejected, 1)
+	assert.ErrorIs(t, observer.rejected[0], protocol.ErrDecryption)
+	assert.Empty(t, observer.culprits)
+
+	// the genuine message is still accepted afterwards
+	sender.Accept(msg)
+	assert.Len(t, observer.rejected, 1)
+	assert.NotNil(t, <-sender.Listen())
+
+	// with authentication, the sender is to blame
+	auths := authenticators(t, partyIDs)
+	receiver, err = protocol.NewTwoPartyHandler(doerner.Keygen(group, true, "a", "b", pl), nil, true,
+		protocol.WithEncryption(encs["a"]), protocol.WithAuthenticator(auths["a"]))
+	require.NoError(t, err)
+	sender, err = protocol.NewTwoPartyHandler(doerner.Keygen(group, false, "b", "a", pl), nil, false,
+		protocol.WithEncryption(other["b"]), protocol.WithAuthenticator(auths["b"]))
+	require.NoError(t, err)
+	sender.Accept(<-receiver.Listen())
+
+	_, err = sender.Result()
+	assert.ErrorIs(t, err, protocol.ErrDecryption)
+	var protocolErr protocol.Error
+	require.ErrorAs(t, err, &protocolErr)
+	assert.Equal(t, []party.ID{"a"}, protocolErr.Culprits)
+}
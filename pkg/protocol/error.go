@@ -1,18 +1,82 @@
 package protocol
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 )
 
+// ErrRoundTimeout is the cause of a TimeoutError when a round exceeded the limit set by WithRoundTimeout.
+var ErrRoundTimeout = errors.New("round timed out")
+
+// ErrEquivocation is the cause of the abort when a party broadcast different messages to different parties,
+// which is detected by the echo sub-round of WithEchoBroadcast, or when the BroadcastVerification of a message
+// shows that its sender received different broadcast messages than we did.
+var ErrEquivocation = errors.New("broadcast equivocation")
+
+// ErrStopped is the cause of the abort when the protocol was stopped with Handler.Stop.
+var ErrStopped = errors.New("aborted by user")
+
+// Kinds of misbehavior detected by the rounds of a protocol, which are wrapped by the Err of an Error.
+var (
+	// ErrMalformed is a message which could not be decoded, or contains values which are out of range.
+	ErrMalformed = round.ErrMalformed
+	// ErrNilFields is a message with missing values.
+	ErrNilFields = round.ErrNilFields
+	// ErrInvalidContent is a message with content of the wrong type.
+	ErrInvalidContent = round.ErrInvalidContent
+	// ErrInvalidProof is a zero-knowledge proof which failed to verify.
+	ErrInvalidProof = round.ErrInvalidProof
+	// ErrInvalidCommitment is a decommitment which doesn't match the commitment sent earlier.
+	ErrInvalidCommitment = round.ErrInvalidCommitment
+	// ErrInvalidShare is a share which doesn't match the public values it should be consistent with,
+	// or which results in an invalid output.
+	ErrInvalidShare = round.ErrInvalidShare
+	// ErrInconsistent means that parties disagree on values which should be the same for all.
+	ErrInconsistent = round.ErrInconsistent
+)
+
+// errorKinds identifies the kinds of failures in abort messages.
+// Codes must never change, since they are understood by the other parties.
+var errorKinds = []struct {
+	code string
+	err  error
+}{
+	{"malformed", ErrMalformed},
+	{"nil-fields", ErrNilFields},
+	{"invalid-content", ErrInvalidContent},
+	{"invalid-proof", ErrInvalidProof},
+	{"invalid-commitment", ErrInvalidCommitment},
+	{"invalid-share", ErrInvalidShare},
+	{"inconsistent", ErrInconsistent},
+	{"invalid-signature", ErrInvalidSignature},
+	{"decryption", ErrDecryption},
+	{"equivocation", ErrEquivocation},
+	{"round-timeout", ErrRoundTimeout},
+	{"deadline-exceeded", context.DeadlineExceeded},
+	{"canceled", context.Canceled},
+	{"stopped", ErrStopped},
+}
+
 // Error is a custom error for protocols which contains information about the responsible round in which it occurred,
 // and the party responsible.
+//
+// Err wraps the kind of failure, such as ErrInvalidProof or ErrRoundTimeout, when it is known,
+// which can be checked with errors.Is.
+// When another party aborted, Err is an *AbortError describing its own failure.
 type Error struct {
 	// Culprit is empty if the identity of the misbehaving party cannot be known.
 	Culprits []party.ID
 	// Err is the underlying error.
 	Err error
+	// Protocol is the ID of the protocol which failed.
+	Protocol string
+	// Round is the round in which the failure was detected.
+	Round round.Number
 }
 
 // Error implement error.
@@ -27,3 +91,120 @@ func (e Error) Error() string {
 func (e Error) Unwrap() error {
 	return e.Err
 }
+
+// TimeoutError indicates that the protocol was aborted while still waiting for messages.
+type TimeoutError struct {
+	// Round is the round in which the protocol was stuck.
+	Round round.Number
+	// Missing are the parties which had not delivered their messages for Round.
+	Missing []party.ID
+	// Err is ErrRoundTimeout, or the error of the context passed to the handler.
+	Err error
+}
+
+// Error implement error.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("round %d: waiting for %v: %s", e.Round, e.Missing, e.Err)
+}
+
+// Unwrap implement errors.Wrapper.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// AbortError indicates that the protocol was aborted by another party, and describes its failure
+// as reported in its abort message.
+//
+// It is the Err of the Error returned by the handler, whose culprit is the party which aborted.
+// Since the report cannot be verified, it is up to the application to decide whether to trust the culprits it blames.
+type AbortError struct {
+	// From is the party which aborted.
+	From party.ID
+	// Protocol is the ID of the protocol From was running, or empty if it didn't say.
+	Protocol string
+	// Round is the round in which From detected the failure, or 0 if it didn't say.
+	Round round.Number
+	// Culprits are the parties blamed by From.
+	Culprits []party.ID
+	// Err is the error reported by From.
+	// It wraps the same kind of failure as the original, such as ErrInvalidProof, if From reported a known one.
+	Err error
+}
+
+// Error implement error.
+func (e *AbortError) Error() string {
+	if e.Culprits == nil {
+		return fmt.Sprintf("aborted by other party with error: \"%s\"", e.Err)
+	}
+	return fmt.Sprintf("aborted by other party with error: \"culprits: %v: %s\"", e.Culprits, e.Err)
+}
+
+// Unwrap implement errors.Wrapper.
+func (e *AbortError) Unwrap() error {
+	return e.Err
+}
+
+// reportedError is an error received from another party, which wraps the kind of failure it reported.
+type reportedError struct {
+	msg  string
+	kind error
+}
+
+func (e *reportedError) Error() string { return e.msg }
+
+func (e *reportedError) Unwrap() error { return e.kind }
+
+// abortContent is the Data of the abort message sent to the other parties.
+type abortContent struct {
+	Protocol string
+	Round    round.Number
+	Culprits []party.ID
+	// Kind is the code of the kind of failure in errorKinds, or empty if it is unknown.
+	Kind string
+	// Error is the description of the failure.
+	Error string
+}
+
+// marshalAbort returns the Data of the abort message for err.
+func marshalAbort(err Error) []byte {
+	content := abortContent{
+		Protocol: err.Protocol,
+		Round:    err.Round,
+		Culprits: err.Culprits,
+		Error:    err.Err.Error(),
+	}
+	for _, kind := range errorKinds {
+		if errors.Is(err.Err, kind.err) {
+			content.Kind = kind.code
+			break
+		}
+	}
+	data, e := cbor.Marshal(content)
+	if e != nil {
+		return []byte(err.Error())
+	}
+	return data
+}
+
+// unmarshalAbort returns the error described by the abort message msg.
+func unmarshalAbort(msg *Message) *AbortError {
+	var content abortContent
+	if err := cbor.Unmarshal(msg.Data, &content); err != nil {
+		// the message only contains a description, as sent by earlier versions
+		return &AbortError{From: msg.From, Err: errors.New(string(msg.Data))}
+	}
+	reported := &reportedError{msg: content.Error}
+	for _, kind := range errorKinds {
+		if kind.code == content.Kind {
+			reported.kind = kind.err
+			break
+		}
+	}
+	return &AbortError{
+		From:     msg.From,
+		Protocol: content.Protocol,
+		Round:    content.Round,
+		Culprits: content.Culprits,
+		Err:      reported,
+	}
+}
@@ -0,0 +1,106 @@
+package protocol_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func TestErrorKind(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+
+	handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, partyIDs, 1), nil)
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+
+	// b sends a the share it computed for c
+	done := make(chan struct{})
+	for _, h := range handlers {
+		go func(h protocol.Handler) {
+			shares := map[party.ID]*protocol.Message{}
+			for msg := range h.Listen() {
+				if msg.From == "b" && msg.RoundNumber == 3 && msg.To != "" {
+					shares[msg.To] = msg
+					if len(shares) == 2 {
+						forged := *shares["a"]
+						forged.Data = shares["c"].Data
+						handlers["a"].Accept(&forged)
+						handlers["c"].Accept(shares["c"])
+					}
+					continue
+				}
+				for id, other := range handlers {
+					if msg.IsFor(id) {
+						other.Accept(msg)
+					}
+				}
+			}
+			done <- struct{}{}
+		}(h)
+	}
+	for range handlers {
+		<-done
+	}
+
+	_, err := handlers["a"].Result()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, protocol.ErrInvalidShare)
+	var protocolErr protocol.Error
+	require.True(t, errors.As(err, &protocolErr))
+	assert.Equal(t, []party.ID{"b"}, protocolErr.Culprits)
+	assert.Equal(t, "frost/keygen-threshold", protocolErr.Protocol)
+	assert.Equal(t, round.Number(3), protocolErr.Round)
+}
+
+func TestAbortError(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b"}
+
+	a, err := protocol.NewMultiHandler(frost.Keygen(group, "a", partyIDs, 1), nil)
+	require.NoError(t, err)
+	b, err := protocol.NewMultiHandler(frost.Keygen(group, "b", partyIDs, 1), nil)
+	require.NoError(t, err)
+	<-b.Listen()
+	b.Stop()
+	a.Accept(<-b.Listen())
+
+	_, err = a.Result()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, protocol.ErrStopped)
+	var protocolErr protocol.Error
+	require.True(t, errors.As(err, &protocolErr))
+	assert.Equal(t, []party.ID{"b"}, protocolErr.Culprits)
+	var abortErr *protocol.AbortError
+	require.True(t, errors.As(err, &abortErr))
+	assert.Equal(t, party.ID("b"), abortErr.From)
+	assert.Equal(t, "frost/keygen-threshold", abortErr.Protocol)
+	assert.Equal(t, round.Number(2), abortErr.Round)
+	assert.Equal(t, []party.ID{"b"}, abortErr.Culprits)
+	assert.EqualError(t, abortErr.Err, protocol.ErrStopped.Error())
+
+	// an abort message with only a description
+	a, err = protocol.NewMultiHandler(frost.Keygen(group, "a", partyIDs, 1), nil)
+	require.NoError(t, err)
+	a.Accept(&protocol.Message{
+		SSID:     (<-a.Listen()).SSID,
+		From:     "b",
+		Protocol: "frost/keygen-threshold",
+		Data:     []byte("something went wrong"),
+	})
+	_, err = a.Result()
+	require.True(t, errors.As(err, &abortErr))
+	assert.EqualError(t, abortErr.Err, "something went wrong")
+	assert.Empty(t, abortErr.Protocol)
+	assert.Nil(t, abortErr.Culprits)
+}
@@ -2,9 +2,11 @@ package protocol
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/fxamacker/cbor/v2"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
@@ -36,6 +38,7 @@ type Handler interface {
 // MultiHandler represents an execution of a given protocol.
 // It provides a simple interface for the user to receive/deliver protocol messages.
 type MultiHandler struct {
+	sessionID       []byte
 	currentRound    round.Session
 	rounds          map[round.Number]round.Session
 	err             *Error
@@ -43,25 +46,58 @@ type MultiHandler struct {
 	messages        map[round.Number]map[party.ID]*Message
 	broadcast       map[round.Number]map[party.ID]*Message
 	broadcastHashes map[round.Number][]byte
-	out             chan *Message
-	mtx             sync.Mutex
+	// echoes are the echo messages received for each round, when using WithEchoBroadcast.
+	echoes        map[round.Number]map[party.ID]*Message
+	echoSent      map[round.Number]bool
+	echoBroadcast bool
+	out           chan *Message
+	watchdog      *watchdog
+	auth          Authenticator
+	encryptor     *Encryptor
+	observer      Observer
+	// roundStart is the time at which the current round started.
+	roundStart time.Time
+	mtx        sync.Mutex
 }
 
 // NewMultiHandler expects a StartFunc for the desired protocol. It returns a handler that the user can interact with.
-func NewMultiHandler(create StartFunc, sessionID []byte) (*MultiHandler, error) {
+func NewMultiHandler(create StartFunc, sessionID []byte, opts ...Option) (*MultiHandler, error) {
+	return NewMultiHandlerWithContext(context.Background(), create, sessionID, opts...)
+}
+
+// NewMultiHandlerWithContext is like NewMultiHandler, but the protocol is aborted once ctx is done,
+// or when a round exceeds the limit set by WithRoundTimeout.
+//
+// In both cases, Result returns an Error wrapping a *TimeoutError,
+// whose culprits are the parties we were still waiting on in the current round.
+func NewMultiHandlerWithContext(ctx context.Context, create StartFunc, sessionID []byte, opts ...Option) (*MultiHandler, error) {
+	o := newOptions(opts)
 	r, err := create(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("protocol: failed to create round: %w", err)
 	}
 	h := &MultiHandler{
+		sessionID:       sessionID,
 		currentRound:    r,
 		rounds:          map[round.Number]round.Session{r.Number(): r},
 		messages:        newQueue(r.OtherPartyIDs(), r.FinalRoundNumber()),
 		broadcast:       newQueue(r.OtherPartyIDs(), r.FinalRoundNumber()),
 		broadcastHashes: map[round.Number][]byte{},
-		out:             make(chan *Message, 2*r.N()),
+		echoes:          map[round.Number]map[party.ID]*Message{},
+		echoSent:        map[round.Number]bool{},
+		echoBroadcast:   o.echoBroadcast,
+		out:             make(chan *Message, 2*(r.N()+1)),
+		auth:            o.auth,
+		encryptor:       o.encryptor,
+		observer:        o.observer,
 	}
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.startRound()
 	h.finalize()
+	if h.err == nil && h.result == nil {
+		h.watchdog = newWatchdog(ctx, o.roundTimeout, h.expire)
+	}
 	return h, nil
 }
 
@@ -79,7 +115,7 @@ func (h *MultiHandler) Result() (interface{}, error) {
 }
 
 // Listen returns a channel with outgoing messages that must be sent to other parties.
-// The message received should be _reliably_ broadcast if msg.Broadcast is true.
+// The message received should be _reliably_ broadcast if msg.Broadcast is true, unless the handler uses WithEchoBroadcast.
 // The channel is closed when either an error occurs or the protocol detects an error.
 func (h *MultiHandler) Listen() <-chan *Message {
 	h.mtx.Lock()
@@ -89,42 +125,49 @@ func (h *MultiHandler) Listen() <-chan *Message {
 
 // CanAccept returns true if the message is designated for this protocol protocol execution.
 func (h *MultiHandler) CanAccept(msg *Message) bool {
+	return msg != nil && h.canAccept(msg) == nil
+}
+
+// canAccept returns the reason why CanAccept rejects msg, or nil if it accepts it.
+func (h *MultiHandler) canAccept(msg *Message) error {
 	r := h.currentRound
-	if msg == nil {
-		return false
-	}
 	// are we the intended recipient
 	if !msg.IsFor(r.SelfID()) {
-		return false
+		return errNotForUs
 	}
 	// is the protocol ID correct
 	if msg.Protocol != r.ProtocolID() {
-		return false
+		return errWrongProtocol
 	}
 	// check for same SSID
 	if !bytes.Equal(msg.SSID, r.SSID()) {
-		return false
+		return errWrongSSID
 	}
 	// do we know the sender
 	if !r.PartyIDs().Contains(msg.From) {
-		return false
+		return errUnknownSender
 	}
 
 	// data is cannot be nil
 	if msg.Data == nil {
-		return false
+		return errNoData
+	}
+
+	// echoes are only expected with WithEchoBroadcast
+	if msg.Echo && !h.echoBroadcast {
+		return errUnexpectedEcho
 	}
 
 	// check if message for unexpected round
 	if msg.RoundNumber > r.FinalRoundNumber() {
-		return false
+		return errInvalidRound
 	}
 
 	if msg.RoundNumber < r.Number() && msg.RoundNumber > 0 {
-		return false
+		return errPastRound
 	}
 
-	return true
+	return nil
 }
 
 // Accept tries to process the given message. If an abort occurs, the channel returned by Listen() is closed,
@@ -136,13 +179,32 @@ func (h *MultiHandler) Accept(msg *Message) {
 	defer h.mtx.Unlock()
 
 	// exit early if the message is bad, or if we are already done
-	if !h.CanAccept(msg) || h.err != nil || h.result != nil || h.duplicate(msg) {
+	if msg == nil {
+		return
+	}
+	if err := h.canAccept(msg); err != nil {
+		h.observer.MessageRejected(execution(h.currentRound), msg, err)
+		return
+	}
+	if h.err != nil || h.result != nil {
+		h.observer.MessageRejected(execution(h.currentRound), msg, errProtocolStopped)
+		return
+	}
+	if h.duplicate(msg) {
+		h.observer.MessageRejected(execution(h.currentRound), msg, errDuplicate)
 		return
 	}
 
+	// a message with an invalid signature could have been sent by anyone, so we wait for the genuine one
+	if err := authenticate(h.auth, msg); err != nil {
+		h.observer.MessageRejected(execution(h.currentRound), msg, err)
+		return
+	}
+	h.observer.MessageReceived(execution(h.currentRound), msg)
+
 	// a msg with roundNumber 0 is considered an abort from another party
 	if msg.RoundNumber == 0 {
-		h.abort(fmt.Errorf("aborted by other party with error: \"%s\"", msg.Data), msg.From)
+		h.abort(unmarshalAbort(msg), msg.From)
 		return
 	}
 
@@ -151,6 +213,11 @@ func (h *MultiHandler) Accept(msg *Message) {
 		return
 	}
 
+	if msg.Echo {
+		h.finalize()
+		return
+	}
+
 	if msg.Broadcast {
 		if err := h.verifyBroadcastMessage(msg); err != nil {
 			h.abort(err, msg.From)
@@ -213,6 +280,21 @@ func (h *MultiHandler) verifyMessage(msg *Message) error {
 		}
 	}
 
+	// point-to-point messages may be encrypted
+	if msg.To != "" {
+		decrypted, err := decrypt(h.encryptor, msg, msg.To)
+		if err != nil {
+			// without an Authenticator, we can't know who sent it, so we wait for another one
+			if h.auth == nil {
+				h.messages[msg.RoundNumber][msg.From] = nil
+				h.observer.MessageRejected(execution(h.currentRound), msg, errUndecryptable)
+				return nil
+			}
+			return err
+		}
+		msg = decrypted
+	}
+
 	roundMsg, err := getRoundMessage(msg, r)
 	if err != nil {
 		return err
@@ -236,7 +318,10 @@ func (h *MultiHandler) finalize() {
 		return
 	}
 	if !h.checkBroadcastHash() {
-		h.abort(errors.New("broadcast verification failed"))
+		h.abort(fmt.Errorf("broadcast verification failed: %w", ErrEquivocation))
+		return
+	}
+	if h.echoBroadcast && !h.receivedEchoes() {
 		return
 	}
 
@@ -267,10 +352,21 @@ func (h *MultiHandler) finalize() {
 			Broadcast:             roundMsg.Broadcast,
 			BroadcastVerification: h.broadcastHashes[r.Number()-1],
 		}
+		if msg.To != "" {
+			if err = encrypt(h.encryptor, msg, msg.To); err != nil {
+				h.abort(err, r.SelfID())
+				return
+			}
+		}
+		if err = sign(h.auth, msg); err != nil {
+			h.abort(err, r.SelfID())
+			return
+		}
 		if msg.Broadcast {
 			h.store(msg)
 		}
 		h.out <- msg
+		h.observer.MessageSent(execution(r), msg)
 	}
 
 	roundNumber := r.Number()
@@ -278,22 +374,23 @@ func (h *MultiHandler) finalize() {
 	if _, ok := h.rounds[roundNumber]; ok {
 		return
 	}
+	h.observer.RoundFinished(execution(h.currentRound), h.currentRound.Number(), time.Since(h.roundStart))
+	// An abort happened, in the round we keep as the current one
+	if R, ok := r.(*round.Abort); ok {
+		h.abort(R.Err, R.Culprits...)
+		return
+	}
 	h.rounds[roundNumber] = r
 	h.currentRound = r
+	h.watchdog.reset()
 
-	// either we get the current round, the next one, or one of the two final ones
-	switch R := r.(type) {
-	// An abort happened
-	case *round.Abort:
-		h.abort(R.Err, R.Culprits...)
-		return
-	// We have the result
-	case *round.Output:
+	// either we get the current round, the next one, or the output
+	if R, ok := r.(*round.Output); ok {
 		h.result = R.Result
 		h.abort(nil)
 		return
-	default:
 	}
+	h.startRound()
 
 	if _, ok := r.(round.BroadcastRound); ok {
 		// handle queued broadcast messages, which will then check the subsequent normal message
@@ -326,30 +423,82 @@ func (h *MultiHandler) finalize() {
 }
 
 func (h *MultiHandler) abort(err error, culprits ...party.ID) {
+	h.watchdog.stop()
 	if err != nil {
 		h.err = &Error{
 			Culprits: culprits,
 			Err:      err,
+			Protocol: h.currentRound.ProtocolID(),
+			Round:    h.currentRound.Number(),
 		}
-		select {
-		case h.out <- &Message{
+		msg := &Message{
 			SSID:     h.currentRound.SSID(),
 			From:     h.currentRound.SelfID(),
 			Protocol: h.currentRound.ProtocolID(),
-			Data:     []byte(h.err.Error()),
-		}:
+			Data:     marshalAbort(*h.err),
+		}
+		// without a signature, the other parties will still abort, and blame us
+		_ = sign(h.auth, msg)
+		select {
+		case h.out <- msg:
+			h.observer.MessageSent(execution(h.currentRound), msg)
 		default:
 		}
-
+		h.observer.Aborted(execution(h.currentRound), *h.err, culprits)
 	}
 	close(h.out)
 }
 
+// startRound notifies the observer that the current round started.
+func (h *MultiHandler) startRound() {
+	h.roundStart = time.Now()
+	h.observer.RoundStarted(execution(h.currentRound), h.currentRound.Number())
+}
+
 // Stop cancels the current execution of the protocol, and alerts the other users.
 func (h *MultiHandler) Stop() {
-	if h.err != nil || h.result != nil {
-		h.abort(errors.New("aborted by user"), h.currentRound.SelfID())
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.err == nil && h.result == nil {
+		h.abort(ErrStopped, h.currentRound.SelfID())
+	}
+}
+
+// expire aborts the protocol when the watchdog fires at time t,
+// unless the protocol has since ended or moved on to another round.
+func (h *MultiHandler) expire(err error, t time.Time) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.err != nil || h.result != nil || !h.watchdog.expired(t) {
+		return
+	}
+	missing := h.missing()
+	h.abort(&TimeoutError{
+		Round:   h.currentRound.Number(),
+		Missing: missing,
+		Err:     err,
+	}, missing...)
+}
+
+// missing returns the parties whose messages for the current round have not been received yet.
+func (h *MultiHandler) missing() []party.ID {
+	r := h.currentRound
+	number := r.Number()
+	_, isBroadcast := r.(round.BroadcastRound)
+	var missing []party.ID
+	for _, id := range r.OtherPartyIDs() {
+		if isBroadcast && h.broadcast[number] != nil && h.broadcast[number][id] == nil {
+			missing = append(missing, id)
+			continue
+		}
+		if expectsNormalMessage(r) && h.messages[number] != nil && h.messages[number][id] == nil {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 && h.echoBroadcast {
+		missing = h.missingEchoes()
 	}
+	return missing
 }
 
 func expectsNormalMessage(r round.Session) bool {
@@ -403,6 +552,9 @@ func (h *MultiHandler) duplicate(msg *Message) bool {
 	if msg.RoundNumber == 0 {
 		return false
 	}
+	if msg.Echo {
+		return h.echoes[msg.RoundNumber][msg.From] != nil
+	}
 	var q map[party.ID]*Message
 	if msg.Broadcast {
 		q = h.broadcast[msg.RoundNumber]
@@ -417,6 +569,15 @@ func (h *MultiHandler) duplicate(msg *Message) bool {
 }
 
 func (h *MultiHandler) store(msg *Message) {
+	if msg.Echo {
+		if h.echoes[msg.RoundNumber] == nil {
+			h.echoes[msg.RoundNumber] = make(map[party.ID]*Message, h.currentRound.N())
+		}
+		if h.echoes[msg.RoundNumber][msg.From] == nil {
+			h.echoes[msg.RoundNumber][msg.From] = msg
+		}
+		return
+	}
 	var q map[party.ID]*Message
 	if msg.Broadcast {
 		q = h.broadcast[msg.RoundNumber]
@@ -438,7 +599,7 @@ func getRoundMessage(msg *Message, r round.Session) (round.Message, error) {
 	if msg.Broadcast {
 		b, ok := r.(round.BroadcastRound)
 		if !ok {
-			return round.Message{}, errors.New("got broadcast message when none was expected")
+			return round.Message{}, fmt.Errorf("got broadcast message when none was expected: %w", ErrMalformed)
 		}
 		content = b.BroadcastContent()
 	} else {
@@ -447,7 +608,7 @@ func getRoundMessage(msg *Message, r round.Session) (round.Message, error) {
 
 	// unmarshal message
 	if err := cbor.Unmarshal(msg.Data, content); err != nil {
-		return round.Message{}, fmt.Errorf("failed to unmarshal: %w", err)
+		return round.Message{}, fmt.Errorf("failed to unmarshal: %w: %w", ErrMalformed, err)
 	}
 	roundMsg := round.Message{
 		From:      msg.From,
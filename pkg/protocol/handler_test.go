@@ -0,0 +1,139 @@
+package protocol_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/doerner"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+// relay forwards messages between the given handlers until all of them are done.
+func relay(handlers map[party.ID]protocol.Handler) {
+	done := make(chan struct{})
+	for _, h := range handlers {
+		go func(h protocol.Handler) {
+			for msg := range h.Listen() {
+				for id, other := range handlers {
+					if msg.IsFor(id) {
+						other.Accept(msg)
+					}
+				}
+			}
+			done <- struct{}{}
+		}(h)
+	}
+	for range handlers {
+		<-done
+	}
+}
+
+// requireTimeout checks that h was aborted by a timeout, caused by cause, while waiting for missing.
+func requireTimeout(t *testing.T, h protocol.Handler, cause error, missing ...party.ID) {
+	_, err := h.Result()
+	require.Error(t, err)
+
+	var protocolErr protocol.Error
+	require.True(t, errors.As(err, &protocolErr))
+	assert.Equal(t, missing, protocolErr.Culprits)
+
+	var timeoutErr *protocol.TimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, missing, timeoutErr.Missing)
+	assert.ErrorIs(t, err, cause)
+}
+
+func TestMultiHandlerRoundTimeout(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+
+	// c never starts, so a and b must give up in the first round they wait for its messages
+	handlers := make(map[party.ID]protocol.Handler, 2)
+	for _, id := range partyIDs[:2] {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, partyIDs, 1), nil, protocol.WithRoundTimeout(100*time.Millisecond))
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	relay(handlers)
+
+	// the first party to time out alerts the other one, which may then abort before its own timeout
+	timeouts := 0
+	for _, h := range handlers {
+		_, err := h.Result()
+		require.Error(t, err)
+		var timeoutErr *protocol.TimeoutError
+		if errors.As(err, &timeoutErr) {
+			requireTimeout(t, h, protocol.ErrRoundTimeout, "c")
+			timeouts++
+		}
+	}
+	assert.NotZero(t, timeouts)
+}
+
+func TestMultiHandlerContext(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h, err := protocol.NewMultiHandlerWithContext(ctx, frost.Keygen(group, "a", partyIDs, 1), nil)
+	require.NoError(t, err)
+	cancel()
+	relay(map[party.ID]protocol.Handler{"a": h})
+
+	requireTimeout(t, h, context.Canceled, "b", "c")
+}
+
+func TestMultiHandlerNoTimeout(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandlerWithContext(ctx, frost.Keygen(group, id, partyIDs, 1), nil, protocol.WithRoundTimeout(time.Minute))
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	relay(handlers)
+
+	for _, h := range handlers {
+		_, err := h.Result()
+		require.NoError(t, err)
+	}
+}
+
+func TestTwoPartyHandlerRoundTimeout(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	// the leader sends its first message, but never gets an answer
+	h, err := protocol.NewTwoPartyHandler(doerner.Keygen(curve.Secp256k1{}, true, "a", "b", pl), nil, true, protocol.WithRoundTimeout(100*time.Millisecond))
+	require.NoError(t, err)
+	relay(map[party.ID]protocol.Handler{"a": h})
+
+	requireTimeout(t, h, protocol.ErrRoundTimeout, "b")
+}
+
+func TestStop(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+
+	h, err := protocol.NewMultiHandler(frost.Keygen(group, "a", partyIDs, 1), nil)
+	require.NoError(t, err)
+	h.Stop()
+	relay(map[party.ID]protocol.Handler{"a": h})
+
+	_, err = h.Result()
+	require.Error(t, err)
+	// stopping again has no effect
+	h.Stop()
+}
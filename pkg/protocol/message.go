@@ -27,6 +27,11 @@ type Message struct {
 	// BroadcastVerification is the hash of all messages broadcast by the parties,
 	// and is included in all messages in the round following a broadcast round.
 	BroadcastVerification []byte
+	// Echo indicates that Data contains the hashes of the broadcast messages received by From in RoundNumber,
+	// when the handlers use WithEchoBroadcast.
+	Echo bool
+	// Signature is the signature of Hash() by the sender, when the handlers use an Authenticator.
+	Signature []byte
 }
 
 // String implements fmt.Stringer.
@@ -43,7 +48,7 @@ func (m Message) IsFor(id party.ID) bool {
 }
 
 // Hash returns a 64 byte hash of the message content, including the headers.
-// Can be used to produce a signature for the message.
+// Can be used to produce a signature for the message, and doesn't include the Signature itself.
 func (m *Message) Hash() []byte {
 	var broadcast byte
 	if m.Broadcast {
@@ -59,6 +64,10 @@ func (m *Message) Hash() []byte {
 		hash.BytesWithDomain{TheDomain: "Broadcast", Bytes: []byte{broadcast}},
 		hash.BytesWithDomain{TheDomain: "BroadcastVerification", Bytes: m.BroadcastVerification},
 	)
+	// only added for echo messages, so that the hash of other messages is unchanged
+	if m.Echo {
+		_ = h.WriteAny(&hash.BytesWithDomain{TheDomain: "Echo", Bytes: []byte{1}})
+	}
 	return h.Sum()
 }
 
@@ -75,6 +84,8 @@ type marshallableMessage struct {
 	Data                  []byte
 	Broadcast             bool
 	BroadcastVerification []byte
+	Echo                  bool
+	Signature             []byte
 }
 
 func (m *Message) toMarshallable() *marshallableMessage {
@@ -87,6 +98,8 @@ func (m *Message) toMarshallable() *marshallableMessage {
 		Data:                  m.Data,
 		Broadcast:             m.Broadcast,
 		BroadcastVerification: m.BroadcastVerification,
+		Echo:                  m.Echo,
+		Signature:             m.Signature,
 	}
 }
 
@@ -97,7 +110,7 @@ func (m *Message) MarshalBinary() ([]byte, error) {
 func (m *Message) UnmarshalBinary(data []byte) error {
 	deserialized := m.toMarshallable()
 	if err := cbor.Unmarshal(data, deserialized); err != nil {
-		return nil
+		return err
 	}
 	m.SSID = deserialized.SSID
 	m.From = deserialized.From
@@ -107,5 +120,7 @@ func (m *Message) UnmarshalBinary(data []byte) error {
 	m.Data = deserialized.Data
 	m.Broadcast = deserialized.Broadcast
 	m.BroadcastVerification = deserialized.BroadcastVerification
+	m.Echo = deserialized.Echo
+	m.Signature = deserialized.Signature
 	return nil
 }
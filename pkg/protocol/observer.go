@@ -0,0 +1,86 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// RoundNumber is the number of a round of a protocol, starting at 1.
+//
+// It is the type of Message.RoundNumber, and can be used by packages which can't import the package defining it.
+type RoundNumber = round.Number
+
+// Execution identifies the protocol execution of a Handler in the callbacks of an Observer.
+type Execution struct {
+	// Protocol is the ID of the protocol, such as "cmp/sign".
+	Protocol string
+	// SelfID is the party running the handler.
+	SelfID party.ID
+	// SSID is the session identifier shared by all parties of the execution.
+	SSID []byte
+}
+
+// Observer is notified of the progress of a Handler, to collect metrics or logs.
+//
+// Its methods are called synchronously while the handler holds its lock,
+// so they should return quickly and must not call the handler.
+// An Observer may be shared by several handlers, and must then be safe for concurrent use.
+type Observer interface {
+	// RoundStarted is called when the handler starts waiting for the messages of a round.
+	RoundStarted(e Execution, number RoundNumber)
+	// RoundFinished is called when a round was finalized, d after it started.
+	RoundFinished(e Execution, number RoundNumber, d time.Duration)
+	// MessageSent is called for each message put on the Listen channel.
+	MessageSent(e Execution, msg *Message)
+	// MessageReceived is called for each message given to Accept which is processed by the handler.
+	MessageReceived(e Execution, msg *Message)
+	// MessageRejected is called for each message given to Accept which is ignored, with the reason why.
+	MessageRejected(e Execution, msg *Message, reason error)
+	// Aborted is called once if the protocol fails, with the error returned by Result and the culprits it blames.
+	Aborted(e Execution, err error, culprits []party.ID)
+}
+
+// WithObserver notifies o of the progress of the Handler.
+func WithObserver(o Observer) Option {
+	return func(opts *options) {
+		opts.observer = o
+	}
+}
+
+// Reasons given to Observer.MessageRejected.
+var (
+	errNotForUs        = errors.New("message is not for this party")
+	errWrongProtocol   = errors.New("message is for another protocol")
+	errWrongSSID       = errors.New("message is for another session")
+	errUnknownSender   = errors.New("message is from an unknown party")
+	errNoData          = errors.New("message has no data")
+	errUnexpectedEcho  = errors.New("echo message without echo broadcast")
+	errInvalidRound    = errors.New("message is for an invalid round")
+	errPastRound       = errors.New("message is for a past round")
+	errDuplicate       = errors.New("message was already received")
+	errProtocolStopped = errors.New("protocol has already ended")
+	errUndecryptable   = fmt.Errorf("%w, and its sender isn't authenticated", ErrDecryption)
+)
+
+// nopObserver is the Observer of a handler without WithObserver.
+type nopObserver struct{}
+
+func (nopObserver) RoundStarted(Execution, RoundNumber)                 {}
+func (nopObserver) RoundFinished(Execution, RoundNumber, time.Duration) {}
+func (nopObserver) MessageSent(Execution, *Message)                     {}
+func (nopObserver) MessageReceived(Execution, *Message)                 {}
+func (nopObserver) MessageRejected(Execution, *Message, error)          {}
+func (nopObserver) Aborted(Execution, error, []party.ID)                {}
+
+// execution returns the Execution of the protocol run with session r.
+func execution(r round.Session) Execution {
+	return Execution{
+		Protocol: r.ProtocolID(),
+		SelfID:   r.SelfID(),
+		SSID:     r.SSID(),
+	}
+}
@@ -0,0 +1,140 @@
+package protocol_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/doerner"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+// recorder is an Observer keeping track of the events of a single handler.
+type recorder struct {
+	mtx      sync.Mutex
+	started  []protocol.RoundNumber
+	finished []protocol.RoundNumber
+	sent     int
+	received int
+	rejected []error
+	culprits [][]party.ID
+}
+
+func (r *recorder) RoundStarted(_ protocol.Execution, number protocol.RoundNumber) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.started = append(r.started, number)
+}
+
+func (r *recorder) RoundFinished(_ protocol.Execution, number protocol.RoundNumber, d time.Duration) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.finished = append(r.finished, number)
+}
+
+func (r *recorder) MessageSent(protocol.Execution, *protocol.Message) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.sent++
+}
+
+func (r *recorder) MessageReceived(protocol.Execution, *protocol.Message) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.received++
+}
+
+func (r *recorder) MessageRejected(_ protocol.Execution, _ *protocol.Message, reason error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.rejected = append(r.rejected, reason)
+}
+
+func (r *recorder) Aborted(_ protocol.Execution, _ error, culprits []party.ID) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.culprits = append(r.culprits, culprits)
+}
+
+func TestMultiHandlerObserver(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+
+	observers := make(map[party.ID]*recorder, len(partyIDs))
+	handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+	for _, id := range partyIDs {
+		observers[id] = &recorder{}
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, partyIDs, 1), nil, protocol.WithObserver(observers[id]))
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	relay(handlers)
+
+	for id, h := range handlers {
+		_, err := h.Result()
+		require.NoError(t, err)
+		o := observers[id]
+		assert.Equal(t, []protocol.RoundNumber{1, 2, 3}, o.started)
+		assert.Equal(t, []protocol.RoundNumber{1, 2, 3}, o.finished)
+		// each party broadcasts in rounds 2 and 3, and sends a message to each other party in round 2
+		assert.Equal(t, 2+2, o.sent)
+		assert.Equal(t, 2*3, o.received)
+		assert.Empty(t, o.rejected)
+		assert.Empty(t, o.culprits)
+	}
+
+	// messages for another session, duplicates and messages after Stop are rejected
+	o := &recorder{}
+	a, err := protocol.NewMultiHandler(frost.Keygen(group, "a", partyIDs, 1), nil, protocol.WithObserver(o))
+	require.NoError(t, err)
+	b, err := protocol.NewMultiHandler(frost.Keygen(group, "b", partyIDs, 1), nil)
+	require.NoError(t, err)
+	msg := <-b.Listen()
+	other := *msg
+	other.SSID = []byte("other")
+	a.Accept(&other)
+	a.Accept(msg)
+	a.Accept(msg)
+	a.Stop()
+	a.Accept(msg)
+	require.Len(t, o.rejected, 3)
+	assert.EqualError(t, o.rejected[0], "message is for another session")
+	assert.EqualError(t, o.rejected[1], "message was already received")
+	assert.EqualError(t, o.rejected[2], "protocol has already ended")
+	assert.Equal(t, 1, o.received)
+	assert.Equal(t, [][]party.ID{{"a"}}, o.culprits)
+	b.Stop()
+}
+
+func TestTwoPartyHandlerObserver(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	group := curve.Secp256k1{}
+	receiverObserver, senderObserver := &recorder{}, &recorder{}
+	receiver, err := protocol.NewTwoPartyHandler(doerner.Keygen(group, true, "a", "b", pl), nil, true, protocol.WithObserver(receiverObserver))
+	require.NoError(t, err)
+	sender, err := protocol.NewTwoPartyHandler(doerner.Keygen(group, false, "b", "a", pl), nil, false, protocol.WithObserver(senderObserver))
+	require.NoError(t, err)
+	relay(map[party.ID]protocol.Handler{"a": receiver, "b": sender})
+	_, err = receiver.Result()
+	require.NoError(t, err)
+	_, err = sender.Result()
+	require.NoError(t, err)
+
+	for _, o := range []*recorder{receiverObserver, senderObserver} {
+		assert.Equal(t, []protocol.RoundNumber{1, 2, 3}, o.started)
+		assert.Equal(t, o.started, o.finished)
+		assert.NotZero(t, o.sent)
+		assert.NotZero(t, o.received)
+		assert.Empty(t, o.culprits)
+	}
+	assert.Equal(t, receiverObserver.sent, senderObserver.received)
+	assert.Equal(t, senderObserver.sent, receiverObserver.received)
+}
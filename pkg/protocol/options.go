@@ -0,0 +1,40 @@
+package protocol
+
+import "time"
+
+// Option configures optional behavior of a Handler.
+type Option func(*options)
+
+type options struct {
+	// roundTimeout is the maximum duration of a round, or 0 if there's no limit.
+	roundTimeout time.Duration
+	// auth signs and verifies messages, or is nil if messages aren't authenticated.
+	auth Authenticator
+	// encryptor encrypts point-to-point messages, or is nil if they are sent in the clear.
+	encryptor *Encryptor
+	// echoBroadcast adds an echo sub-round to every broadcast round.
+	echoBroadcast bool
+	// observer is notified of the progress of the protocol.
+	observer Observer
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.observer == nil {
+		o.observer = nopObserver{}
+	}
+	return o
+}
+
+// WithRoundTimeout aborts the protocol with a *TimeoutError if a round doesn't
+// receive all its messages within d, after the previous round finished.
+//
+// A duration of 0 disables the limit, which is the default.
+func WithRoundTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.roundTimeout = d
+	}
+}
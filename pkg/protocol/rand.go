@@ -0,0 +1,41 @@
+package protocol
+
+import (
+	"crypto/rand"
+	"io"
+)
+
+// StartOption configures the execution of a protocol by the local party.
+// It is given to the function creating the StartFunc, such as cmp.Keygen or frost.Sign.
+type StartOption func(*startOptions)
+
+type startOptions struct {
+	// rand is the source of randomness, or nil to use crypto/rand.Reader.
+	rand io.Reader
+}
+
+// WithRand makes the local party read all its randomness from r, instead of crypto/rand.Reader.
+//
+// r must be a cryptographically secure source of randomness, and must never be reused across executions,
+// since a party producing the same randomness twice may leak its secret.
+// It is meant for integrators providing their own CSPRNG,
+// and for tests, known-answer vectors and transcript replay, which use a deterministic r.
+// A protocol given a deterministic r produces the same messages for the same inputs, as long as it runs without a *pool.Pool,
+// since the order in which the workers of a pool read from r is not deterministic.
+func WithRand(r io.Reader) StartOption {
+	return func(o *startOptions) {
+		o.rand = r
+	}
+}
+
+// Rand returns the source of randomness set by opts, which is crypto/rand.Reader by default.
+func Rand(opts ...StartOption) io.Reader {
+	o := &startOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.rand == nil {
+		return rand.Reader
+	}
+	return o.rand
+}
@@ -0,0 +1,78 @@
+package protocol_test
+
+import (
+	mrand "math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+// seededRand returns a deterministic source of randomness for each party.
+// It is not cryptographically secure, and is only suitable for tests.
+func seededRand(partyIDs party.IDSlice, seed int64) map[party.ID]protocol.StartOption {
+	opts := make(map[party.ID]protocol.StartOption, len(partyIDs))
+	for i, id := range partyIDs {
+		opts[id] = protocol.WithRand(mrand.New(mrand.NewSource(seed + int64(i))))
+	}
+	return opts
+}
+
+func TestWithRand(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+	signers := partyIDs[:2]
+	message := []byte("hello")
+
+	run := func(seed int64) (map[party.ID]*frost.Config, map[party.ID]frost.Signature) {
+		opts := seededRand(partyIDs, seed)
+		handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+		for _, id := range partyIDs {
+			h, err := protocol.NewMultiHandler(frost.Keygen(group, id, partyIDs, 1, opts[id]), nil)
+			require.NoError(t, err)
+			handlers[id] = h
+		}
+		relay(handlers)
+
+		configs := make(map[party.ID]*frost.Config, len(partyIDs))
+		for id, h := range handlers {
+			r, err := h.Result()
+			require.NoError(t, err)
+			configs[id] = r.(*frost.Config)
+		}
+
+		handlers = make(map[party.ID]protocol.Handler, len(signers))
+		for _, id := range signers {
+			h, err := protocol.NewMultiHandler(frost.Sign(configs[id], signers, message, opts[id]), nil)
+			require.NoError(t, err)
+			handlers[id] = h
+		}
+		relay(handlers)
+
+		signatures := make(map[party.ID]frost.Signature, len(signers))
+		for id, h := range handlers {
+			r, err := h.Result()
+			require.NoError(t, err)
+			signatures[id] = r.(frost.Signature)
+			assert.True(t, signatures[id].Verify(configs[id].PublicKey, message))
+		}
+		return configs, signatures
+	}
+
+	configs1, signatures1 := run(1)
+	configs2, signatures2 := run(1)
+	for _, id := range partyIDs {
+		assert.True(t, configs1[id].PublicKey.Equal(configs2[id].PublicKey))
+		assert.True(t, configs1[id].PrivateShare.Equal(configs2[id].PrivateShare))
+	}
+	for _, id := range signers {
+		assert.True(t, signatures1[id].R.Equal(signatures2[id].R))
+	}
+
+	configs3, _ := run(2)
+	assert.False(t, configs1["a"].PublicKey.Equal(configs3["a"].PublicKey))
+}
@@ -0,0 +1,230 @@
+package protocol
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// multiHandlerSnapshot is the serialized form of a MultiHandler.
+type multiHandlerSnapshot struct {
+	SessionID []byte
+	// Round is the number of the current round, whose state is given by State.
+	Round round.Number
+	State []byte
+	// Messages are the queued messages, including our own broadcast messages, and the echoes of the other parties.
+	Messages        []*Message
+	BroadcastHashes map[round.Number][]byte
+}
+
+// Snapshot serializes the state of the protocol execution, so that it can be resumed with RestoreMultiHandler,
+// for example after the process was restarted.
+//
+// The snapshot contains the secret state of the current round, and must be stored as carefully as the result.
+// Only the most recent snapshot should be resumed, and at most once,
+// since running a round again from an older state may reuse its secret nonces.
+func (h *MultiHandler) Snapshot() ([]byte, error) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.err != nil || h.result != nil {
+		return nil, errors.New("protocol: finished")
+	}
+	restorer, ok := h.currentRound.(round.Restorer)
+	if !ok {
+		return nil, round.ErrNoSnapshot
+	}
+	state, err := restorer.Snapshot(h.currentRound)
+	if err != nil {
+		return nil, fmt.Errorf("protocol: %w", err)
+	}
+	var messages []*Message
+	for _, q := range []map[round.Number]map[party.ID]*Message{h.broadcast, h.messages, h.echoes} {
+		for number, msgs := range q {
+			if number < h.currentRound.Number() {
+				continue
+			}
+			for _, msg := range msgs {
+				if msg != nil {
+					messages = append(messages, msg)
+				}
+			}
+		}
+	}
+	return cbor.Marshal(&multiHandlerSnapshot{
+		SessionID:       h.sessionID,
+		Round:           h.currentRound.Number(),
+		State:           state,
+		Messages:        messages,
+		BroadcastHashes: h.broadcastHashes,
+	})
+}
+
+// RestoreMultiHandler resumes the protocol execution saved by MultiHandler.Snapshot.
+//
+// create must be the same StartFunc used to create the original handler, with the same parameters.
+// It is only used to recover the parameters of the protocol, and none of its rounds are executed again.
+// Messages sent by the original handler are not sent again.
+func RestoreMultiHandler(data []byte, create StartFunc, opts ...Option) (*MultiHandler, error) {
+	return RestoreMultiHandlerWithContext(context.Background(), data, create, opts...)
+}
+
+// RestoreMultiHandlerWithContext is like RestoreMultiHandler, with the behavior of NewMultiHandlerWithContext.
+func RestoreMultiHandlerWithContext(ctx context.Context, data []byte, create StartFunc, opts ...Option) (*MultiHandler, error) {
+	o := newOptions(opts)
+	var s multiHandlerSnapshot
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("protocol: failed to unmarshal snapshot: %w", err)
+	}
+	first, r, err := restoreRound(create, s.SessionID, s.Round, s.State)
+	if err != nil {
+		return nil, err
+	}
+	h := &MultiHandler{
+		sessionID:       s.SessionID,
+		currentRound:    r,
+		rounds:          map[round.Number]round.Session{first.Number(): first, r.Number(): r},
+		messages:        newQueue(r.OtherPartyIDs(), r.FinalRoundNumber()),
+		broadcast:       newQueue(r.OtherPartyIDs(), r.FinalRoundNumber()),
+		broadcastHashes: s.BroadcastHashes,
+		echoes:          map[round.Number]map[party.ID]*Message{},
+		echoSent:        map[round.Number]bool{},
+		echoBroadcast:   o.echoBroadcast,
+		out:             make(chan *Message, 2*(r.N()+1)),
+		auth:            o.auth,
+		encryptor:       o.encryptor,
+		observer:        o.observer,
+	}
+	if h.broadcastHashes == nil {
+		h.broadcastHashes = map[round.Number][]byte{}
+	}
+	for _, msg := range s.Messages {
+		if msg == nil || !bytes.Equal(msg.SSID, r.SSID()) || !r.PartyIDs().Contains(msg.From) {
+			return nil, errors.New("protocol: snapshot contains an invalid message")
+		}
+		h.store(msg)
+	}
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.startRound()
+	h.finalize()
+	if h.err == nil && h.result == nil {
+		h.watchdog = newWatchdog(ctx, o.roundTimeout, h.expire)
+	}
+	return h, nil
+}
+
+// twoPartyHandlerSnapshot is the serialized form of a TwoPartyHandler.
+type twoPartyHandlerSnapshot struct {
+	SessionID []byte
+	Leader    bool
+	// Round is the number of the current round, whose state is given by State.
+	Round round.Number
+	State []byte
+	// Messages are the queued messages for the next rounds.
+	Messages []*Message
+}
+
+// Snapshot serializes the state of the protocol execution, so that it can be resumed with RestoreTwoPartyHandler.
+//
+// The same precautions as for MultiHandler.Snapshot apply.
+func (h *TwoPartyHandler) Snapshot() ([]byte, error) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.err != nil || h.result != nil {
+		return nil, errors.New("protocol: finished")
+	}
+	restorer, ok := h.round.(round.Restorer)
+	if !ok {
+		return nil, round.ErrNoSnapshot
+	}
+	state, err := restorer.Snapshot(h.round)
+	if err != nil {
+		return nil, fmt.Errorf("protocol: %w", err)
+	}
+	var messages []*Message
+	for number, msg := range h.messages {
+		if number >= h.round.Number() {
+			messages = append(messages, msg)
+		}
+	}
+	return cbor.Marshal(&twoPartyHandlerSnapshot{
+		SessionID: h.sessionID,
+		Leader:    h.leader,
+		Round:     h.round.Number(),
+		State:     state,
+		Messages:  messages,
+	})
+}
+
+// RestoreTwoPartyHandler resumes the protocol execution saved by TwoPartyHandler.Snapshot.
+//
+// create must be the same StartFunc used to create the original handler, with the same parameters.
+func RestoreTwoPartyHandler(data []byte, create StartFunc, opts ...Option) (*TwoPartyHandler, error) {
+	return RestoreTwoPartyHandlerWithContext(context.Background(), data, create, opts...)
+}
+
+// RestoreTwoPartyHandlerWithContext is like RestoreTwoPartyHandler, with the behavior of NewTwoPartyHandlerWithContext.
+func RestoreTwoPartyHandlerWithContext(ctx context.Context, data []byte, create StartFunc, opts ...Option) (*TwoPartyHandler, error) {
+	o := newOptions(opts)
+	var s twoPartyHandlerSnapshot
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("protocol: failed to unmarshal snapshot: %w", err)
+	}
+	_, r, err := restoreRound(create, s.SessionID, s.Round, s.State)
+	if err != nil {
+		return nil, err
+	}
+	h := &TwoPartyHandler{
+		sessionID: s.SessionID,
+		round:     r,
+		leader:    s.Leader,
+		messages:  map[round.Number]*Message{},
+		out:       make(chan *Message, 2),
+		auth:      o.auth,
+		encryptor: o.encryptor,
+		observer:  o.observer,
+		mtx:       sync.Mutex{},
+	}
+	for _, msg := range s.Messages {
+		if msg == nil || !h.CanAccept(msg) {
+			return nil, errors.New("protocol: snapshot contains an invalid message")
+		}
+		h.messages[msg.RoundNumber] = msg
+	}
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.startRound()
+	if h.messages[r.Number()] != nil {
+		h.advance()
+	}
+	if h.err == nil && h.result == nil {
+		h.watchdog = newWatchdog(ctx, o.roundTimeout, h.expire)
+	}
+	return h, nil
+}
+
+// restoreRound creates the first round of a protocol, and uses it to restore the round with the given number.
+func restoreRound(create StartFunc, sessionID []byte, number round.Number, state []byte) (first, r round.Session, err error) {
+	first, err = create(sessionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("protocol: failed to create round: %w", err)
+	}
+	restorer, ok := first.(round.Restorer)
+	if !ok {
+		return nil, nil, round.ErrNoSnapshot
+	}
+	r, err = restorer.Restore(number, state)
+	if err != nil {
+		return nil, nil, fmt.Errorf("protocol: failed to restore round %d: %w", number, err)
+	}
+	if r.Number() != number {
+		return nil, nil, fmt.Errorf("protocol: restored round %d instead of %d", r.Number(), number)
+	}
+	return first, r, nil
+}
@@ -2,42 +2,72 @@ package protocol
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/fxamacker/cbor/v2"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
 )
 
 // TwoPartyHandler represents a restriction of the Handler for 2 party protocols.
 type TwoPartyHandler struct {
-	round    round.Session
-	leader   bool
-	err      error
-	result   interface{}
-	messages map[round.Number]*Message
-	out      chan *Message
-	mtx      sync.Mutex
+	sessionID []byte
+	round     round.Session
+	leader    bool
+	err       error
+	result    interface{}
+	messages  map[round.Number]*Message
+	out       chan *Message
+	watchdog  *watchdog
+	auth      Authenticator
+	encryptor *Encryptor
+	observer  Observer
+	// roundStart is the time at which the current round started.
+	roundStart time.Time
+	mtx        sync.Mutex
 }
 
-func NewTwoPartyHandler(create StartFunc, sessionID []byte, leader bool) (*TwoPartyHandler, error) {
+func NewTwoPartyHandler(create StartFunc, sessionID []byte, leader bool, opts ...Option) (*TwoPartyHandler, error) {
+	return NewTwoPartyHandlerWithContext(context.Background(), create, sessionID, leader, opts...)
+}
+
+// NewTwoPartyHandlerWithContext is like NewTwoPartyHandler, but the protocol is aborted once ctx is done,
+// or when a round exceeds the limit set by WithRoundTimeout.
+//
+// In both cases, Result returns an Error wrapping a *TimeoutError, blaming the other party
+// if we were still waiting on its message.
+func NewTwoPartyHandlerWithContext(ctx context.Context, create StartFunc, sessionID []byte, leader bool, opts ...Option) (*TwoPartyHandler, error) {
+	o := newOptions(opts)
 	r, err := create(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("protocol: failed to create round: %w", err)
 	}
 	handler := &TwoPartyHandler{
-		round:    r,
-		leader:   leader,
-		err:      nil,
-		result:   nil,
-		messages: map[round.Number]*Message{},
-		out:      make(chan *Message, 2),
-		mtx:      sync.Mutex{},
+		sessionID: sessionID,
+		round:     r,
+		leader:    leader,
+		err:       nil,
+		result:    nil,
+		messages:  map[round.Number]*Message{},
+		out:       make(chan *Message, 2),
+		auth:      o.auth,
+		encryptor: o.encryptor,
+		observer:  o.observer,
+		mtx:       sync.Mutex{},
 	}
+	handler.mtx.Lock()
+	defer handler.mtx.Unlock()
+	handler.startRound()
 	if leader {
 		handler.advance()
 	}
+	if handler.err == nil && handler.result == nil {
+		handler.watchdog = newWatchdog(ctx, o.roundTimeout, handler.expire)
+	}
 	return handler, nil
 }
 
@@ -60,31 +90,70 @@ func (h *TwoPartyHandler) Listen() <-chan *Message {
 }
 
 func (h *TwoPartyHandler) Stop() {
-	if h.err != nil || h.result != nil {
-		h.abort(errors.New("aborted by user"))
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.err == nil && h.result == nil {
+		h.abort(ErrStopped, h.round.SelfID())
 	}
 }
 
+// expire aborts the protocol when the watchdog fires at time t,
+// unless the protocol has since ended or moved on to another round.
+func (h *TwoPartyHandler) expire(err error, t time.Time) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.err != nil || h.result != nil || !h.watchdog.expired(t) {
+		return
+	}
+	var missing []party.ID
+	if !h.canAdvance() {
+		missing = h.round.OtherPartyIDs()
+	}
+	h.abort(&TimeoutError{
+		Round:   h.round.Number(),
+		Missing: missing,
+		Err:     err,
+	}, missing...)
+}
+
 func (h *TwoPartyHandler) String() string {
 	return fmt.Sprintf("party: %s, protocol: %s", h.round.SelfID(), h.round.ProtocolID())
 }
 
-func (h *TwoPartyHandler) abort(err error) {
+func (h *TwoPartyHandler) abort(err error, culprits ...party.ID) {
+	h.watchdog.stop()
 	if err != nil {
-		h.err = err
-		select {
-		case h.out <- &Message{
+		protocolErr := Error{
+			Culprits: culprits,
+			Err:      err,
+			Protocol: h.round.ProtocolID(),
+			Round:    h.round.Number(),
+		}
+		h.err = protocolErr
+		msg := &Message{
 			SSID:     h.round.SSID(),
 			From:     h.round.SelfID(),
 			Protocol: h.round.ProtocolID(),
-			Data:     []byte(h.err.Error()),
-		}:
+			Data:     marshalAbort(protocolErr),
+		}
+		// without a signature, the other party will still abort, and blame us
+		_ = sign(h.auth, msg)
+		select {
+		case h.out <- msg:
+			h.observer.MessageSent(execution(h.round), msg)
 		default:
 		}
+		h.observer.Aborted(execution(h.round), h.err, culprits)
 	}
 	close(h.out)
 }
 
+// startRound notifies the observer that the current round started.
+func (h *TwoPartyHandler) startRound() {
+	h.roundStart = time.Now()
+	h.observer.RoundStarted(execution(h.round), h.round.Number())
+}
+
 func (h *TwoPartyHandler) canAdvance() bool {
 	if h.round.MessageContent() == nil {
 		return true
@@ -98,7 +167,7 @@ func (h *TwoPartyHandler) canAdvance() bool {
 func extractRoundMessage(r round.Session, msg *Message) (round.Message, error) {
 	content := r.MessageContent()
 	if err := cbor.Unmarshal(msg.Data, content); err != nil {
-		return round.Message{}, fmt.Errorf("failed to unmarshal message: %w", err)
+		return round.Message{}, fmt.Errorf("failed to unmarshal message: %w: %w", ErrMalformed, err)
 	}
 	roundMsg := round.Message{
 		From:      msg.From,
@@ -114,7 +183,17 @@ func (h *TwoPartyHandler) verifyMessage(msg *Message) error {
 		return nil
 	}
 	r := h.round
-	roundMsg, err := extractRoundMessage(r, msg)
+	decrypted, err := decrypt(h.encryptor, msg, r.SelfID())
+	if err != nil {
+		// without an Authenticator, we can't know who sent it, so we wait for another one
+		if h.auth == nil {
+			delete(h.messages, msg.RoundNumber)
+			h.observer.MessageRejected(execution(r), msg, errUndecryptable)
+			return nil
+		}
+		return err
+	}
+	roundMsg, err := extractRoundMessage(r, decrypted)
 	if err != nil {
 		return err
 	}
@@ -134,13 +213,17 @@ func (h *TwoPartyHandler) advance() {
 	for h.canAdvance() {
 		msg := h.messages[h.round.Number()]
 		if err := h.verifyMessage(msg); err != nil {
-			h.abort(err)
+			h.abort(err, msg.From)
+			return
+		}
+		// the message may have been dropped
+		if !h.canAdvance() {
 			return
 		}
 		out := make(chan *round.Message, 1)
 		newRound, err := h.round.Finalize(out)
 		if err != nil || newRound == nil {
-			h.abort(err)
+			h.abort(err, h.round.SelfID())
 			return
 		}
 		close(out)
@@ -159,60 +242,88 @@ func (h *TwoPartyHandler) advance() {
 				Broadcast:             roundMsg.Broadcast,
 				BroadcastVerification: nil,
 			}
+			if err = encrypt(h.encryptor, msg, newRound.OtherPartyIDs()[0]); err != nil {
+				h.abort(err, h.round.SelfID())
+				return
+			}
+			if err = sign(h.auth, msg); err != nil {
+				h.abort(err, h.round.SelfID())
+				return
+			}
 			h.out <- msg
+			h.observer.MessageSent(execution(newRound), msg)
 		}
-		h.round = newRound
-		switch R := newRound.(type) {
-		// An abort happened
-		case *round.Abort:
-			h.abort(R.Err)
+		h.observer.RoundFinished(execution(h.round), h.round.Number(), time.Since(h.roundStart))
+		// An abort happened, in the round we keep as the current one
+		if R, ok := newRound.(*round.Abort); ok {
+			h.abort(R.Err, R.Culprits...)
 			return
+		}
+		h.round = newRound
+		h.watchdog.reset()
 		// We have the result
-		case *round.Output:
+		if R, ok := newRound.(*round.Output); ok {
 			h.result = R.Result
 			h.abort(nil)
 			return
-		default:
 		}
+		h.startRound()
 	}
 }
 
 func (h *TwoPartyHandler) CanAccept(msg *Message) bool {
+	return msg != nil && h.canAccept(msg) == nil
+}
+
+// canAccept returns the reason why CanAccept rejects msg, or nil if it accepts it.
+func (h *TwoPartyHandler) canAccept(msg *Message) error {
 	r := h.round
-	if msg == nil {
-		return false
-	}
 	if !msg.IsFor(r.SelfID()) {
-		return false
+		return errNotForUs
 	}
 	if msg.Protocol != r.ProtocolID() {
-		return false
+		return errWrongProtocol
 	}
 	if !bytes.Equal(msg.SSID, r.SSID()) {
-		return false
+		return errWrongSSID
 	}
 	if !r.PartyIDs().Contains(msg.From) {
-		return false
+		return errUnknownSender
 	}
 	if msg.Data == nil {
-		return false
+		return errNoData
 	}
 	if msg.RoundNumber > r.FinalRoundNumber() {
-		return false
+		return errInvalidRound
 	}
-	return true
+	return nil
 }
 
 func (h *TwoPartyHandler) Accept(msg *Message) {
 	h.mtx.Lock()
 	defer h.mtx.Unlock()
 
-	if !h.CanAccept(msg) || h.err != nil || h.result != nil {
+	if msg == nil {
+		return
+	}
+	if err := h.canAccept(msg); err != nil {
+		h.observer.MessageRejected(execution(h.round), msg, err)
+		return
+	}
+	if h.err != nil || h.result != nil {
+		h.observer.MessageRejected(execution(h.round), msg, errProtocolStopped)
+		return
+	}
+
+	// a message with an invalid signature could have been sent by anyone, so we wait for the genuine one
+	if err := authenticate(h.auth, msg); err != nil {
+		h.observer.MessageRejected(execution(h.round), msg, err)
 		return
 	}
+	h.observer.MessageReceived(execution(h.round), msg)
 
 	if msg.RoundNumber == 0 {
-		h.abort(fmt.Errorf("aborted by other party with error: \"%s\"", msg.Data))
+		h.abort(unmarshalAbort(msg), msg.From)
 		return
 	}
 
@@ -0,0 +1,70 @@
+package protocol
+
+import (
+	"context"
+	"time"
+)
+
+// watchdog calls expire when a context is done, or when a round exceeds its time limit.
+//
+// The handler must call reset when a new round starts, and stop when the protocol ends.
+// Both should be called while holding the handler's lock, which expire should also acquire.
+type watchdog struct {
+	timeout  time.Duration
+	timer    *time.Timer
+	deadline time.Time
+	done     chan struct{}
+}
+
+func newWatchdog(ctx context.Context, timeout time.Duration, expire func(err error, deadline time.Time)) *watchdog {
+	w := &watchdog{
+		timeout: timeout,
+		done:    make(chan struct{}),
+	}
+	if timeout > 0 {
+		w.deadline = time.Now().Add(timeout)
+		w.timer = time.AfterFunc(timeout, func() { expire(ErrRoundTimeout, time.Now()) })
+	}
+	if ctx != nil && ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				expire(ctx.Err(), time.Time{})
+			case <-w.done:
+			}
+		}()
+	}
+	return w
+}
+
+// reset restarts the time limit for a new round.
+func (w *watchdog) reset() {
+	if w == nil || w.timer == nil {
+		return
+	}
+	w.deadline = time.Now().Add(w.timeout)
+	w.timer.Reset(w.timeout)
+}
+
+// expired returns true if the expiry of the timer at time t is still relevant,
+// as opposed to belonging to a round which has since finished.
+//
+// A zero t indicates that the context is done, which is always relevant.
+func (w *watchdog) expired(t time.Time) bool {
+	return t.IsZero() || !t.Before(w.deadline)
+}
+
+// stop releases the resources used by the watchdog.
+func (w *watchdog) stop() {
+	if w == nil {
+		return
+	}
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	select {
+	case <-w.done:
+	default:
+		close(w.done)
+	}
+}
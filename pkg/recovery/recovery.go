@@ -0,0 +1,114 @@
+// Package recovery reconstructs a full private key from a quorum of threshold shares.
+//
+// This is meant for offline disaster recovery. Once reconstructed, the key is no longer
+// protected by the threshold scheme, and should be handled accordingly.
+package recovery
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/base58"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// ShareError indicates that the share of a given party is inconsistent with the public data.
+type ShareError struct {
+	// ID is the party holding the inconsistent share.
+	ID party.ID
+	// Reason describes the inconsistency.
+	Reason string
+}
+
+func (e *ShareError) Error() string {
+	return fmt.Sprintf("recovery: share of party %s: %s", e.ID, e.Reason)
+}
+
+// PrivateKey is a reconstructed private key.
+type PrivateKey struct {
+	// Scalar is the secret x, such that X = x⋅G is the shared public key.
+	Scalar curve.Scalar
+}
+
+// Bytes returns the Big Endian encoding of the key.
+func (k *PrivateKey) Bytes() []byte {
+	data, _ := k.Scalar.MarshalBinary()
+	return data
+}
+
+// Hex returns the hexadecimal encoding of Bytes.
+func (k *PrivateKey) Hex() string {
+	return hex.EncodeToString(k.Bytes())
+}
+
+// WIF returns the Wallet Import Format encoding of the key, for a compressed public key.
+//
+// The mainnet prefix 0x80 is used if mainnet is true, otherwise the testnet prefix 0xef.
+// This only makes sense for curve.Secp256k1 keys, and returns an error for other curves.
+//
+// See: https://en.bitcoin.it/wiki/Wallet_import_format
+func (k *PrivateKey) WIF(mainnet bool) (string, error) {
+	if _, ok := k.Scalar.(*curve.Secp256k1Scalar); !ok {
+		return "", errors.New("recovery: WIF is only defined for secp256k1 keys")
+	}
+	prefix := byte(0xef)
+	if mainnet {
+		prefix = 0x80
+	}
+	data := make([]byte, 0, 1+32+1)
+	data = append(data, prefix)
+	data = append(data, k.Bytes()...)
+	data = append(data, 0x01)
+	return base58.CheckEncode(data), nil
+}
+
+// Reconstruct interpolates the secret from Shamir shares.
+//
+// Each share must match its public share x⋅G, and the result must match publicKey.
+// At least threshold+1 shares are needed, but this can only be detected by comparing against publicKey.
+// The IDs of the shares must be distinct evaluation points, see party.ID.Scalar.
+func Reconstruct(group curve.Curve, shares map[party.ID]curve.Scalar, publicShares map[party.ID]curve.Point, publicKey curve.Point) (*PrivateKey, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("recovery: no shares given")
+	}
+	if publicKey == nil {
+		return nil, errors.New("recovery: no public key given")
+	}
+	partyIDs := make([]party.ID, 0, len(shares))
+	// indices maps the evaluation point of each party to its ID, since different IDs can have the same one.
+	indices := make(map[string]party.ID, len(shares))
+	for id, share := range shares {
+		index := id.Scalar(group)
+		if index.IsZero() {
+			return nil, &ShareError{ID: id, Reason: "party ID is zero"}
+		}
+		indexBytes, _ := index.MarshalBinary()
+		if other, ok := indices[string(indexBytes)]; ok {
+			return nil, &ShareError{ID: id, Reason: fmt.Sprintf("party ID is a duplicate of %s", other)}
+		}
+		indices[string(indexBytes)] = id
+		if share == nil {
+			return nil, &ShareError{ID: id, Reason: "missing secret share"}
+		}
+		public, ok := publicShares[id]
+		if !ok || public == nil {
+			return nil, &ShareError{ID: id, Reason: "missing public share"}
+		}
+		if !share.ActOnBase().Equal(public) {
+			return nil, &ShareError{ID: id, Reason: "secret share doesn't match public share"}
+		}
+		partyIDs = append(partyIDs, id)
+	}
+
+	secret := group.NewScalar()
+	for id, lagrange := range polynomial.Lagrange(group, partyIDs) {
+		secret.Add(lagrange.Mul(shares[id]))
+	}
+	if !secret.ActOnBase().Equal(publicKey) {
+		return nil, errors.New("recovery: reconstructed key doesn't match the public key, not enough shares were given")
+	}
+	return &PrivateKey{Scalar: secret}, nil
+}
@@ -0,0 +1,120 @@
+package recovery
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+func TestWIF(t *testing.T) {
+	data, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	scalar := curve.Secp256k1{}.NewScalar()
+	require.NoError(t, scalar.UnmarshalBinary(data))
+	key := &PrivateKey{Scalar: scalar}
+
+	assert.Equal(t, "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d", key.Hex())
+	wif, err := key.WIF(true)
+	require.NoError(t, err)
+	assert.Equal(t, "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617", wif)
+
+	_, err = (&PrivateKey{Scalar: curve.Ed25519{}.NewScalar()}).WIF(true)
+	assert.Error(t, err)
+}
+
+// shamirShares shares secret with a polynomial of degree threshold, returning the shares and public shares of ids.
+func shamirShares(secret curve.Scalar, threshold int, ids []party.ID) (map[party.ID]curve.Scalar, map[party.ID]curve.Point) {
+	group := secret.Curve()
+	f := polynomial.NewPolynomial(rand.Reader, group, threshold, secret)
+	shares := make(map[party.ID]curve.Scalar, len(ids))
+	publicShares := make(map[party.ID]curve.Point, len(ids))
+	for _, id := range ids {
+		shares[id] = f.Evaluate(id.Scalar(group))
+		publicShares[id] = shares[id].ActOnBase()
+	}
+	return shares, publicShares
+}
+
+func TestReconstruct(t *testing.T) {
+	group := curve.Secp256k1{}
+	secret := sample.Scalar(rand.Reader, group)
+	publicKey := secret.ActOnBase()
+	shares, publicShares := shamirShares(secret, 1, []party.ID{"a", "b", "c"})
+
+	key, err := Reconstruct(group, map[party.ID]curve.Scalar{"a": shares["a"], "c": shares["c"]}, publicShares, publicKey)
+	require.NoError(t, err)
+	assert.True(t, secret.Equal(key.Scalar))
+
+	_, err = Reconstruct(group, nil, publicShares, publicKey)
+	assert.Error(t, err)
+	_, err = Reconstruct(group, shares, publicShares, nil)
+	assert.Error(t, err)
+}
+
+func TestReconstructTooFewShares(t *testing.T) {
+	group := curve.Secp256k1{}
+	secret := sample.Scalar(rand.Reader, group)
+	shares, publicShares := shamirShares(secret, 2, []party.ID{"a", "b", "c"})
+	delete(shares, "b")
+
+	// every share is valid, so only the result can be rejected
+	_, err := Reconstruct(group, shares, publicShares, secret.ActOnBase())
+	require.Error(t, err)
+	var shareErr *ShareError
+	assert.False(t, errors.As(err, &shareErr))
+}
+
+func TestReconstructWrongShare(t *testing.T) {
+	group := curve.Secp256k1{}
+	secret := sample.Scalar(rand.Reader, group)
+	shares, publicShares := shamirShares(secret, 1, []party.ID{"a", "b", "c"})
+	shares["b"] = sample.Scalar(rand.Reader, group)
+
+	_, err := Reconstruct(group, shares, publicShares, secret.ActOnBase())
+	var shareErr *ShareError
+	require.ErrorAs(t, err, &shareErr)
+	assert.Equal(t, party.ID("b"), shareErr.ID)
+}
+
+func TestReconstructMissingPublicShare(t *testing.T) {
+	group := curve.Secp256k1{}
+	secret := sample.Scalar(rand.Reader, group)
+	shares, publicShares := shamirShares(secret, 1, []party.ID{"a", "b", "c"})
+	delete(publicShares, "c")
+
+	_, err := Reconstruct(group, shares, publicShares, secret.ActOnBase())
+	var shareErr *ShareError
+	require.ErrorAs(t, err, &shareErr)
+	assert.Equal(t, party.ID("c"), shareErr.ID)
+
+	publicShares["c"] = nil
+	_, err = Reconstruct(group, shares, publicShares, secret.ActOnBase())
+	require.ErrorAs(t, err, &shareErr)
+	assert.Equal(t, party.ID("c"), shareErr.ID)
+}
+
+func TestReconstructDuplicateIDs(t *testing.T) {
+	group := curve.Secp256k1{}
+	secret := sample.Scalar(rand.Reader, group)
+	// "\x00a" is a different ID than "a", but both are evaluated at the same point
+	shares, publicShares := shamirShares(secret, 1, []party.ID{"a", "\x00a"})
+
+	_, err := Reconstruct(group, shares, publicShares, secret.ActOnBase())
+	var shareErr *ShareError
+	require.ErrorAs(t, err, &shareErr)
+	assert.Contains(t, []party.ID{"a", "\x00a"}, shareErr.ID)
+
+	// an ID evaluated at 0 would hold the secret itself
+	shares, publicShares = shamirShares(secret, 1, []party.ID{"a"})
+	shares["\x00"], publicShares["\x00"] = secret, secret.ActOnBase()
+	_, err = Reconstruct(group, shares, publicShares, secret.ActOnBase())
+	require.ErrorAs(t, err, &shareErr)
+	assert.Equal(t, party.ID("\x00"), shareErr.ID)
+}
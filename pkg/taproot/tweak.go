@@ -0,0 +1,49 @@
+package taproot
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// TweakScalar calculates the scalar t = hash_TapTweak(P || merkleRoot), by which an internal key P
+// is tweaked into the output key of a Taproot output.
+//
+// merkleRoot is the 32 byte root of the script tree, or empty if the output has no script path,
+// as recommended in BIP-86.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki#constructing-and-spending-taproot-outputs
+func TweakScalar(internal PublicKey, merkleRoot []byte) (*curve.Secp256k1Scalar, error) {
+	if len(internal) != PublicKeyLength {
+		return nil, fmt.Errorf("invalid public key length: %d", len(internal))
+	}
+	if len(merkleRoot) != 0 && len(merkleRoot) != 32 {
+		return nil, fmt.Errorf("invalid merkle root length: %d", len(merkleRoot))
+	}
+	t := new(curve.Secp256k1Scalar)
+	// This fails if t is not smaller than the order of the curve.
+	if err := t.UnmarshalBinary(TaggedHash("TapTweak", internal, merkleRoot)); err != nil {
+		return nil, fmt.Errorf("invalid tweak: %w", err)
+	}
+	return t, nil
+}
+
+// Tweak calculates the output key Q = P + t⋅G of a Taproot output with internal key P,
+// where t is given by TweakScalar.
+//
+// Signatures for spending the output with the key path must verify against Q.
+func (pk PublicKey) Tweak(merkleRoot []byte) (PublicKey, error) {
+	t, err := TweakScalar(pk, merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	P, err := curve.Secp256k1{}.LiftX(pk)
+	if err != nil {
+		return nil, err
+	}
+	Q := P.Add(t.ActOnBase()).(*curve.Secp256k1Point)
+	if Q.IsIdentity() {
+		return nil, fmt.Errorf("invalid tweak: output key is the identity")
+	}
+	return PublicKey(Q.XBytes()), nil
+}
@@ -0,0 +1,60 @@
+package taproot
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTweakBIP86(t *testing.T) {
+	// The first receiving address of the test vectors of BIP-86, m/86'/0'/0'/0/0.
+	//
+	// See: https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki#test-vectors
+	internal, err := hex.DecodeString("cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115")
+	require.NoError(t, err)
+	output, err := PublicKey(internal).Tweak(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c", hex.EncodeToString(output))
+
+	_, err = PublicKey(internal).Tweak(make([]byte, 31))
+	assert.Error(t, err, "merkle root should be 32 bytes")
+	_, err = PublicKey(internal[1:]).Tweak(nil)
+	assert.Error(t, err, "public key should be 32 bytes")
+}
+
+func TestTweakBIP341(t *testing.T) {
+	// Test vectors of the scriptPubKey section of bip-0341/wallet-test-vectors.json.
+	vectors := []struct {
+		internal, merkleRoot, tweak, output string
+	}{
+		{
+			internal: "d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+			tweak:    "b86e7be8f39bab32a6f2c0443abbc210f0edac0e2c53d501b36b64437d9c6c70",
+			output:   "53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343",
+		},
+		{
+			internal:   "187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27",
+			merkleRoot: "5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21",
+			tweak:      "cbd8679ba636c1110ea247542cfbd964131a6be84f873f7f3b62a777528ed001",
+			output:     "147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3",
+		},
+	}
+	for _, v := range vectors {
+		internal, err := hex.DecodeString(v.internal)
+		require.NoError(t, err)
+		merkleRoot, err := hex.DecodeString(v.merkleRoot)
+		require.NoError(t, err)
+
+		tweak, err := TweakScalar(internal, merkleRoot)
+		require.NoError(t, err)
+		tweakBytes, err := tweak.MarshalBinary()
+		require.NoError(t, err)
+		assert.Equal(t, v.tweak, hex.EncodeToString(tweakBytes))
+
+		output, err := PublicKey(internal).Tweak(merkleRoot)
+		require.NoError(t, err)
+		assert.Equal(t, v.output, hex.EncodeToString(output))
+	}
+}
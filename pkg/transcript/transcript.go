@@ -0,0 +1,178 @@
+// Package transcript records the messages exchanged by a protocol.Handler, so that a failed execution
+// can be replayed offline, to reproduce the error and the culprits it blamed.
+//
+// A transcript is a sequence of CBOR encoded Entry values, written by a Recorder and read back with Read.
+package transcript
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// Direction indicates whether a message was received or sent by the recorded handler.
+type Direction uint8
+
+const (
+	// In is a message given to the handler with Accept.
+	In Direction = iota + 1
+	// Out is a message produced by the handler on its Listen channel.
+	Out
+)
+
+// String implements fmt.Stringer.
+func (d Direction) String() string {
+	switch d {
+	case In:
+		return "in"
+	case Out:
+		return "out"
+	default:
+		return fmt.Sprintf("Direction(%d)", uint8(d))
+	}
+}
+
+// Entry is a message recorded by a Recorder.
+type Entry struct {
+	// Time at which the message was given to, or produced by the handler.
+	Time      time.Time
+	Direction Direction
+	Message   *protocol.Message
+}
+
+// String implements fmt.Stringer.
+func (e Entry) String() string {
+	return fmt.Sprintf("%s %-3s %s", e.Time.Format(time.RFC3339Nano), e.Direction, e.Message)
+}
+
+var _ protocol.Handler = (*Recorder)(nil)
+
+// Recorder is a protocol.Handler which writes all messages going in and out of another Handler to a transcript.
+//
+// It is used in place of the wrapped handler, which must not be used directly anymore.
+type Recorder struct {
+	h   protocol.Handler
+	out chan *protocol.Message
+
+	mtx sync.Mutex
+	enc *cbor.Encoder
+	err error
+}
+
+// NewRecorder returns a Recorder for h, which writes the transcript to w.
+//
+// Messages already produced by h, such as those of the first round, are recorded as well.
+// Writes to w are serialized, so w does not need to be safe for concurrent use.
+func NewRecorder(h protocol.Handler, w io.Writer) *Recorder {
+	in := h.Listen()
+	r := &Recorder{
+		h:   h,
+		out: make(chan *protocol.Message, cap(in)),
+		enc: cbor.NewEncoder(w),
+	}
+	go func() {
+		for msg := range in {
+			r.record(Out, msg)
+			r.out <- msg
+		}
+		close(r.out)
+	}()
+	return r
+}
+
+// record writes msg to the transcript.
+// The first error is kept, and nothing more is written after it.
+func (r *Recorder) record(direction Direction, msg *protocol.Message) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.err != nil {
+		return
+	}
+	if err := r.enc.Encode(&Entry{Time: time.Now(), Direction: direction, Message: msg}); err != nil {
+		r.err = fmt.Errorf("transcript: failed to record message: %w", err)
+	}
+}
+
+// Err returns the first error which occurred while writing the transcript, if any.
+// The transcript is incomplete if it is not nil.
+func (r *Recorder) Err() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.err
+}
+
+// Result implements protocol.Handler.
+func (r *Recorder) Result() (interface{}, error) { return r.h.Result() }
+
+// Listen implements protocol.Handler.
+func (r *Recorder) Listen() <-chan *protocol.Message { return r.out }
+
+// Stop implements protocol.Handler.
+func (r *Recorder) Stop() { r.h.Stop() }
+
+// CanAccept implements protocol.Handler.
+func (r *Recorder) CanAccept(msg *protocol.Message) bool { return r.h.CanAccept(msg) }
+
+// Accept implements protocol.Handler.
+//
+// Every message is recorded, even if the handler does not accept it, so that it is given the same input on replay.
+func (r *Recorder) Accept(msg *protocol.Message) {
+	r.record(In, msg)
+	r.h.Accept(msg)
+}
+
+// Read returns all entries of the transcript in r.
+func Read(r io.Reader) ([]Entry, error) {
+	dec := cbor.NewDecoder(r)
+	var entries []Entry
+	for {
+		var e Entry
+		err := dec.Decode(&e)
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, fmt.Errorf("transcript: failed to read entry %d: %w", len(entries), err)
+		}
+		if e.Message == nil {
+			return entries, fmt.Errorf("transcript: entry %d has no message", len(entries))
+		}
+		entries = append(entries, e)
+	}
+}
+
+// Replay gives the incoming messages of entries to h, in the recorded order, and returns the result of h.
+//
+// h must be a fresh handler for the same party, protocol, configuration and session ID as the recorded one,
+// and with the same options (for instance the same Authenticator and Encryptor).
+// The messages produced by h are discarded.
+// If the transcript ends before the protocol does, the error is the one of an unfinished protocol.
+//
+// The error returned is then the one of the recorded execution, with the same culprits,
+// as long as the messages h sends are the same as those that were recorded.
+// Since the other parties' messages may depend on them, the local party must use the same randomness
+// as in the recorded execution for the replay to be exact, which is given to both with protocol.WithRand.
+func Replay(h protocol.Handler, entries []Entry) (interface{}, error) {
+	done := make(chan struct{})
+	go func() {
+		for range h.Listen() {
+		}
+		close(done)
+	}()
+	for _, e := range entries {
+		if e.Direction != In {
+			continue
+		}
+		h.Accept(e.Message)
+	}
+	result, err := h.Result()
+	// if the transcript ends before the protocol does, the handler is still waiting for messages
+	h.Stop()
+	<-done
+	return result, err
+}
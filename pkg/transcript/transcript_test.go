@@ -0,0 +1,132 @@
+package transcript_test
+
+import (
+	"bytes"
+	"errors"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transcript"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+// relay delivers the messages of all handlers, after passing them through tamper.
+func relay(handlers map[party.ID]protocol.Handler, tamper func(*protocol.Message)) {
+	done := make(chan struct{})
+	for _, h := range handlers {
+		go func(h protocol.Handler) {
+			for msg := range h.Listen() {
+				tamper(msg)
+				for id, other := range handlers {
+					if msg.IsFor(id) {
+						other.Accept(msg)
+					}
+				}
+			}
+			done <- struct{}{}
+		}(h)
+	}
+	for range handlers {
+		<-done
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+
+	var buf bytes.Buffer
+	handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, partyIDs, 1), sessionID)
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	recorder := transcript.NewRecorder(handlers["a"], &buf)
+	handlers["a"] = recorder
+	// b sends an invalid message in the first broadcast round, which does not depend on the messages of a
+	relay(handlers, func(msg *protocol.Message) {
+		if msg.From == "b" && msg.Broadcast && msg.RoundNumber == 2 {
+			msg.Data = []byte{0}
+		}
+	})
+	require.NoError(t, recorder.Err())
+	_, recordedErr := recorder.Result()
+	require.Error(t, recordedErr)
+
+	entries, err := transcript.Read(&buf)
+	require.NoError(t, err)
+	var in, out int
+	for i, e := range entries {
+		if i > 0 {
+			assert.False(t, e.Time.Before(entries[i-1].Time))
+		}
+		switch e.Direction {
+		case transcript.In:
+			assert.NotEqual(t, party.ID("a"), e.Message.From)
+			in++
+		case transcript.Out:
+			assert.Equal(t, party.ID("a"), e.Message.From)
+			out++
+		}
+	}
+	assert.NotZero(t, in)
+	assert.NotZero(t, out)
+
+	h, err := protocol.NewMultiHandler(frost.Keygen(group, "a", partyIDs, 1), sessionID)
+	require.NoError(t, err)
+	_, err = transcript.Replay(h, entries)
+	require.Error(t, err)
+	assert.Equal(t, recordedErr.Error(), err.Error())
+	var protocolErr protocol.Error
+	require.True(t, errors.As(err, &protocolErr))
+	assert.Equal(t, []party.ID{"b"}, protocolErr.Culprits)
+}
+
+func TestReplayRand(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+	// a deterministic source of randomness, which is not secure outside of tests
+	seeded := func() protocol.StartOption { return protocol.WithRand(mrand.New(mrand.NewSource(1))) }
+
+	var buf bytes.Buffer
+	handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+	for _, id := range partyIDs {
+		var opts []protocol.StartOption
+		if id == "a" {
+			opts = append(opts, seeded())
+		}
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, partyIDs, 1, opts...), sessionID)
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	recorder := transcript.NewRecorder(handlers["a"], &buf)
+	handlers["a"] = recorder
+	relay(handlers, func(*protocol.Message) {})
+	require.NoError(t, recorder.Err())
+	recorded, err := recorder.Result()
+	require.NoError(t, err)
+
+	entries, err := transcript.Read(&buf)
+	require.NoError(t, err)
+	h, err := protocol.NewMultiHandler(frost.Keygen(group, "a", partyIDs, 1, seeded()), sessionID)
+	require.NoError(t, err)
+	replayed, err := transcript.Replay(h, entries)
+	require.NoError(t, err)
+	assert.True(t, recorded.(*frost.Config).PublicKey.Equal(replayed.(*frost.Config).PublicKey))
+	assert.True(t, recorded.(*frost.Config).PrivateShare.Equal(replayed.(*frost.Config).PrivateShare))
+}
+
+func TestReplayIncomplete(t *testing.T) {
+	h, err := protocol.NewMultiHandler(frost.Keygen(curve.Secp256k1{}, "a", party.IDSlice{"a", "b"}, 1), nil)
+	require.NoError(t, err)
+	_, err = transcript.Replay(h, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,237 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+const (
+	// maxMessageSize is the largest message accepted by the Server.
+	maxMessageSize = 32 << 20
+	// pollTimeout is how long the Server waits for a message before answering a GET with no content.
+	pollTimeout = 30 * time.Second
+	// retryDelay is how long the HTTPClient waits before fetching a message again after a failed request.
+	retryDelay = 100 * time.Millisecond
+)
+
+// Server is an HTTP relay, which stores the messages sent to each party of each session until that party fetches them.
+//
+// A message for a party is sent with a POST to /{session}/{party}, with the binary encoding of the message as body.
+// The party fetches its messages in order with a GET to /{session}/{party}?next={n}, where n is the number of
+// messages it received so far. This answers with message number n, counting from 0, waiting for it to arrive,
+// or with 204 No Content if it didn't after some time.
+// The messages before n are then dropped, while message n is kept until a later GET acknowledges it,
+// so that a party which didn't receive an answer asks for the same message again.
+// A GET is answered with 409 Conflict if n is before an acknowledged message, or after the last one.
+// The messages of a session are dropped once none was sent or fetched for an hour,
+// and a POST is answered with 429 Too Many Requests when too many messages are waiting for a party.
+//
+// The Server does not check who sends or fetches messages,
+// so the handlers should use protocol.WithAuthenticator and protocol.WithEncryption when it is not trusted.
+type Server struct {
+	boxes mailboxes
+}
+
+// NewServer returns a Server with no stored messages.
+func NewServer() *Server {
+	return &Server{}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	session, id, err := parsePath(r.URL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		var msg protocol.Message
+		if err = msg.UnmarshalBinary(data); err != nil {
+			http.Error(w, fmt.Sprintf("invalid message: %s", err), http.StatusBadRequest)
+			return
+		}
+		if !msg.IsFor(id) {
+			http.Error(w, fmt.Sprintf("message is not for %s", id), http.StatusBadRequest)
+			return
+		}
+		if err = s.boxes.put(session, id, &msg); err != nil {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodGet:
+		var next uint64
+		if n := r.URL.Query().Get("next"); n != "" {
+			if next, err = strconv.ParseUint(n, 10, 64); err != nil {
+				http.Error(w, fmt.Sprintf("invalid next: %s", err), http.StatusBadRequest)
+				return
+			}
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), pollTimeout)
+		defer cancel()
+		msg, err := s.boxes.take(ctx, session, id, next)
+		if errors.Is(err, errInvalidCursor) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		data, err := msg.MarshalBinary()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(data)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// parsePath returns the session and party of a request to /{session}/{party}.
+func parsePath(u *url.URL) (string, party.ID, error) {
+	parts := strings.Split(strings.TrimPrefix(u.EscapedPath(), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.New("path must be /{session}/{party}")
+	}
+	session, err := url.PathUnescape(parts[0])
+	if err != nil {
+		return "", "", err
+	}
+	id, err := url.PathUnescape(parts[1])
+	if err != nil {
+		return "", "", err
+	}
+	return session, party.ID(id), nil
+}
+
+// HTTPClient is a Transport which relays messages through a Server.
+type HTTPClient struct {
+	url     string
+	session string
+	self    party.ID
+	parties party.IDSlice
+	client  *http.Client
+	// next is the number of the next message to receive.
+	next uint64
+}
+
+// NewHTTPClient returns the Transport used by self in session, among the given parties,
+// through the Server at baseURL.
+//
+// If client is nil, http.DefaultClient is used.
+func NewHTTPClient(baseURL, session string, self party.ID, parties party.IDSlice, client *http.Client) *HTTPClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPClient{
+		url:     strings.TrimSuffix(baseURL, "/"),
+		session: session,
+		self:    self,
+		parties: parties.Copy(),
+		client:  client,
+	}
+}
+
+// mailboxURL returns the URL of the mailbox of id.
+func (c *HTTPClient) mailboxURL(id party.ID) string {
+	return c.url + "/" + url.PathEscape(c.session) + "/" + url.PathEscape(string(id))
+}
+
+// Send implements Transport.
+func (c *HTTPClient) Send(ctx context.Context, msg *protocol.Message) error {
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	for _, id := range recipients(msg, c.parties) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.mailboxURL(id), bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/octet-stream")
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			return fmt.Errorf("relay: %s", resp.Status)
+		}
+	}
+	return nil
+}
+
+// wait returns after retryDelay, or with an error if ctx is done before.
+func wait(ctx context.Context) error {
+	t := time.NewTimer(retryDelay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Receive implements Transport.
+//
+// A request which fails, or whose answer is cut off, is sent again after some time, until ctx is done.
+func (c *HTTPClient) Receive(ctx context.Context) (*protocol.Message, error) {
+	for {
+		u := c.mailboxURL(c.self) + "?next=" + strconv.FormatUint(c.next, 10)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.client.Do(req)
+		if err != nil {
+			if err = wait(ctx); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxMessageSize))
+		_ = resp.Body.Close()
+		if err != nil {
+			// the Server keeps the message until we ask for the next one
+			if err = wait(ctx); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			var msg protocol.Message
+			if err = msg.UnmarshalBinary(data); err != nil {
+				return nil, fmt.Errorf("relay: invalid message: %w", err)
+			}
+			c.next++
+			return &msg, nil
+		case http.StatusNoContent:
+			// no message yet, poll again
+		default:
+			return nil, fmt.Errorf("relay: %s", resp.Status)
+		}
+	}
+}
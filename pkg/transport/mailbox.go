@@ -0,0 +1,199 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+const (
+	// maxMailboxSize is the largest number of messages waiting to be received by a party in a session.
+	maxMailboxSize = 1 << 14
+	// sessionExpiry is how long the messages of a session are kept after a message was last added or received.
+	sessionExpiry = time.Hour
+	// sweepInterval is how often expired sessions are looked for.
+	sweepInterval = time.Minute
+)
+
+var (
+	// errMailboxFull is returned when a party has too many messages waiting to be received.
+	errMailboxFull = errors.New("transport: mailbox is full")
+	// errInvalidCursor is returned when a party acknowledges messages it can't have received.
+	errInvalidCursor = errors.New("transport: invalid cursor")
+)
+
+// mailboxes stores the messages for each party of each session, until they are acknowledged.
+//
+// Each message is numbered, and a party asks for the message following the ones it received,
+// which acknowledges these, so that a message lost on its way is sent again.
+// Empty mailboxes are removed once nobody waits on them,
+// and sessions are removed with all their messages once they expire.
+type mailboxes struct {
+	sessions map[string]*session
+	// lastSweep is the last time expired sessions were removed.
+	lastSweep time.Time
+	// now returns the current time, and is only replaced in tests.
+	now func() time.Time
+	mtx sync.Mutex
+}
+
+type session struct {
+	boxes map[party.ID]*mailbox
+	// lastUsed is the last time a message was added or received.
+	lastUsed time.Time
+}
+
+type mailbox struct {
+	messages []*protocol.Message
+	// first is the number of messages[0], which is set by the first call to take,
+	// since the party may have received messages from a previous mailbox.
+	first uint64
+	// numbered is true once first was set.
+	numbered bool
+	// ready is closed and replaced whenever a message is added.
+	ready chan struct{}
+	// waiters is the number of calls to take waiting on ready.
+	waiters int
+}
+
+// time returns the current time.
+func (m *mailboxes) time() time.Time {
+	if m.now != nil {
+		return m.now()
+	}
+	return time.Now()
+}
+
+// get returns the mailbox for id in sessionID, creating it if necessary.
+// m.mtx must be held.
+func (m *mailboxes) get(sessionID string, id party.ID) (*session, *mailbox) {
+	if m.sessions == nil {
+		m.sessions = make(map[string]*session)
+	}
+	s, ok := m.sessions[sessionID]
+	if !ok {
+		s = &session{
+			boxes:    make(map[party.ID]*mailbox),
+			lastUsed: m.time(),
+		}
+		m.sessions[sessionID] = s
+	}
+	box, ok := s.boxes[id]
+	if !ok {
+		box = &mailbox{ready: make(chan struct{})}
+		s.boxes[id] = box
+	}
+	return s, box
+}
+
+// release removes box from sessionID if it is empty, and nobody waits on it.
+// m.mtx must be held.
+func (m *mailboxes) release(sessionID string, id party.ID, box *mailbox) {
+	s, ok := m.sessions[sessionID]
+	if !ok || s.boxes[id] != box || len(box.messages) > 0 || box.waiters > 0 {
+		return
+	}
+	delete(s.boxes, id)
+	if len(s.boxes) == 0 {
+		delete(m.sessions, sessionID)
+	}
+}
+
+// sweep removes the sessions which have expired.
+// The parties waiting on them keep waiting, on new empty mailboxes.
+// m.mtx must be held.
+func (m *mailboxes) sweep() {
+	now := m.time()
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for sessionID, s := range m.sessions {
+		if now.Sub(s.lastUsed) < sessionExpiry {
+			continue
+		}
+		for _, box := range s.boxes {
+			close(box.ready)
+		}
+		delete(m.sessions, sessionID)
+	}
+}
+
+// put adds msg to the mailbox of id in sessionID.
+func (m *mailboxes) put(sessionID string, id party.ID, msg *protocol.Message) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.sweep()
+	s, box := m.get(sessionID, id)
+	if len(box.messages) >= maxMailboxSize {
+		return errMailboxFull
+	}
+	box.messages = append(box.messages, msg)
+	s.lastUsed = m.time()
+	close(box.ready)
+	box.ready = make(chan struct{})
+	return nil
+}
+
+// ack removes the messages of box numbered before next.
+func (box *mailbox) ack(next uint64) error {
+	if !box.numbered {
+		box.first, box.numbered = next, true
+	}
+	if next < box.first || next-box.first > uint64(len(box.messages)) {
+		return errInvalidCursor
+	}
+	n := next - box.first
+	for i := uint64(0); i < n; i++ {
+		box.messages[i] = nil
+	}
+	box.messages = box.messages[n:]
+	box.first = next
+	return nil
+}
+
+// take returns message number next in the mailbox of id in sessionID,
+// waiting for it to arrive if necessary.
+//
+// The messages numbered before next are removed, since the party received them.
+// The returned message is only removed by a later call, so that it is returned again
+// if it didn't reach the party.
+func (m *mailboxes) take(ctx context.Context, sessionID string, id party.ID, next uint64) (*protocol.Message, error) {
+	for {
+		m.mtx.Lock()
+		s, box := m.get(sessionID, id)
+		if err := box.ack(next); err != nil {
+			m.release(sessionID, id, box)
+			m.mtx.Unlock()
+			return nil, err
+		}
+		if len(box.messages) > 0 {
+			msg := box.messages[0]
+			s.lastUsed = m.time()
+			m.mtx.Unlock()
+			return msg, nil
+		}
+		box.waiters++
+		ready := box.ready
+		m.mtx.Unlock()
+
+		var err error
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+
+		m.mtx.Lock()
+		box.waiters--
+		m.release(sessionID, id, box)
+		m.mtx.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
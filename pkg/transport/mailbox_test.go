@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+func TestMailboxRemovedWhenEmpty(t *testing.T) {
+	var m mailboxes
+	ctx := context.Background()
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	require.NoError(t, m.put("s", "a", &protocol.Message{RoundNumber: 1}))
+	require.NoError(t, m.put("s", "a", &protocol.Message{RoundNumber: 2}))
+	msg, err := m.take(ctx, "s", "a", 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, msg.RoundNumber)
+	msg, err = m.take(ctx, "s", "a", 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, msg.RoundNumber)
+	// the last message is kept until it is acknowledged
+	assert.Len(t, m.sessions, 1)
+	_, err = m.take(canceled, "s", "a", 2)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, m.sessions)
+
+	// a party waiting for a message keeps its mailbox
+	received := make(chan *protocol.Message)
+	go func() {
+		msg, _ := m.take(ctx, "s", "b", 0)
+		received <- msg
+	}()
+	require.Eventually(t, func() bool {
+		m.mtx.Lock()
+		defer m.mtx.Unlock()
+		return len(m.sessions) == 1
+	}, time.Second, time.Millisecond)
+	require.NoError(t, m.put("s", "b", &protocol.Message{RoundNumber: 3}))
+	assert.EqualValues(t, 3, (<-received).RoundNumber)
+	_, err = m.take(canceled, "s", "b", 1)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, m.sessions)
+
+	// giving up also removes it
+	_, err = m.take(canceled, "s", "c", 0)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, m.sessions)
+}
+
+func TestMailboxCursor(t *testing.T) {
+	var m mailboxes
+	ctx := context.Background()
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	require.NoError(t, m.put("s", "a", &protocol.Message{RoundNumber: 1}))
+	require.NoError(t, m.put("s", "a", &protocol.Message{RoundNumber: 2}))
+
+	// a message is returned again until the next one is asked for
+	for i := 0; i < 2; i++ {
+		msg, err := m.take(ctx, "s", "a", 0)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, msg.RoundNumber)
+	}
+	msg, err := m.take(ctx, "s", "a", 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, msg.RoundNumber)
+
+	// acknowledged messages are gone, and later ones were never sent
+	_, err = m.take(ctx, "s", "a", 0)
+	assert.ErrorIs(t, err, errInvalidCursor)
+	_, err = m.take(ctx, "s", "a", 3)
+	assert.ErrorIs(t, err, errInvalidCursor)
+
+	// once the mailbox was removed, a new one continues from the party's count
+	_, err = m.take(canceled, "s", "a", 2)
+	assert.ErrorIs(t, err, context.Canceled)
+	require.Empty(t, m.sessions)
+	require.NoError(t, m.put("s", "a", &protocol.Message{RoundNumber: 3}))
+	msg, err = m.take(ctx, "s", "a", 2)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, msg.RoundNumber)
+}
+
+func TestMailboxExpiry(t *testing.T) {
+	now := time.Now()
+	m := mailboxes{now: func() time.Time { return now }}
+
+	require.NoError(t, m.put("old", "a", &protocol.Message{}))
+	now = now.Add(sessionExpiry / 2)
+	require.NoError(t, m.put("new", "a", &protocol.Message{}))
+	now = now.Add(sessionExpiry / 2)
+	require.NoError(t, m.put("new", "b", &protocol.Message{}))
+	assert.NotContains(t, m.sessions, "old")
+	assert.Contains(t, m.sessions, "new")
+}
+
+func TestMailboxFull(t *testing.T) {
+	var m mailboxes
+	for i := 0; i < maxMailboxSize; i++ {
+		require.NoError(t, m.put("s", "a", &protocol.Message{}))
+	}
+	assert.ErrorIs(t, m.put("s", "a", &protocol.Message{}), errMailboxFull)
+	// other parties are not affected
+	assert.NoError(t, m.put("s", "b", &protocol.Message{}))
+}
@@ -0,0 +1,61 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// Memory connects parties running in the same process.
+//
+// Messages are queued until the recipient receives them, so parties may start a session at different times.
+// The same Memory can be used for any number of sessions.
+// The messages of a session are dropped once none was sent or received for an hour.
+type Memory struct {
+	boxes mailboxes
+}
+
+// NewMemory returns an empty Memory.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Transport returns the Transport used by self in session, among the given parties.
+func (m *Memory) Transport(session string, self party.ID, parties party.IDSlice) Transport {
+	return &memoryTransport{
+		memory:  m,
+		session: session,
+		self:    self,
+		parties: parties.Copy(),
+	}
+}
+
+type memoryTransport struct {
+	memory  *Memory
+	session string
+	self    party.ID
+	parties party.IDSlice
+	// next is the number of the next message to receive.
+	next uint64
+}
+
+// Send implements Transport.
+func (t *memoryTransport) Send(_ context.Context, msg *protocol.Message) error {
+	for _, id := range recipients(msg, t.parties) {
+		if err := t.memory.boxes.put(t.session, id, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Receive implements Transport.
+func (t *memoryTransport) Receive(ctx context.Context) (*protocol.Message, error) {
+	msg, err := t.memory.boxes.take(ctx, t.session, t.self, t.next)
+	if err != nil {
+		return nil, err
+	}
+	t.next++
+	return msg, nil
+}
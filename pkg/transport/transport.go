@@ -0,0 +1,88 @@
+// Package transport delivers protocol messages between the parties of a protocol execution.
+//
+// A Transport is created for each party in each session, and Run drives a protocol.Handler over it
+// until the protocol finishes.
+// Memory connects parties within the same process, and Server and HTTPClient relay messages over HTTP.
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// Transport sends and receives the messages of a single party, in a single session.
+type Transport interface {
+	// Send delivers msg to msg.To, or to all other parties of the session if msg.To is empty.
+	Send(ctx context.Context, msg *protocol.Message) error
+	// Receive blocks until a message is available for this party, or ctx is done.
+	Receive(ctx context.Context) (*protocol.Message, error)
+}
+
+// Run forwards the messages of h over t until the protocol finishes, and returns the result of h.
+//
+// If ctx is done, or t fails, the protocol is stopped and the error is returned.
+func Run(ctx context.Context, h protocol.Handler, t Transport) (interface{}, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	incoming := make(chan *protocol.Message)
+	receiveErr := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := t.Receive(ctx)
+			if err != nil {
+				receiveErr <- err
+				return
+			}
+			select {
+			case incoming <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		// outgoing messages
+		case msg, ok := <-h.Listen():
+			if !ok {
+				// the channel was closed, indicating that the protocol is done executing.
+				return h.Result()
+			}
+			if err := t.Send(ctx, msg); err != nil {
+				h.Stop()
+				return nil, fmt.Errorf("transport: failed to send %v: %w", msg, err)
+			}
+
+		// incoming messages
+		case msg := <-incoming:
+			h.Accept(msg)
+
+		case err := <-receiveErr:
+			h.Stop()
+			return nil, fmt.Errorf("transport: failed to receive: %w", err)
+
+		case <-ctx.Done():
+			h.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// recipients returns the parties among parties to which msg should be delivered.
+func recipients(msg *protocol.Message, parties party.IDSlice) []party.ID {
+	if msg.To != "" {
+		return []party.ID{msg.To}
+	}
+	ids := make([]party.ID, 0, len(parties))
+	for _, id := range parties {
+		if id != msg.From {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,170 @@
+package transport_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+	"github.com/taurusgroup/multi-party-sig/protocols/doerner"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+// runAll runs the handler of each party over its transport, and returns the results.
+func runAll(t *testing.T, handlers map[party.ID]protocol.Handler, transports map[party.ID]transport.Transport) map[party.ID]interface{} {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		mtx     sync.Mutex
+		results = make(map[party.ID]interface{}, len(handlers))
+	)
+	for id := range handlers {
+		wg.Add(1)
+		go func(id party.ID) {
+			defer wg.Done()
+			result, err := transport.Run(ctx, handlers[id], transports[id])
+			assert.NoError(t, err)
+			mtx.Lock()
+			defer mtx.Unlock()
+			results[id] = result
+		}(id)
+	}
+	wg.Wait()
+	return results
+}
+
+// frostKeygenSign runs a FROST keygen and signature, where newTransport returns the transport for a party in a session.
+func frostKeygenSign(t *testing.T, newTransport func(session string, id party.ID, parties party.IDSlice) transport.Transport) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+	message := []byte("hello")
+
+	handlers := make(map[party.ID]protocol.Handler, len(partyIDs))
+	transports := make(map[party.ID]transport.Transport, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, partyIDs, 1), nil)
+		require.NoError(t, err)
+		handlers[id] = h
+		transports[id] = newTransport("keygen", id, partyIDs)
+	}
+	configs := runAll(t, handlers, transports)
+
+	signers := partyIDs[:2]
+	handlers = make(map[party.ID]protocol.Handler, len(signers))
+	transports = make(map[party.ID]transport.Transport, len(signers))
+	for _, id := range signers {
+		h, err := protocol.NewMultiHandler(frost.Sign(configs[id].(*frost.Config), signers, message), nil)
+		require.NoError(t, err)
+		handlers[id] = h
+		transports[id] = newTransport("sign", id, signers)
+	}
+	results := runAll(t, handlers, transports)
+
+	for _, id := range signers {
+		signature, ok := results[id].(frost.Signature)
+		require.True(t, ok)
+		assert.True(t, signature.Verify(configs[id].(*frost.Config).PublicKey, message))
+	}
+}
+
+func TestMemory(t *testing.T) {
+	m := transport.NewMemory()
+	frostKeygenSign(t, m.Transport)
+}
+
+func TestHTTP(t *testing.T) {
+	server := httptest.NewServer(transport.NewServer())
+	defer server.Close()
+
+	frostKeygenSign(t, func(session string, id party.ID, parties party.IDSlice) transport.Transport {
+		return transport.NewHTTPClient(server.URL, session, id, parties, server.Client())
+	})
+}
+
+// dropper forwards requests to a transport.Server, but cuts off every third answer to a GET halfway through.
+type dropper struct {
+	server http.Handler
+	gets   int32
+}
+
+func (d *dropper) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && atomic.AddInt32(&d.gets, 1)%3 == 0 {
+		w = halfWriter{w}
+	}
+	d.server.ServeHTTP(w, r)
+}
+
+// halfWriter writes the first half of the body, and then aborts the connection.
+type halfWriter struct {
+	http.ResponseWriter
+}
+
+func (w halfWriter) Write(data []byte) (int, error) {
+	_, _ = w.ResponseWriter.Write(data[:len(data)/2])
+	w.ResponseWriter.(http.Flusher).Flush()
+	panic(http.ErrAbortHandler)
+}
+
+func TestHTTPDroppedResponse(t *testing.T) {
+	d := &dropper{server: transport.NewServer()}
+	server := httptest.NewServer(d)
+	defer server.Close()
+
+	frostKeygenSign(t, func(session string, id party.ID, parties party.IDSlice) transport.Transport {
+		return transport.NewHTTPClient(server.URL, session, id, parties, server.Client())
+	})
+	assert.Greater(t, atomic.LoadInt32(&d.gets), int32(3), "expected some answers to be dropped")
+}
+
+func TestHTTPTwoParty(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	server := httptest.NewServer(transport.NewServer())
+	defer server.Close()
+
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b"}
+	receiver, err := protocol.NewTwoPartyHandler(doerner.Keygen(group, true, "a", "b", pl), nil, true)
+	require.NoError(t, err)
+	sender, err := protocol.NewTwoPartyHandler(doerner.Keygen(group, false, "b", "a", pl), nil, false)
+	require.NoError(t, err)
+
+	results := runAll(t,
+		map[party.ID]protocol.Handler{"a": receiver, "b": sender},
+		map[party.ID]transport.Transport{
+			"a": transport.NewHTTPClient(server.URL, "doerner", "a", partyIDs, server.Client()),
+			"b": transport.NewHTTPClient(server.URL, "doerner", "b", partyIDs, server.Client()),
+		})
+	assert.IsType(t, &doerner.ConfigReceiver{}, results["a"])
+	assert.IsType(t, &doerner.ConfigSender{}, results["b"])
+}
+
+func TestRunContext(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := party.IDSlice{"a", "b", "c"}
+
+	h, err := protocol.NewMultiHandler(frost.Keygen(group, "a", partyIDs, 1), nil)
+	require.NoError(t, err)
+
+	// the other parties never start
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = transport.Run(ctx, h, transport.NewMemory().Transport("keygen", "a", partyIDs))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = h.Result()
+	assert.Error(t, err)
+}
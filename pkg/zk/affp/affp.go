@@ -1,7 +1,7 @@
 package zkaffp
 
 import (
-	"crypto/rand"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
@@ -103,7 +103,7 @@ func (p *Proof) IsValid(public Public) bool {
 	return true
 }
 
-func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
+func NewProof(rand io.Reader, group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
 	N0 := public.Verifier.N()
 	N1 := public.Prover.N()
 	N0Modulus := public.Verifier.Modulus()
@@ -112,17 +112,17 @@ func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private
 	verifier := public.Verifier
 	prover := public.Prover
 
-	alpha := sample.IntervalLEps(rand.Reader)
-	beta := sample.IntervalLPrimeEps(rand.Reader)
+	alpha := sample.IntervalLEps(rand)
+	beta := sample.IntervalLPrimeEps(rand)
 
-	rho := sample.UnitModN(rand.Reader, N0)
-	rhoX := sample.UnitModN(rand.Reader, N1)
-	rhoY := sample.UnitModN(rand.Reader, N1)
+	rho := sample.UnitModN(rand, N0)
+	rhoX := sample.UnitModN(rand, N1)
+	rhoY := sample.UnitModN(rand, N1)
 
-	gamma := sample.IntervalLEpsN(rand.Reader)
-	m := sample.IntervalLN(rand.Reader)
-	delta := sample.IntervalLEpsN(rand.Reader)
-	mu := sample.IntervalLN(rand.Reader)
+	gamma := sample.IntervalLEpsN(rand)
+	m := sample.IntervalLN(rand)
+	delta := sample.IntervalLEpsN(rand)
+	mu := sample.IntervalLN(rand)
 
 	cAlpha := public.Kv.Clone().Mul(verifier, alpha)            // = Cᵃ mod N₀ = α ⊙ Kv
 	A := verifier.EncWithNonce(beta, rho).Add(verifier, cAlpha) // = Enc₀(β,ρ) ⊕ (α ⊙ Kv)
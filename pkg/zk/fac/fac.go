@@ -1,7 +1,7 @@
 package zkfac
 
 import (
-	"crypto/rand"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
@@ -37,18 +37,18 @@ type Proof struct {
 	V     *saferith.Int
 }
 
-func NewProof(private Private, hash *hash.Hash, public Public) *Proof {
+func NewProof(rand io.Reader, private Private, hash *hash.Hash, public Public) *Proof {
 	Nhat := public.Aux.NArith()
 
 	// Figure 28, point 1.
-	alpha := sample.IntervalLEpsRootN(rand.Reader)
-	beta := sample.IntervalLEpsRootN(rand.Reader)
-	mu := sample.IntervalLN(rand.Reader)
-	nu := sample.IntervalLN(rand.Reader)
-	sigma := sample.IntervalLN2(rand.Reader)
-	r := sample.IntervalLEpsN2(rand.Reader)
-	x := sample.IntervalLEpsN(rand.Reader)
-	y := sample.IntervalLEpsN(rand.Reader)
+	alpha := sample.IntervalLEpsRootN(rand)
+	beta := sample.IntervalLEpsRootN(rand)
+	mu := sample.IntervalLN(rand)
+	nu := sample.IntervalLN(rand)
+	sigma := sample.IntervalLN2(rand)
+	r := sample.IntervalLEpsN2(rand)
+	x := sample.IntervalLEpsN(rand)
+	y := sample.IntervalLEpsN(rand)
 
 	pInt := new(saferith.Int).SetNat(private.P)
 	qInt := new(saferith.Int).SetNat(private.Q)
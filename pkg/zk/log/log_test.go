@@ -26,7 +26,7 @@ func TestLog(t *testing.T) {
 		Y: Y,
 	}
 
-	proof := NewProof(group, hash.New(), public, Private{
+	proof := NewProof(rand.Reader, group, hash.New(), public, Private{
 		A: a,
 		B: b,
 	})
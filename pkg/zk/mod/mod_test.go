@@ -24,7 +24,7 @@ func TestMod(t *testing.T) {
 	p, q := zk.ProverPaillierSecret.P(), zk.ProverPaillierSecret.Q()
 	sk := zk.ProverPaillierSecret
 	public := Public{N: sk.PublicKey.N()}
-	proof := NewProof(hash.New(), Private{
+	proof := NewProof(rand.Reader, hash.New(), Private{
 		P:   p,
 		Q:   q,
 		Phi: sk.Phi(),
@@ -103,8 +103,8 @@ func BenchmarkCRT(b *testing.B) {
 	pl := pool.NewPool(0)
 	defer pl.TearDown()
 
-	sk := paillier.NewSecretKey(pl)
-	ped, _ := sk.GeneratePedersen()
+	sk := paillier.NewSecretKey(rand.Reader, pl)
+	ped, _ := sk.GeneratePedersen(rand.Reader)
 
 	public := Public{
 		ped.N(),
@@ -117,6 +117,6 @@ func BenchmarkCRT(b *testing.B) {
 	}
 	b.StartTimer()
 	for i := 0; i < b.N; i++ {
-		proof = NewProof(hash.New(), private, public, nil)
+		proof = NewProof(rand.Reader, hash.New(), private, public, nil)
 	}
 }
@@ -21,7 +21,7 @@ func TestMulG(t *testing.T) {
 	verifierPedersen := zk.Pedersen
 
 	c := new(saferith.Int).SetUint64(12)
-	C, _ := verifierPaillier.Enc(c)
+	C, _ := verifierPaillier.Enc(rand.Reader, c)
 
 	x := sample.IntervalL(rand.Reader)
 	X := group.NewScalar().SetNat(x.Mod(group.Order())).ActOnBase()
@@ -42,7 +42,7 @@ func TestMulG(t *testing.T) {
 		X:   x,
 		Rho: rho,
 	}
-	proof := NewProof(group, hash.New(), public, private)
+	proof := NewProof(rand.Reader, group, hash.New(), public, private)
 	assert.True(t, proof.Verify(group, hash.New(), public))
 
 	out, err := cbor.Marshal(proof)
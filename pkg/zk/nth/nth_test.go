@@ -23,7 +23,7 @@ func TestNth(t *testing.T) {
 	r := N.ModulusSquared().Exp(rho, NMod.Nat())
 
 	public := Public{N: N, R: r}
-	proof := NewProof(hash.New(), public, Private{
+	proof := NewProof(rand.Reader, hash.New(), public, Private{
 		Rho: rho,
 	})
 	assert.True(t, proof.Verify(hash.New(), public))
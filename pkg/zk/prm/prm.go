@@ -1,7 +1,6 @@
 package zkprm
 
 import (
-	"crypto/rand"
 	"io"
 	"math/big"
 
@@ -38,7 +37,7 @@ func (p *Proof) IsValid(public Public) bool {
 
 // NewProof generates a proof that:
 // s = t^lambda (mod N).
-func NewProof(private Private, hash *hash.Hash, public Public, pl *pool.Pool) *Proof {
+func NewProof(rand io.Reader, private Private, hash *hash.Hash, public Public, pl *pool.Pool) *Proof {
 	lambda := private.Lambda
 	phi := saferith.ModulusFromNat(private.Phi)
 
@@ -48,7 +47,7 @@ func NewProof(private Private, hash *hash.Hash, public Public, pl *pool.Pool) *P
 		as [params.StatParam]*saferith.Nat
 		As [params.StatParam]*big.Int
 	)
-	lockedRand := pool.NewLockedReader(rand.Reader)
+	lockedRand := pool.NewLockedReader(rand)
 	pl.Parallelize(params.StatParam, func(i int) interface{} {
 		// aᵢ ∈ mod ϕ(N)
 		as[i] = sample.ModN(lockedRand, phi)
@@ -1,9 +1,10 @@
 package zksch
 
 import (
-	"crypto/rand"
+	"errors"
 	"io"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -32,10 +33,10 @@ type Proof struct {
 }
 
 // NewProof generates a Schnorr proof of knowledge of exponent for public, using the Fiat-Shamir transform.
-func NewProof(hash *hash.Hash, public curve.Point, private curve.Scalar, gen curve.Point) *Proof {
+func NewProof(rand io.Reader, hash *hash.Hash, public curve.Point, private curve.Scalar, gen curve.Point) *Proof {
 	group := private.Curve()
 
-	a := NewRandomness(rand.Reader, group, gen)
+	a := NewRandomness(rand, group, gen)
 	z := a.Prove(hash, public, private, gen)
 	return &Proof{
 		C: *a.Commitment(),
@@ -164,3 +165,32 @@ func EmptyResponse(group curve.Curve) *Response {
 func EmptyCommitment(group curve.Curve) *Commitment {
 	return &Commitment{C: group.NewPoint()}
 }
+
+// EmptyRandomness creates an empty Randomness with a fixed group, ready for unmarshalling.
+func EmptyRandomness(group curve.Curve) *Randomness {
+	return &Randomness{
+		a:          group.NewScalar(),
+		commitment: Commitment{C: group.NewPoint()},
+	}
+}
+
+type randomnessMarshal struct {
+	A curve.Scalar
+	C curve.Point
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// The result contains the secret nonce, and must be stored as carefully as a secret key.
+func (r *Randomness) MarshalBinary() ([]byte, error) {
+	return cbor.Marshal(&randomnessMarshal{A: r.a, C: r.commitment.C})
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (r *Randomness) UnmarshalBinary(data []byte) error {
+	if r == nil || r.a == nil || r.commitment.C == nil {
+		return errors.New("zksch: Randomness must be initialized using EmptyRandomness")
+	}
+	rm := &randomnessMarshal{A: r.a, C: r.commitment.C}
+	return cbor.Unmarshal(data, rm)
+}
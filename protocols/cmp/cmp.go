@@ -1,12 +1,15 @@
 package cmp
 
 import (
+	"errors"
+
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/recovery"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/keygen"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/presign"
@@ -27,12 +30,18 @@ func EmptyConfig(group curve.Curve) *Config {
 	}
 }
 
+// UnmarshalConfig decodes a Config produced by Config.MarshalBinary,
+// using the curve name embedded in the data to find the group.
+func UnmarshalConfig(data []byte) (*Config, error) {
+	return config.Unmarshal(data)
+}
+
 // Keygen generates a new shared ECDSA key over the curve defined by `group`. After a successful execution,
 // all participants posses a unique share of this key, as well as auxiliary parameters required during signing.
 //
 // For better performance, a `pool.Pool` can be provided in order to parallelize certain steps of the protocol.
 // Returns *cmp.Config if successful.
-func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, pl *pool.Pool) protocol.StartFunc {
+func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, pl *pool.Pool, opts ...protocol.StartOption) protocol.StartFunc {
 	info := round.Info{
 		ProtocolID:       "cmp/keygen-threshold",
 		FinalRoundNumber: keygen.Rounds,
@@ -40,14 +49,38 @@ func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, thresho
 		PartyIDs:         participants,
 		Threshold:        threshold,
 		Group:            group,
+		Rand:             protocol.Rand(opts...),
 	}
 	return keygen.Start(info, pl, nil)
 }
 
+// Import shares an existing ECDSA private key among `participants`, instead of generating a new one.
+// The `dealer` holds the full `secret` and deals it out, while the other participants pass a nil secret
+// and generate their auxiliary parameters, as in Keygen. The dealer should discard the secret afterwards.
+//
+// The dealer alone chooses the polynomial from which every share is computed, so unlike Keygen,
+// the shares are only as random as the dealer makes them. The other participants only check
+// that their shares are consistent with that polynomial.
+//
+// The resulting configs are the same as those produced by Keygen, and their PublicPoint() is secret⋅G.
+// Returns *cmp.Config if successful.
+func Import(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, dealer party.ID, secret curve.Scalar, pl *pool.Pool, opts ...protocol.StartOption) protocol.StartFunc {
+	info := round.Info{
+		ProtocolID:       "cmp/import-threshold",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           selfID,
+		PartyIDs:         participants,
+		Threshold:        threshold,
+		Group:            group,
+		Rand:             protocol.Rand(opts...),
+	}
+	return keygen.StartImport(info, pl, dealer, secret)
+}
+
 // Refresh allows the parties to refresh all existing cryptographic keys from a previously generated Config.
 // The group's ECDSA public key remains the same, but any previous shares are rendered useless.
 // Returns *cmp.Config if successful.
-func Refresh(config *Config, pl *pool.Pool) protocol.StartFunc {
+func Refresh(config *Config, pl *pool.Pool, opts ...protocol.StartOption) protocol.StartFunc {
 	info := round.Info{
 		ProtocolID:       "cmp/refresh-threshold",
 		FinalRoundNumber: keygen.Rounds,
@@ -55,14 +88,61 @@ func Refresh(config *Config, pl *pool.Pool) protocol.StartFunc {
 		PartyIDs:         config.PartyIDs(),
 		Threshold:        config.Threshold,
 		Group:            config.Group,
+		Rand:             protocol.Rand(opts...),
 	}
 	return keygen.Start(info, pl, config)
 }
 
+// Reshare transfers the key held by `oldParties` to `newParties`, with a new threshold.
+// The group's ECDSA public key and chain key remain the same, and new auxiliary parameters are generated
+// for every new party, while the shares of the previous committee are rendered useless.
+//
+// At least threshold+1 of the previous parties must take part, each providing its existing Config.
+// Parties joining without a share should use the Config created by NewcomerConfig.
+// Parties can be part of both sets.
+//
+// Returns *cmp.Config if successful, which is nil for parties that are not in `newParties`.
+func Reshare(config *Config, oldParties, newParties []party.ID, newThreshold int, pl *pool.Pool, opts ...protocol.StartOption) protocol.StartFunc {
+	oldIDs := party.NewIDSlice(oldParties)
+	partyIDs := append(party.IDSlice{}, oldIDs...)
+	for _, id := range newParties {
+		if !oldIDs.Contains(id) {
+			partyIDs = append(partyIDs, id)
+		}
+	}
+	info := round.Info{
+		ProtocolID:       "cmp/reshare-threshold",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           config.ID,
+		PartyIDs:         partyIDs,
+		Threshold:        newThreshold,
+		Group:            config.Group,
+		Rand:             protocol.Rand(opts...),
+	}
+	return keygen.StartReshare(info, pl, config, oldParties, newParties)
+}
+
+// NewcomerConfig creates the Config a party without a share of the key provides to Reshare,
+// in order to join the committee.
+//
+// publicShares contains the public key shares of the old parties, as found in the Public field of their configs.
+// They determine the key the newcomer expects to receive a share of, and Reshare aborts if the old parties deal a different one.
+func NewcomerConfig(group curve.Curve, selfID party.ID, publicShares map[party.ID]curve.Point) *Config {
+	public := make(map[party.ID]*config.Public, len(publicShares))
+	for j, X := range publicShares {
+		public[j] = &config.Public{ECDSA: X}
+	}
+	return &Config{
+		Group:  group,
+		ID:     selfID,
+		Public: public,
+	}
+}
+
 // Sign generates an ECDSA signature for `messageHash` among the given `signers`.
 // Returns *ecdsa.Signature if successful.
-func Sign(config *Config, signers []party.ID, messageHash []byte, pl *pool.Pool) protocol.StartFunc {
-	return sign.StartSign(config, signers, messageHash, pl)
+func Sign(config *Config, signers []party.ID, messageHash []byte, pl *pool.Pool, opts ...protocol.StartOption) protocol.StartFunc {
+	return sign.StartSign(config, signers, messageHash, pl, protocol.Rand(opts...))
 }
 
 // Presign generates a preprocessed signature that does not depend on the message being signed.
@@ -70,12 +150,43 @@ func Sign(config *Config, signers []party.ID, messageHash []byte, pl *pool.Pool)
 // to produce a full signature with the PresignOnline protocol.
 // Note: the PreSignatures should be treated as secret key material.
 // Returns *ecdsa.PreSignature if successful.
-func Presign(config *Config, signers []party.ID, pl *pool.Pool) protocol.StartFunc {
-	return presign.StartPresign(config, signers, nil, pl)
+func Presign(config *Config, signers []party.ID, pl *pool.Pool, opts ...protocol.StartOption) protocol.StartFunc {
+	return presign.StartPresign(config, signers, nil, pl, protocol.Rand(opts...))
 }
 
 // PresignOnline efficiently generates an ECDSA signature for `messageHash` given a preprocessed `PreSignature`.
 // Returns *ecdsa.Signature if successful.
-func PresignOnline(config *Config, preSignature *ecdsa.PreSignature, messageHash []byte, pl *pool.Pool) protocol.StartFunc {
-	return presign.StartPresignOnline(config, preSignature, messageHash, pl)
+func PresignOnline(config *Config, preSignature *ecdsa.PreSignature, messageHash []byte, pl *pool.Pool, opts ...protocol.StartOption) protocol.StartFunc {
+	return presign.StartPresignOnline(config, preSignature, messageHash, pl, protocol.Rand(opts...))
+}
+
+// ReconstructKey recovers the full ECDSA private key from the configs of at least threshold+1 parties.
+//
+// Each share is checked against the public data of the first config, and the result against its PublicPoint().
+// If a share is inconsistent, the error is a *recovery.ShareError naming its holder.
+//
+// This should only be used offline, for disaster recovery, since the key is no longer protected by the threshold.
+func ReconstructKey(configs []*Config) (*recovery.PrivateKey, error) {
+	if len(configs) == 0 || configs[0] == nil {
+		return nil, errors.New("cmp.ReconstructKey: no configs given")
+	}
+	group := configs[0].Group
+	publicShares := make(map[party.ID]curve.Point, len(configs[0].Public))
+	for id, public := range configs[0].Public {
+		publicShares[id] = public.ECDSA
+	}
+	shares := make(map[party.ID]curve.Scalar, len(configs))
+	for _, c := range configs {
+		if c == nil {
+			return nil, errors.New("cmp.ReconstructKey: config is nil")
+		}
+		if _, ok := shares[c.ID]; ok {
+			return nil, &recovery.ShareError{ID: c.ID, Reason: "given more than once"}
+		}
+		if public, ok := c.Public[c.ID]; !ok || public == nil || public.ECDSA == nil || !public.ECDSA.Equal(publicShares[c.ID]) {
+			return nil, &recovery.ShareError{ID: c.ID, Reason: "config belongs to a different key"}
+		}
+		shares[c.ID] = c.ECDSA
+	}
+	return recovery.Reconstruct(group, shares, publicShares, configs[0].PublicPoint())
 }
@@ -3,9 +3,11 @@ package cmp
 import (
 	"crypto/rand"
 	"math"
+	mrand "math/rand"
 	"sync"
 	"testing"
 
+	"github.com/cronokirby/saferith"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/taurusgroup/multi-party-sig/internal/test"
@@ -14,6 +16,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/recovery"
 )
 
 func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte, pl *pool.Pool, n *test.Network, wg *sync.WaitGroup) {
@@ -161,3 +164,64 @@ func TestStart(t *testing.T) {
 		})
 	}
 }
+
+func TestReconstructKey(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	group := curve.Secp256k1{}
+	N, T := 4, 2
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+
+	quorum := make([]*Config, 0, T+1)
+	for _, id := range partyIDs[1:] {
+		quorum = append(quorum, configs[id])
+	}
+	key, err := ReconstructKey(quorum)
+	require.NoError(t, err)
+	assert.True(t, key.Scalar.ActOnBase().Equal(configs[partyIDs[0]].PublicPoint()))
+
+	_, err = ReconstructKey(quorum[:T])
+	assert.Error(t, err, "reconstruction should fail with only threshold shares")
+
+	tampered := *configs[partyIDs[2]]
+	tampered.ECDSA = group.NewScalar().Set(tampered.ECDSA).Add(group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1)))
+	_, err = ReconstructKey([]*Config{configs[partyIDs[0]], &tampered, configs[partyIDs[3]]})
+	var shareErr *recovery.ShareError
+	require.ErrorAs(t, err, &shareErr)
+	assert.Equal(t, partyIDs[2], shareErr.ID)
+}
+
+func TestDerivePath(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	configs, partyIDs := test.GenerateConfig(curve.Secp256k1{}, 3, 1, mrand.New(mrand.NewSource(1)), pl)
+
+	derived := make([]*Config, 0, len(partyIDs))
+	for _, id := range partyIDs {
+		c, err := configs[id].DerivePath("m/44/60/0/0/5")
+		require.NoError(t, err)
+		expected := configs[id]
+		for _, i := range []uint32{44, 60, 0, 0, 5} {
+			expected, err = expected.DeriveBIP32(i)
+			require.NoError(t, err)
+		}
+		assert.True(t, expected.PublicPoint().Equal(c.PublicPoint()))
+		assert.Equal(t, expected.ChainKey, c.ChainKey)
+		derived = append(derived, c)
+
+		_, err = configs[id].DerivePath("m/44'/60'/0'/0/5")
+		assert.Error(t, err, "hardened derivation should fail")
+	}
+	key, err := ReconstructKey(derived)
+	require.NoError(t, err, "derived shares should be consistent")
+	assert.True(t, key.Scalar.ActOnBase().Equal(derived[0].PublicPoint()))
+
+	xpub, err := configs[partyIDs[0]].XPub(true)
+	require.NoError(t, err)
+	assert.Equal(t, "xpub", xpub[:4])
+	tpub, err := configs[partyIDs[0]].XPub(false)
+	require.NoError(t, err)
+	assert.Equal(t, "tpub", tpub[:4])
+}
@@ -254,7 +254,7 @@ func (c *Config) Derive(adjust curve.Scalar, newChainKey []byte) (*Config, error
 // DeriveBIP32 derives a sharing of the ith child of the consortium signing key.
 //
 // This function uses unhardened derivation, deriving a key without including the
-// underlying private key. This function will panic if i ⩾ 2³¹, since that indicates
+// underlying private key. An error is returned if i ⩾ 2³¹, since that indicates
 // a hardened key.
 //
 // Sometimes, an error will be returned, indicating that this index generates
@@ -272,3 +272,21 @@ func (c *Config) DeriveBIP32(i uint32) (*Config, error) {
 	}
 	return c.Derive(scalar, newChainKey)
 }
+
+// DerivePath derives a sharing of the key at a BIP32 path,
+// by applying DeriveBIP32 for each segment. See bip32.DerivePath.
+func (c *Config) DerivePath(path string) (*Config, error) {
+	return bip32.DerivePath(c, path, (*Config).DeriveBIP32)
+}
+
+// XPub returns the BIP32 extended public key made of PublicPoint() and ChainKey, encoded as a master key.
+//
+// Watch-only wallets can use it to derive the same public keys as DerivePath.
+// The "xpub" version is used if mainnet is true, otherwise "tpub".
+func (c *Config) XPub(mainnet bool) (string, error) {
+	publicPoint, ok := c.PublicPoint().(*curve.Secp256k1Point)
+	if !ok {
+		return "", errors.New("XPub must be called with secp256k1")
+	}
+	return (&bip32.ExtendedPublicKey{Public: publicPoint, ChainKey: c.ChainKey}).Encode(mainnet)
+}
@@ -23,7 +23,21 @@ func EmptyConfig(group curve.Curve) *Config {
 	}
 }
 
+// Unmarshal decodes a Config produced by MarshalBinary.
+//
+// The group is looked up using the curve name embedded in the data,
+// so it doesn't need to be known in advance, unlike with EmptyConfig.
+func Unmarshal(data []byte) (*Config, error) {
+	c := new(Config)
+	if err := c.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 type configMarshal struct {
+	// Group is the name of the curve, see curve.ByName.
+	Group          string
 	ID             party.ID
 	Threshold      int
 	ECDSA, ElGamal curve.Scalar
@@ -58,6 +72,7 @@ func (c *Config) MarshalBinary() ([]byte, error) {
 		ps = append(ps, data)
 	}
 	return cbor.Marshal(&configMarshal{
+		Group:     c.Group.Name(),
 		ID:        c.ID,
 		Threshold: c.Threshold,
 		ECDSA:     c.ECDSA,
@@ -70,13 +85,31 @@ func (c *Config) MarshalBinary() ([]byte, error) {
 	})
 }
 
+// UnmarshalBinary decodes a Config, using the curve name embedded in the data.
+//
+// Data produced without a curve name can still be decoded, if the group was set using EmptyConfig.
 func (c *Config) UnmarshalBinary(data []byte) error {
-	if c.Group == nil {
+	var header struct{ Group string }
+	if err := cbor.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	group := c.Group
+	if header.Group != "" {
+		named, err := curve.ByName(header.Group)
+		if err != nil {
+			return fmt.Errorf("config: %w", err)
+		}
+		if group != nil && group.Name() != named.Name() {
+			return fmt.Errorf("config: expected curve %s, found %s", group.Name(), named.Name())
+		}
+		group = named
+	}
+	if group == nil {
 		return errors.New("config must be initialized using EmptyConfig")
 	}
 	cm := &configMarshal{
-		ECDSA:   c.Group.NewScalar(),
-		ElGamal: c.Group.NewScalar(),
+		ECDSA:   group.NewScalar(),
+		ElGamal: group.NewScalar(),
 	}
 	if err := cbor.Unmarshal(data, &cm); err != nil {
 		return fmt.Errorf("config: %w", err)
@@ -100,8 +133,8 @@ func (c *Config) UnmarshalBinary(data []byte) error {
 	ps := make(map[party.ID]*Public, len(cm.Public))
 	for _, pm := range cm.Public {
 		p := &publicMarshal{
-			ECDSA:   c.Group.NewPoint(),
-			ElGamal: c.Group.NewPoint(),
+			ECDSA:   group.NewPoint(),
+			ElGamal: group.NewPoint(),
 		}
 		if err := cbor.Unmarshal(pm, p); err != nil {
 			return fmt.Errorf("config: party %s: %w", p.ID, err)
@@ -152,7 +185,7 @@ func (c *Config) UnmarshalBinary(data []byte) error {
 	}
 
 	*c = Config{
-		Group:     c.Group,
+		Group:     group,
 		ID:        cm.ID,
 		Threshold: cm.Threshold,
 		ECDSA:     cm.ECDSA,
@@ -0,0 +1,65 @@
+package keygen
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+// StartImport shares an existing secret key held by a single dealer among all parties.
+//
+// This is a reshare with the dealer as the only old party, and info.PartyIDs as the new parties.
+// The dealer shares its secret directly, and a fresh chain key, so that it alone chooses every share,
+// while every party generates new Paillier and Pedersen parameters as in a regular keygen.
+//
+// The dealer must provide the secret, the other parties pass nil.
+func StartImport(info round.Info, pl *pool.Pool, dealer party.ID, secret curve.Scalar) protocol.StartFunc {
+	return func(sessionID []byte) (_ round.Session, err error) {
+		dealerIDs := party.NewIDSlice([]party.ID{dealer})
+		receiverIDs := party.NewIDSlice(info.PartyIDs)
+		if !config.ValidThreshold(info.Threshold, len(receiverIDs)) {
+			return nil, fmt.Errorf("import: threshold %d is invalid for %d parties", info.Threshold, len(receiverIDs))
+		}
+		if !receiverIDs.Contains(dealer) {
+			return nil, fmt.Errorf("import: dealer %s is not one of the parties", dealer)
+		}
+
+		helper, err := round.NewSession(info, sessionID, pl, dealerIDs, receiverIDs)
+		if err != nil {
+			return nil, fmt.Errorf("import: %w", err)
+		}
+
+		r := &round1{
+			Helper:           helper,
+			ReshareDealers:   dealerIDs,
+			ReshareReceivers: receiverIDs,
+		}
+
+		if helper.SelfID() != dealer {
+			// the dealer alone chooses the polynomial, we only receive a share
+			return r, nil
+		}
+
+		if secret == nil || secret.IsZero() {
+			return nil, errors.New("import: dealer must provide a non zero secret")
+		}
+		r.ResharePublicShares = map[party.ID]curve.Point{dealer: secret.ActOnBase()}
+		if r.PreviousChainKey, err = types.NewRID(r.Rand()); err != nil {
+			return nil, fmt.Errorf("import: failed to sample chain key: %w", err)
+		}
+
+		// fᵢ(X) deg(fᵢ) = t, fᵢ(0) = x
+		group := helper.Group()
+		constant := group.NewScalar().Set(secret)
+		r.VSSSecret = polynomial.NewPolynomial(r.Rand(), group, helper.Threshold(), constant)
+		return r, nil
+	}
+}
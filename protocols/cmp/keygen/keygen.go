@@ -1,7 +1,6 @@
 package keygen
 
 import (
-	"crypto/rand"
 	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
@@ -40,13 +39,13 @@ func Start(info round.Info, pl *pool.Pool, c *config.Config) protocol.StartFunc
 				PreviousSecretECDSA:       c.ECDSA,
 				PreviousPublicSharesECDSA: PublicSharesECDSA,
 				PreviousChainKey:          c.ChainKey,
-				VSSSecret:                 polynomial.NewPolynomial(group, helper.Threshold(), group.NewScalar()), // fᵢ(X) deg(fᵢ) = t, fᵢ(0) = 0
+				VSSSecret:                 polynomial.NewPolynomial(helper.Rand(), group, helper.Threshold(), group.NewScalar()), // fᵢ(X) deg(fᵢ) = t, fᵢ(0) = 0
 			}, nil
 		}
 
 		// sample fᵢ(X) deg(fᵢ) = t, fᵢ(0) = secretᵢ
-		VSSConstant := sample.Scalar(rand.Reader, group)
-		VSSSecret := polynomial.NewPolynomial(group, helper.Threshold(), VSSConstant)
+		VSSConstant := sample.Scalar(helper.Rand(), group)
+		VSSSecret := polynomial.NewPolynomial(helper.Rand(), group, helper.Threshold(), VSSConstant)
 		return &round1{
 			Helper:    helper,
 			VSSSecret: VSSSecret,
@@ -1,6 +1,8 @@
 package keygen
 
 import (
+	"crypto/rand"
+	"errors"
 	mrand "math/rand"
 	"testing"
 
@@ -10,13 +12,13 @@ import (
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/test"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 )
 
-var group = curve.Secp256k1{}
-
-func checkOutput(t *testing.T, rounds []round.Session) {
+func checkOutput(t *testing.T, group curve.Curve, rounds []round.Session) {
 	N := len(rounds)
 	newConfigs := make([]*config.Config, 0, N)
 	for _, r := range rounds {
@@ -51,10 +53,20 @@ func checkOutput(t *testing.T, rounds []round.Session) {
 		c2 := config.EmptyConfig(group)
 		err = c2.UnmarshalBinary(data)
 		assert.NoError(t, err, "failed to unmarshal new config", c.ID)
+		c3, err := config.Unmarshal(data)
+		assert.NoError(t, err, "failed to unmarshal new config without a group", c.ID)
+		assert.Equal(t, group.Name(), c3.Group.Name(), "group is different")
+		assert.True(t, pk.Equal(c3.PublicPoint()), "public point is different")
 	}
 }
 
 func TestKeygen(t *testing.T) {
+	for _, group := range test.Curves {
+		t.Run(group.Name(), func(t *testing.T) { testKeygen(t, group) })
+	}
+}
+
+func testKeygen(t *testing.T, group curve.Curve) {
 	pl := pool.NewPool(0)
 	defer pl.TearDown()
 
@@ -83,10 +95,54 @@ func TestKeygen(t *testing.T) {
 			break
 		}
 	}
-	checkOutput(t, rounds)
+	checkOutput(t, group, rounds)
+}
+
+func TestSnapshot(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	group := curve.Secp256k1{}
+	N := 2
+	partyIDs := test.PartyIDs(N)
+
+	start := func(i int) (round.Session, error) {
+		info := round.Info{
+			ProtocolID:       "cmp/keygen-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           partyIDs[i],
+			PartyIDs:         partyIDs,
+			Threshold:        N - 1,
+			Group:            group,
+		}
+		return Start(info, pl, nil)([]byte("session"))
+	}
+	rounds := make([]round.Session, 0, N)
+	for i := range partyIDs {
+		r, err := start(i)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+		// continue with rounds restored from their snapshots
+		require.NoError(t, test.Restore(rounds, start), "failed to restore round")
+	}
+	checkOutput(t, group, rounds)
 }
 
 func TestRefresh(t *testing.T) {
+	for _, group := range test.Curves {
+		t.Run(group.Name(), func(t *testing.T) { testRefresh(t, group) })
+	}
+}
+
+func testRefresh(t *testing.T, group curve.Curve) {
 	pl := pool.NewPool(0)
 	defer pl.TearDown()
 
@@ -117,5 +173,154 @@ func TestRefresh(t *testing.T) {
 			break
 		}
 	}
-	checkOutput(t, rounds)
+	checkOutput(t, group, rounds)
+}
+
+func TestReshare(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	group := curve.Secp256k1{}
+	// a 2-of-3 key held by a, b, c
+	oldConfigs, oldPartyIDs := test.GenerateConfig(group, 3, 1, mrand.New(mrand.NewSource(1)), pl)
+	publicPoint := oldConfigs[oldPartyIDs[0]].PublicPoint()
+	chainKey := oldConfigs[oldPartyIDs[0]].ChainKey
+
+	// a and b transfer the key to b, c, d as a 3-of-3 key
+	dealers := party.IDSlice{"a", "b"}
+	receivers := party.IDSlice{"b", "c", "d"}
+	partyIDs := party.IDSlice{"a", "b", "c", "d"}
+	newThreshold := 2
+
+	rounds := startReshare(t, pl, oldConfigs, oldConfigs[oldPartyIDs[0]].Public, dealers, receivers, partyIDs, newThreshold)
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+
+	newRounds := make([]round.Session, 0, len(receivers))
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		c := r.(*round.Output).Result.(*config.Config)
+		if !receivers.Contains(r.SelfID()) {
+			assert.Nil(t, c, "parties leaving should not get a config")
+			continue
+		}
+		newRounds = append(newRounds, r)
+		assert.Equal(t, newThreshold, c.Threshold)
+		assert.Equal(t, receivers, c.PartyIDs())
+		assert.True(t, publicPoint.Equal(c.PublicPoint()), "public point changed")
+		assert.EqualValues(t, chainKey, c.ChainKey, "chain key changed")
+		assert.True(t, c.ECDSA.ActOnBase().Equal(c.Public[c.ID].ECDSA), "share doesn't match public share")
+	}
+	checkOutput(t, group, newRounds)
+}
+
+// startReshare creates the first round of each party, where parties which are not dealers
+// only know the previous public shares.
+func startReshare(t *testing.T, pl *pool.Pool, oldConfigs map[party.ID]*config.Config, public map[party.ID]*config.Public, dealers, receivers, partyIDs party.IDSlice, newThreshold int) []round.Session {
+	group := oldConfigs[dealers[0]].Group
+	rounds := make([]round.Session, 0, len(partyIDs))
+	for _, id := range partyIDs {
+		c := &config.Config{Group: group, ID: id, Public: public}
+		if dealers.Contains(id) {
+			c = oldConfigs[id]
+		}
+		info := round.Info{
+			ProtocolID:       "cmp/reshare-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           id,
+			PartyIDs:         partyIDs,
+			Threshold:        newThreshold,
+			Group:            group,
+		}
+		r, err := StartReshare(info, pl, c, dealers, receivers)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	return rounds
+}
+
+func TestReshareWrongKey(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	group := curve.Secp256k1{}
+	oldConfigs, oldPartyIDs := test.GenerateConfig(group, 3, 1, mrand.New(mrand.NewSource(1)), pl)
+	otherConfigs, _ := test.GenerateConfig(group, 3, 1, mrand.New(mrand.NewSource(2)), pl)
+
+	// d expects a share of a different key, and must not accept this one
+	dealers := party.IDSlice{"a", "b"}
+	receivers := party.IDSlice{"b", "c", "d"}
+	partyIDs := party.IDSlice{"a", "b", "c", "d"}
+	rounds := startReshare(t, pl, oldConfigs, oldConfigs[oldPartyIDs[0]].Public, dealers, receivers, partyIDs, 2)
+	d, err := StartReshare(round.Info{
+		ProtocolID:       "cmp/reshare-test",
+		FinalRoundNumber: Rounds,
+		SelfID:           "d",
+		PartyIDs:         partyIDs,
+		Threshold:        2,
+		Group:            group,
+	}, pl, &config.Config{Group: group, ID: "d", Public: otherConfigs[oldPartyIDs[0]].Public}, dealers, receivers)(nil)
+	require.NoError(t, err)
+	rounds[3] = d
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		if err != nil {
+			assert.True(t, errors.Is(err, round.ErrInvalidShare), "expected an invalid share, got %v", err)
+			return
+		}
+		require.False(t, done, "resharing should not succeed with a different key")
+	}
+}
+
+func TestImport(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	group := curve.Secp256k1{}
+	secret := sample.Scalar(rand.Reader, group)
+	publicPoint := secret.ActOnBase()
+
+	N := 3
+	partyIDs := test.PartyIDs(N)
+	dealer := partyIDs[1]
+
+	rounds := make([]round.Session, 0, N)
+	for _, id := range partyIDs {
+		var s curve.Scalar
+		if id == dealer {
+			s = secret
+		}
+		info := round.Info{
+			ProtocolID:       "cmp/import-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           id,
+			PartyIDs:         partyIDs,
+			Threshold:        N - 2,
+			Group:            group,
+		}
+		r, err := StartImport(info, pl, dealer, s)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		c := r.(*round.Output).Result.(*config.Config)
+		assert.True(t, publicPoint.Equal(c.PublicPoint()), "public point is not the imported key")
+	}
+	checkOutput(t, group, rounds)
 }
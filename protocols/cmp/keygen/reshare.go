@@ -0,0 +1,132 @@
+package keygen
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+// StartReshare transfers the key of an existing set of holders to a new set of parties.
+//
+// info.PartyIDs must be the union of dealers and receivers, and info.Threshold the new threshold.
+//
+// Each dealer j shares λⱼ⋅xⱼ, where λⱼ is its Lagrange coefficient among the dealers,
+// so that the sum of their constant coefficients is the existing secret key.
+// Only the receivers obtain a share of the resulting key, along with fresh Paillier and Pedersen parameters.
+//
+// Dealers must provide their existing config. A receiver without a share only needs Group, ID,
+// and the previous public shares of the dealers in Public set in c, against which the new key is checked.
+// The result is a *config.Config for receivers, and a nil *config.Config for dealers leaving the committee.
+func StartReshare(info round.Info, pl *pool.Pool, c *config.Config, dealers, receivers []party.ID) protocol.StartFunc {
+	return func(sessionID []byte) (_ round.Session, err error) {
+		if c == nil {
+			return nil, errors.New("reshare: config is nil")
+		}
+		dealerIDs := party.NewIDSlice(dealers)
+		receiverIDs := party.NewIDSlice(receivers)
+		if len(dealerIDs) == 0 || !dealerIDs.Valid() || len(receiverIDs) == 0 || !receiverIDs.Valid() {
+			return nil, errors.New("reshare: duplicate or empty party ids")
+		}
+		if !config.ValidThreshold(info.Threshold, len(receiverIDs)) {
+			return nil, fmt.Errorf("reshare: threshold %d is invalid for %d new parties", info.Threshold, len(receiverIDs))
+		}
+		for _, id := range info.PartyIDs {
+			if !dealerIDs.Contains(id) && !receiverIDs.Contains(id) {
+				return nil, fmt.Errorf("reshare: party %s is neither an old nor a new party", id)
+			}
+		}
+
+		helper, err := round.NewSession(info, sessionID, pl, dealerIDs, receiverIDs)
+		if err != nil {
+			return nil, fmt.Errorf("reshare: %w", err)
+		}
+		if !helper.PartyIDs().Contains(dealerIDs...) || !helper.PartyIDs().Contains(receiverIDs...) {
+			return nil, errors.New("reshare: party ids must be the union of old and new parties")
+		}
+
+		// Every party checks the contributions of the dealers against their previous public shares,
+		// which newcomers obtain through their config, so that nobody accepts a different key.
+		ResharePublicShares := make(map[party.ID]curve.Point, len(dealerIDs))
+		for _, j := range dealerIDs {
+			public, ok := c.Public[j]
+			if !ok || public == nil || public.ECDSA == nil {
+				return nil, fmt.Errorf("reshare: missing previous public share of old party %s", j)
+			}
+			ResharePublicShares[j] = public.ECDSA
+		}
+
+		group := helper.Group()
+		r := &round1{
+			Helper:              helper,
+			ReshareDealers:      dealerIDs,
+			ReshareReceivers:    receiverIDs,
+			ResharePublicShares: ResharePublicShares,
+		}
+
+		if !dealerIDs.Contains(helper.SelfID()) {
+			// we're joining without a share, so we have nothing to deal, and only receive shares
+			return r, nil
+		}
+
+		if c.ECDSA == nil || c.ID != helper.SelfID() {
+			return nil, errors.New("reshare: old parties must provide their existing config")
+		}
+		if !c.CanSign(dealerIDs) {
+			return nil, fmt.Errorf("reshare: at least %d of the old parties must take part", c.Threshold+1)
+		}
+
+		r.PreviousChainKey = c.ChainKey
+
+		// fᵢ(X) deg(fᵢ) = t', fᵢ(0) = λᵢ⋅xᵢ
+		lagrange := polynomial.Lagrange(group, dealerIDs)
+		constant := group.NewScalar().Set(lagrange[helper.SelfID()]).Mul(c.ECDSA)
+		r.VSSSecret = polynomial.NewPolynomial(r.Rand(), group, helper.Threshold(), constant)
+		return r, nil
+	}
+}
+
+// resharing returns true if this execution transfers an existing key to a new set of parties.
+func (r *round1) resharing() bool {
+	return r.ReshareDealers != nil
+}
+
+// isDealer returns true if the VSS polynomial of party j contributes to the new key.
+func (r *round1) isDealer(j party.ID) bool {
+	return !r.resharing() || r.ReshareDealers.Contains(j)
+}
+
+// isReceiver returns true if party j obtains a share of the new key.
+func (r *round1) isReceiver(j party.ID) bool {
+	return !r.resharing() || r.ReshareReceivers.Contains(j)
+}
+
+// expectsShare returns true if a share from party j should be sent to party i.
+func (r *round1) expectsShare(j, i party.ID) bool {
+	return r.isDealer(j) && r.isReceiver(i)
+}
+
+// receivers returns the parties which obtain a share of the new key.
+func (r *round1) receivers() party.IDSlice {
+	if r.resharing() {
+		return r.ReshareReceivers
+	}
+	return r.PartyIDs()
+}
+
+// sampleChainKey returns the chain key we contribute.
+//
+// When resharing, dealers reuse the existing chain key, so that it doesn't change.
+func (r *round1) sampleChainKey() (types.RID, error) {
+	if r.resharing() && r.isDealer(r.SelfID()) {
+		return r.PreviousChainKey.Copy(), nil
+	}
+	return types.NewRID(r.Rand())
+}
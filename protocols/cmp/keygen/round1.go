@@ -1,7 +1,6 @@
 package keygen
 
 import (
-	"crypto/rand"
 	"errors"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
@@ -41,7 +40,20 @@ type round1 struct {
 	// Polynomial from which the new secret shares are computed.
 	// Keygen:  fᵢ(0) = xⁱ
 	// Refresh: fᵢ(0) = 0
+	// Reshare: fᵢ(0) = λᵢ⋅x'ᵢ for old parties, new parties don't deal a share and have none
 	VSSSecret *polynomial.Polynomial
+
+	// ReshareDealers contains the old parties whose shares are transferred.
+	// Keygen, Refresh: nil
+	ReshareDealers party.IDSlice
+
+	// ReshareReceivers contains the new parties receiving a share.
+	// Keygen, Refresh: nil
+	ReshareReceivers party.IDSlice
+
+	// ResharePublicShares[j] = X'ⱼ, the previous public share of dealer j.
+	// Known to all parties when resharing, but only to the dealer when importing.
+	ResharePublicShares map[party.ID]curve.Point
 }
 
 // VerifyMessage implements round.Round.
@@ -62,33 +74,39 @@ func (r *round1) StoreMessage(round.Message) error { return nil }
 // - commit to message.
 func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// generate Paillier and Pedersen
-	PaillierSecret := paillier.NewSecretKey(nil)
+	PaillierSecret := paillier.NewSecretKey(r.Rand(), nil)
 	SelfPaillierPublic := PaillierSecret.PublicKey
-	SelfPedersenPublic, PedersenSecret := PaillierSecret.GeneratePedersen()
+	SelfPedersenPublic, PedersenSecret := PaillierSecret.GeneratePedersen(r.Rand())
 
-	ElGamalSecret, ElGamalPublic := sample.ScalarPointPair(rand.Reader, r.Group())
+	ElGamalSecret, ElGamalPublic := sample.ScalarPointPair(r.Rand(), r.Group())
 
 	// save our own share already so we are consistent with what we receive from others
-	SelfShare := r.VSSSecret.Evaluate(r.SelfID().Scalar(r.Group()))
+	ShareReceived := map[party.ID]curve.Scalar{}
+	if r.expectsShare(r.SelfID(), r.SelfID()) {
+		ShareReceived[r.SelfID()] = r.VSSSecret.Evaluate(r.SelfID().Scalar(r.Group()))
+	}
 
-	// set Fᵢ(X) = fᵢ(X)•G
-	SelfVSSPolynomial := polynomial.NewPolynomialExponent(r.VSSSecret)
+	// set Fᵢ(X) = fᵢ(X)•G, or Fᵢ(X) = 0 if we don't deal a share
+	SelfVSSPolynomial := polynomial.ZeroExponent(r.Group())
+	if r.VSSSecret != nil {
+		SelfVSSPolynomial = polynomial.NewPolynomialExponent(r.VSSSecret)
+	}
 
 	// generate Schnorr randomness
-	SchnorrRand := zksch.NewRandomness(rand.Reader, r.Group(), nil)
+	SchnorrRand := zksch.NewRandomness(r.Rand(), r.Group(), nil)
 
 	// Sample RIDᵢ
-	SelfRID, err := types.NewRID(rand.Reader)
+	SelfRID, err := types.NewRID(r.Rand())
 	if err != nil {
 		return r, errors.New("failed to sample Rho")
 	}
-	chainKey, err := types.NewRID(rand.Reader)
+	chainKey, err := r.sampleChainKey()
 	if err != nil {
 		return r, errors.New("failed to sample c")
 	}
 
 	// commit to data in message 2
-	SelfCommitment, Decommitment, err := r.HashForID(r.SelfID()).Commit(
+	SelfCommitment, Decommitment, err := r.HashForID(r.SelfID()).Commit(r.Rand(),
 		SelfRID, chainKey, SelfVSSPolynomial, SchnorrRand.Commitment(), ElGamalPublic,
 		SelfPedersenPublic.N(), SelfPedersenPublic.S(), SelfPedersenPublic.T())
 	if err != nil {
@@ -108,7 +126,7 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 		Commitments:    map[party.ID]hash.Commitment{r.SelfID(): SelfCommitment},
 		RIDs:           map[party.ID]types.RID{r.SelfID(): SelfRID},
 		ChainKeys:      map[party.ID]types.RID{r.SelfID(): chainKey},
-		ShareReceived:  map[party.ID]curve.Scalar{r.SelfID(): SelfShare},
+		ShareReceived:  ShareReceived,
 		ElGamalPublic:  map[party.ID]curve.Point{r.SelfID(): ElGamalPublic},
 		PaillierPublic: map[party.ID]*paillier.PublicKey{r.SelfID(): SelfPaillierPublic},
 		Pedersen:       map[party.ID]*pedersen.Parameters{r.SelfID(): SelfPedersenPublic},
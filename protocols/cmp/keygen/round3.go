@@ -1,7 +1,7 @@
 package keygen
 
 import (
-	"errors"
+	"bytes"
 	"fmt"
 
 	"github.com/cronokirby/saferith"
@@ -85,14 +85,18 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 
 	// Save all X, VSSCommitments
 	VSSPolynomial := body.VSSPolynomial
-	// check that the constant coefficient is 0
-	// if refresh then the polynomial is constant
-	if !(r.VSSSecret.Constant().IsZero() == VSSPolynomial.IsConstant) {
-		return errors.New("vss polynomial has incorrect constant")
+	if r.resharing() {
+		if err := r.verifyReshareConstant(from, VSSPolynomial); err != nil {
+			return err
+		}
+	} else if !(r.VSSSecret.Constant().IsZero() == VSSPolynomial.IsConstant) {
+		// check that the constant coefficient is 0
+		// if refresh then the polynomial is constant
+		return fmt.Errorf("vss polynomial has incorrect constant: %w", round.ErrInvalidShare)
 	}
-	// check deg(Fⱼ) = t
-	if VSSPolynomial.Degree() != r.Threshold() {
-		return errors.New("vss polynomial has incorrect degree")
+	// check deg(Fⱼ) = t, unless Fⱼ = 0 was checked above
+	if r.isDealer(from) && VSSPolynomial.Degree() != r.Threshold() {
+		return fmt.Errorf("vss polynomial has incorrect degree: %w", round.ErrInvalidShare)
 	}
 
 	// Set Paillier
@@ -107,7 +111,7 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 	// Verify decommit
 	if !r.HashForID(from).Decommit(r.Commitments[from], body.Decommitment,
 		body.RID, body.C, VSSPolynomial, body.SchnorrCommitments, body.ElGamalPublic, body.N, body.S, body.T) {
-		return errors.New("failed to decommit")
+		return fmt.Errorf("failed to decommit: %w", round.ErrInvalidCommitment)
 	}
 	r.RIDs[from] = body.RID
 	r.ChainKeys[from] = body.C
@@ -138,7 +142,15 @@ func (round3) StoreMessage(round.Message) error { return nil }
 func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// c = ⊕ⱼ cⱼ
 	chainKey := r.PreviousChainKey
-	if chainKey == nil {
+	if r.resharing() {
+		// all dealers must agree on the existing chain key
+		chainKey = r.ChainKeys[r.ReshareDealers[0]]
+		for _, j := range r.ReshareDealers {
+			if !bytes.Equal(chainKey, r.ChainKeys[j]) {
+				return r.AbortRound(fmt.Errorf("old parties sent different chain keys: %w", round.ErrInconsistent), j), nil
+			}
+		}
+	} else if chainKey == nil {
 		chainKey = types.EmptyRID()
 		for _, j := range r.PartyIDs() {
 			chainKey.XOR(r.ChainKeys[j])
@@ -155,14 +167,14 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	_ = h.WriteAny(rid, r.SelfID())
 
 	// Prove N is a blum prime with zkmod
-	mod := zkmod.NewProof(h.Clone(), zkmod.Private{
+	mod := zkmod.NewProof(r.Rand(), h.Clone(), zkmod.Private{
 		P:   r.PaillierSecret.P(),
 		Q:   r.PaillierSecret.Q(),
 		Phi: r.PaillierSecret.Phi(),
 	}, zkmod.Public{N: r.PaillierPublic[r.SelfID()].N()}, r.Pool)
 
 	// prove s, t are correct as aux parameters with zkprm
-	prm := zkprm.NewProof(zkprm.Private{
+	prm := zkprm.NewProof(r.Rand(), zkprm.Private{
 		Lambda: r.PedersenSecret,
 		Phi:    r.PaillierSecret.Phi(),
 		P:      r.PaillierSecret.P(),
@@ -180,15 +192,18 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	for _, j := range r.OtherPartyIDs() {
 
 		// Prove that the factors of N are relatively large
-		fac := zkfac.NewProof(zkfac.Private{P: r.PaillierSecret.P(), Q: r.PaillierSecret.Q()}, h.Clone(), zkfac.Public{
+		fac := zkfac.NewProof(r.Rand(), zkfac.Private{P: r.PaillierSecret.P(), Q: r.PaillierSecret.Q()}, h.Clone(), zkfac.Public{
 			N:   r.PaillierPublic[r.SelfID()].N(),
 			Aux: r.Pedersen[j],
 		})
 
-		// compute fᵢ(j)
-		share := r.VSSSecret.Evaluate(j.Scalar(r.Group()))
-		// Encrypt share
-		C, _ := r.PaillierPublic[j].Enc(curve.MakeInt(share))
+		// compute fᵢ(j), unless j doesn't receive a share from us
+		var C *paillier.Ciphertext
+		if r.expectsShare(r.SelfID(), j) {
+			share := r.VSSSecret.Evaluate(j.Scalar(r.Group()))
+			// Encrypt share
+			C, _ = r.PaillierPublic[j].Enc(r.Rand(), curve.MakeInt(share))
+		}
 
 		err := r.SendMessage(out, &message4{
 			Share: C,
@@ -208,6 +223,41 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}, nil
 }
 
+// verifyReshareConstant checks the constant coefficient Fⱼ(0) of a VSS polynomial when resharing.
+//
+// - if j is a dealer, verify Fⱼ(0) = λⱼ⋅X'ⱼ, unless we are importing a key we don't know yet
+// - otherwise, verify Fⱼ = 0, since j doesn't deal a share.
+func (r *round3) verifyReshareConstant(j party.ID, VSSPolynomial *polynomial.Exponent) error {
+	if !r.isDealer(j) {
+		if !VSSPolynomial.IsConstant || VSSPolynomial.Degree() != 0 {
+			return fmt.Errorf("vss polynomial of new party isn't zero: %w", round.ErrInvalidShare)
+		}
+		return nil
+	}
+	if VSSPolynomial.IsConstant {
+		return fmt.Errorf("vss polynomial of old party has zero constant: %w", round.ErrInvalidShare)
+	}
+	if r.ResharePublicShares == nil {
+		return nil
+	}
+	lagrange := polynomial.LagrangeSingle(r.Group(), r.ReshareDealers, j)
+	if !lagrange.Act(r.ResharePublicShares[j]).Equal(VSSPolynomial.Constant()) {
+		return fmt.Errorf("vss polynomial constant doesn't match previous public share: %w", round.ErrInvalidShare)
+	}
+	return nil
+}
+
+// reshareCulprits returns the dealers whose constant coefficient doesn't match their previous public share.
+func (r *round3) reshareCulprits() []party.ID {
+	var culprits []party.ID
+	for _, j := range r.ReshareDealers {
+		if r.verifyReshareConstant(j, r.VSSPolynomials[j]) != nil {
+			culprits = append(culprits, j)
+		}
+	}
+	return culprits
+}
+
 // MessageContent implements round.Round.
 func (round3) MessageContent() round.Content { return nil }
 
@@ -1,7 +1,7 @@
 package keygen
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
@@ -12,6 +12,7 @@ import (
 	zkfac "github.com/taurusgroup/multi-party-sig/pkg/zk/fac"
 	zkmod "github.com/taurusgroup/multi-party-sig/pkg/zk/mod"
 	zkprm "github.com/taurusgroup/multi-party-sig/pkg/zk/prm"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 )
 
@@ -51,12 +52,12 @@ func (r *round4) StoreBroadcastMessage(msg round.Message) error {
 
 	// verify zkmod
 	if !body.Mod.Verify(zkmod.Public{N: r.Pedersen[from].N()}, r.HashForID(from), r.Pool) {
-		return errors.New("failed to validate mod proof")
+		return fmt.Errorf("failed to validate mod proof: %w", round.ErrInvalidProof)
 	}
 
 	// verify zkprm
 	if !body.Prm.Verify(zkprm.Public{Aux: r.Pedersen[from]}, r.HashForID(from), r.Pool) {
-		return errors.New("failed to validate prm proof")
+		return fmt.Errorf("failed to validate prm proof: %w", round.ErrInvalidProof)
 	}
 
 	return nil
@@ -72,13 +73,17 @@ func (r *round4) VerifyMessage(msg round.Message) error {
 		return round.ErrInvalidContent
 	}
 
-	if !r.PaillierPublic[msg.To].ValidateCiphertexts(body.Share) {
-		return errors.New("invalid ciphertext")
+	if !r.expectsShare(from, msg.To) {
+		if body.Share != nil {
+			return fmt.Errorf("unexpected share: %w", round.ErrMalformed)
+		}
+	} else if !r.PaillierPublic[msg.To].ValidateCiphertexts(body.Share) {
+		return fmt.Errorf("invalid ciphertext: %w", round.ErrMalformed)
 	}
 
 	// verify zkfac
 	if !body.Fac.Verify(zkfac.Public{N: r.PaillierPublic[from].N(), Aux: r.Pedersen[msg.To]}, r.HashForID(from)) {
-		return errors.New("failed to validate fac proof")
+		return fmt.Errorf("failed to validate fac proof: %w", round.ErrInvalidProof)
 	}
 
 	return nil
@@ -92,6 +97,9 @@ func (r *round4) VerifyMessage(msg round.Message) error {
 // - save share.
 func (r *round4) StoreMessage(msg round.Message) error {
 	from, body := msg.From, msg.Content.(*message4)
+	if !r.expectsShare(from, r.SelfID()) {
+		return nil
+	}
 
 	// decrypt share
 	DecryptedShare, err := r.PaillierSecret.Dec(body.Share)
@@ -100,7 +108,7 @@ func (r *round4) StoreMessage(msg round.Message) error {
 	}
 	Share := r.Group().NewScalar().SetNat(DecryptedShare.Mod(r.Group().Order()))
 	if DecryptedShare.Eq(curve.MakeInt(Share)) != 1 {
-		return errors.New("decrypted share is not in correct range")
+		return fmt.Errorf("decrypted share is not in correct range: %w", round.ErrInvalidShare)
 	}
 
 	// verify share with VSS
@@ -108,7 +116,7 @@ func (r *round4) StoreMessage(msg round.Message) error {
 	PublicShare := Share.ActOnBase()
 	// X == Fⱼ(i)
 	if !PublicShare.Equal(ExpectedPublicShare) {
-		return errors.New("failed to validate VSS share")
+		return fmt.Errorf("failed to validate VSS share: %w", round.ErrInvalidShare)
 	}
 
 	r.ShareReceived[from] = Share
@@ -129,13 +137,16 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	if r.PreviousSecretECDSA != nil {
 		UpdatedSecretECDSA.Set(r.PreviousSecretECDSA)
 	}
-	for _, j := range r.PartyIDs() {
-		UpdatedSecretECDSA.Add(r.ShareReceived[j])
+	for _, share := range r.ShareReceived {
+		UpdatedSecretECDSA.Add(share)
 	}
 
 	// [F₁(X), …, Fₙ(X)]
 	ShamirPublicPolynomials := make([]*polynomial.Exponent, 0, len(r.VSSPolynomials))
-	for _, VSSPolynomial := range r.VSSPolynomials {
+	for j, VSSPolynomial := range r.VSSPolynomials {
+		if !r.isDealer(j) {
+			continue
+		}
 		ShamirPublicPolynomials = append(ShamirPublicPolynomials, VSSPolynomial)
 	}
 
@@ -146,8 +157,8 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}
 
 	// compute the new public key share Xⱼ = F(j) (+X'ⱼ if doing a refresh)
-	PublicData := make(map[party.ID]*config.Public, len(r.PartyIDs()))
-	for _, j := range r.PartyIDs() {
+	PublicData := make(map[party.ID]*config.Public, len(r.receivers()))
+	for _, j := range r.receivers() {
 		PublicECDSAShare := ShamirPublicPolynomial.Evaluate(j.Scalar(r.Group()))
 		if r.PreviousPublicSharesECDSA != nil {
 			PublicECDSAShare = PublicECDSAShare.Add(r.PreviousPublicSharesECDSA[j])
@@ -160,6 +171,19 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 		}
 	}
 
+	// when resharing, the public key must not change.
+	// When importing, only the dealer knows the key beforehand.
+	var PreviousPublicPoint curve.Point
+	if r.ResharePublicShares != nil {
+		PreviousPublicPoint = r.Group().NewPoint()
+		for j, lagrange := range polynomial.Lagrange(r.Group(), r.ReshareDealers) {
+			PreviousPublicPoint = PreviousPublicPoint.Add(lagrange.Act(r.ResharePublicShares[j]))
+		}
+		if !PreviousPublicPoint.Equal(ShamirPublicPolynomial.Constant()) {
+			return r.AbortRound(fmt.Errorf("reshared public key is different: %w", round.ErrInconsistent), r.reshareCulprits()...), nil
+		}
+	}
+
 	UpdatedConfig := &config.Config{
 		Group:     r.Group(),
 		ID:        r.SelfID(),
@@ -172,12 +196,20 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 		Public:    PublicData,
 	}
 
+	if PreviousPublicPoint != nil && !UpdatedConfig.PublicPoint().Equal(PreviousPublicPoint) {
+		return r.AbortRound(fmt.Errorf("reshared public key is different: %w", round.ErrInconsistent)), nil
+	}
+
 	// write new ssid to hash, to bind the Schnorr proof to this new config
 	// Write SSID, selfID to temporary hash
 	h := r.Hash()
 	_ = h.WriteAny(UpdatedConfig, r.SelfID())
 
-	proof := r.SchnorrRand.Prove(h, PublicData[r.SelfID()].ECDSA, UpdatedSecretECDSA, nil)
+	// parties leaving the committee have no share to prove knowledge of
+	var proof *zksch.Response
+	if r.isReceiver(r.SelfID()) {
+		proof = r.SchnorrRand.Prove(h, PublicData[r.SelfID()].ECDSA, UpdatedSecretECDSA, nil)
+	}
 
 	// send to all
 	err = r.BroadcastMessage(out, &broadcast5{SchnorrResponse: proof})
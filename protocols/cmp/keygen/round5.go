@@ -1,7 +1,7 @@
 package keygen
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	sch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
@@ -31,6 +31,11 @@ func (r *round5) StoreBroadcastMessage(msg round.Message) error {
 		return round.ErrInvalidContent
 	}
 
+	// parties leaving the committee don't send a proof
+	if !r.isReceiver(from) {
+		return nil
+	}
+
 	if !body.SchnorrResponse.IsValid() {
 		return round.ErrNilFields
 	}
@@ -38,7 +43,7 @@ func (r *round5) StoreBroadcastMessage(msg round.Message) error {
 	if !body.SchnorrResponse.Verify(r.HashForID(from),
 		r.UpdatedConfig.Public[from].ECDSA,
 		r.SchnorrCommitments[from], nil) {
-		return errors.New("failed to validate schnorr proof for received share")
+		return fmt.Errorf("failed to validate schnorr proof for received share: %w", round.ErrInvalidProof)
 	}
 	return nil
 }
@@ -51,6 +56,9 @@ func (r *round5) StoreMessage(round.Message) error { return nil }
 
 // Finalize implements round.Round.
 func (r *round5) Finalize(chan<- *round.Message) (round.Session, error) {
+	if !r.isReceiver(r.SelfID()) {
+		return r.ResultRound((*config.Config)(nil)), nil
+	}
 	return r.ResultRound(r.UpdatedConfig), nil
 }
 
@@ -0,0 +1,177 @@
+package keygen
+
+import (
+	"fmt"
+
+	"github.com/cronokirby/saferith"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/snapshot"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+// state is the serialized form of the rounds of this protocol.
+//
+// The fields of round1 are set by the StartFunc, so only VSSSecret, which is sampled there, is included.
+type state struct {
+	Hash      []hash.BytesWithDomain
+	VSSSecret []byte
+	// round2
+	VSSPolynomials map[party.ID][]byte
+	Commitments    map[party.ID]hash.Commitment
+	RIDs           map[party.ID]types.RID
+	ChainKeys      map[party.ID]types.RID
+	ShareReceived  map[party.ID][]byte
+	ElGamalPublic  map[party.ID][]byte
+	PaillierPublic map[party.ID]*saferith.Modulus
+	Pedersen       map[party.ID]*snapshot.Pedersen
+	ElGamalSecret  []byte
+	PaillierSecret []*saferith.Nat
+	PedersenSecret *saferith.Nat
+	SchnorrRand    []byte
+	Decommitment   hash.Decommitment
+	// round3
+	SchnorrCommitments map[party.ID][]byte
+	// round4
+	RID      types.RID
+	ChainKey types.RID
+	// round5, the rest of UpdatedConfig is made of the values above
+	UpdatedECDSA       []byte
+	UpdatedPublicECDSA map[party.ID][]byte
+}
+
+// snapshotter is implemented by each round, which adds its own fields to those of the previous round.
+type snapshotter interface {
+	snapshot(s *state, e *snapshot.Encoder)
+}
+
+func (r *round1) snapshot(s *state, e *snapshot.Encoder) {
+	s.Hash = r.HashUpdates()
+	s.VSSSecret = e.Polynomial(r.VSSSecret)
+}
+
+func (r *round2) snapshot(s *state, e *snapshot.Encoder) {
+	r.round1.snapshot(s, e)
+	s.VSSPolynomials = e.Exponents(r.VSSPolynomials)
+	s.Commitments = r.Commitments
+	s.RIDs = r.RIDs
+	s.ChainKeys = r.ChainKeys
+	s.ShareReceived = e.Scalars(r.ShareReceived)
+	s.ElGamalPublic = e.Points(r.ElGamalPublic)
+	s.PaillierPublic = e.PaillierPublic(r.PaillierPublic)
+	s.Pedersen = e.Pedersen(r.Pedersen)
+	s.ElGamalSecret = e.Scalar(r.ElGamalSecret)
+	s.PaillierSecret = e.PaillierSecret(r.PaillierSecret)
+	s.PedersenSecret = r.PedersenSecret
+	s.SchnorrRand = e.SchnorrRandomness(r.SchnorrRand)
+	s.Decommitment = r.Decommitment
+}
+
+func (r *round3) snapshot(s *state, e *snapshot.Encoder) {
+	r.round2.snapshot(s, e)
+	s.SchnorrCommitments = e.SchnorrCommitments(r.SchnorrCommitments)
+}
+
+func (r *round4) snapshot(s *state, e *snapshot.Encoder) {
+	r.round3.snapshot(s, e)
+	s.RID = r.RID
+	s.ChainKey = r.ChainKey
+}
+
+func (r *round5) snapshot(s *state, e *snapshot.Encoder) {
+	r.round4.snapshot(s, e)
+	s.UpdatedECDSA = e.Scalar(r.UpdatedConfig.ECDSA)
+	s.UpdatedPublicECDSA = make(map[party.ID][]byte, len(r.UpdatedConfig.Public))
+	for j, public := range r.UpdatedConfig.Public {
+		s.UpdatedPublicECDSA[j] = e.Point(public.ECDSA)
+	}
+}
+
+// Snapshot implements round.Restorer.
+func (r *round1) Snapshot(current round.Session) ([]byte, error) {
+	c, ok := current.(snapshotter)
+	if !ok {
+		return nil, round.ErrNoSnapshot
+	}
+	var (
+		s state
+		e snapshot.Encoder
+	)
+	c.snapshot(&s, &e)
+	if err := e.Err(); err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(&s)
+}
+
+// Restore implements round.Restorer.
+func (r *round1) Restore(number round.Number, data []byte) (round.Session, error) {
+	var s state
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if err := r.RestoreHashUpdates(s.Hash); err != nil {
+		return nil, err
+	}
+	d := snapshot.NewDecoder(r.Group())
+	r.VSSSecret = d.Polynomial(s.VSSSecret)
+	r2 := &round2{
+		round1:         r,
+		VSSPolynomials: d.Exponents(s.VSSPolynomials),
+		Commitments:    s.Commitments,
+		RIDs:           s.RIDs,
+		ChainKeys:      s.ChainKeys,
+		ShareReceived:  d.Scalars(s.ShareReceived),
+		ElGamalPublic:  d.Points(s.ElGamalPublic),
+		PaillierPublic: d.PaillierPublic(s.PaillierPublic),
+		Pedersen:       d.Pedersen(s.Pedersen),
+		ElGamalSecret:  d.Scalar(s.ElGamalSecret),
+		PaillierSecret: d.PaillierSecret(s.PaillierSecret),
+		PedersenSecret: s.PedersenSecret,
+		SchnorrRand:    d.SchnorrRandomness(s.SchnorrRand),
+		Decommitment:   s.Decommitment,
+	}
+	r3 := &round3{
+		round2:             r2,
+		SchnorrCommitments: d.SchnorrCommitments(s.SchnorrCommitments),
+	}
+	r4 := &round4{
+		round3:   r3,
+		RID:      s.RID,
+		ChainKey: s.ChainKey,
+	}
+	r5 := &round5{round4: r4}
+	if s.UpdatedECDSA != nil {
+		public := make(map[party.ID]*config.Public, len(s.UpdatedPublicECDSA))
+		for j, ecdsa := range d.Points(s.UpdatedPublicECDSA) {
+			public[j] = &config.Public{
+				ECDSA:    ecdsa,
+				ElGamal:  r2.ElGamalPublic[j],
+				Paillier: r2.PaillierPublic[j],
+				Pedersen: r2.Pedersen[j],
+			}
+		}
+		r5.UpdatedConfig = &config.Config{
+			Group:     r.Group(),
+			ID:        r.SelfID(),
+			Threshold: r.Threshold(),
+			ECDSA:     d.Scalar(s.UpdatedECDSA),
+			ElGamal:   r2.ElGamalSecret,
+			Paillier:  r2.PaillierSecret,
+			RID:       r4.RID.Copy(),
+			ChainKey:  r4.ChainKey.Copy(),
+			Public:    public,
+		}
+	}
+	if err := d.Err(); err != nil {
+		return nil, err
+	}
+	rounds := []round.Session{r, r2, r3, r4, r5}
+	if number < 1 || int(number) > len(rounds) {
+		return nil, fmt.Errorf("keygen: invalid round %d", number)
+	}
+	return rounds[number-1], nil
+}
@@ -1,7 +1,8 @@
 package presign
 
 import (
-	"errors"
+	"fmt"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
@@ -48,17 +49,17 @@ func (r *abort1) StoreBroadcastMessage(msg round.Message) error {
 
 	public := r.Paillier[from]
 	if !body.KProof.Verify(r.HashForID(from), public, r.K[from]) {
-		return errors.New("failed to verify validity of k")
+		return fmt.Errorf("failed to verify validity of k: %w", round.ErrInvalidProof)
 	}
 
 	BigGammaShareActual := r.Group().NewScalar().SetNat(body.GammaShare.Mod(r.Group().Order())).ActOnBase()
 	if !r.BigGammaShare[from].Equal(BigGammaShareActual) {
-		return errors.New("different BigGammaShare")
+		return fmt.Errorf("different BigGammaShare: %w", round.ErrInvalidShare)
 	}
 
 	for id, deltaProof := range body.DeltaProofs {
 		if !deltaProof.Verify(r.HashForID(from), public, r.DeltaCiphertext[from][id]) {
-			return errors.New("failed to validate Delta MtA Nth proof")
+			return fmt.Errorf("failed to validate Delta MtA Nth proof: %w", round.ErrInvalidProof)
 		}
 	}
 	return nil
@@ -93,7 +94,7 @@ func (r *abort1) Finalize(chan<- *round.Message) (round.Session, error) {
 			culprits = append(culprits, j)
 		}
 	}
-	return r.AbortRound(errors.New("abort1: detected culprit"), culprits...), nil
+	return r.AbortRound(fmt.Errorf("abort1: detected culprit: %w", round.ErrInvalidShare), culprits...), nil
 }
 
 // MessageContent implements round.Round.
@@ -119,13 +120,13 @@ type abortNth struct {
 }
 
 // proveNth decypts the message and the nonce contained in the ciphertext c, using the private key.
-// Returns an abortNth proving knowledge of the nonce
-func proveNth(hash *hash.Hash, paillierSecret *paillier.SecretKey, c *paillier.Ciphertext) *abortNth {
+// Returns an abortNth proving knowledge of the nonce, using randomness from rand.
+func proveNth(rand io.Reader, hash *hash.Hash, paillierSecret *paillier.SecretKey, c *paillier.Ciphertext) *abortNth {
 	NSquared := paillierSecret.ModulusSquared()
 	N := paillierSecret.Modulus()
 	deltaShareAlpha, deltaNonce, _ := paillierSecret.DecWithRandomness(c)
 	deltaNonceHidden := NSquared.Exp(deltaNonce, N.Nat())
-	proof := zknth.NewProof(hash, zknth.Public{
+	proof := zknth.NewProof(rand, hash, zknth.Public{
 		N: paillierSecret.PublicKey,
 		R: deltaNonceHidden,
 	}, zknth.Private{Rho: deltaNonce})
@@ -1,7 +1,7 @@
 package presign
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -50,17 +50,17 @@ func (r *abort2) StoreBroadcastMessage(msg round.Message) error {
 		X: r.ElGamal[from],
 		Y: body.YHat,
 	}) {
-		return errors.New("failed to verify YHat log proof")
+		return fmt.Errorf("failed to verify YHat log proof: %w", round.ErrInvalidProof)
 	}
 
 	public := r.Paillier[from]
 	if !body.KProof.Verify(r.HashForID(from), public, r.K[from]) {
-		return errors.New("failed to verify validity of k")
+		return fmt.Errorf("failed to verify validity of k: %w", round.ErrInvalidProof)
 	}
 
 	for id, chiProof := range body.ChiProofs {
 		if !chiProof.Verify(r.HashForID(from), public, r.ChiCiphertext[from][id]) {
-			return errors.New("failed to validate Delta MtA Nth proof")
+			return fmt.Errorf("failed to validate Delta MtA Nth proof: %w", round.ErrInvalidProof)
 		}
 	}
 	return nil
@@ -93,7 +93,7 @@ func (r *abort2) Finalize(chan<- *round.Message) (round.Session, error) {
 		}
 	}
 
-	return r.AbortRound(errors.New("abort2: detected culprit"), culprits...), nil
+	return r.AbortRound(fmt.Errorf("abort2: detected culprit: %w", round.ErrInvalidShare), culprits...), nil
 }
 
 // MessageContent implements round.Round.
@@ -1,6 +1,7 @@
 package presign
 
 import (
+	"crypto/rand"
 	"testing"
 
 	"github.com/cronokirby/saferith"
@@ -123,7 +124,7 @@ func TestRoundFail(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			rounds := make([]round.Session, 0, N)
 			for _, c := range configs {
-				r, err := StartPresign(c, partyIDs, messageHash[:], pl)(nil)
+				r, err := StartPresign(c, partyIDs, messageHash[:], pl, rand.Reader)(nil)
 				require.NoError(t, err)
 				rounds = append(rounds, r)
 			}
@@ -1,8 +1,6 @@
 package presign
 
 import (
-	"crypto/rand"
-
 	"github.com/taurusgroup/multi-party-sig/internal/elgamal"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
@@ -68,24 +66,24 @@ func (presign1) StoreMessage(round.Message) error { return nil }
 // In two rounds, we compare the hashes received and if they are different then we abort.
 func (r *presign1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// γᵢ <- 𝔽,
-	GammaShare := sample.Scalar(rand.Reader, r.Group())
+	GammaShare := sample.Scalar(r.Rand(), r.Group())
 	// Gᵢ = Encᵢ(γᵢ;νᵢ)
-	G, GNonce := r.Paillier[r.SelfID()].Enc(curve.MakeInt(GammaShare))
+	G, GNonce := r.Paillier[r.SelfID()].Enc(r.Rand(), curve.MakeInt(GammaShare))
 
 	// kᵢ <- 𝔽,
-	KShare := sample.Scalar(rand.Reader, r.Group())
+	KShare := sample.Scalar(r.Rand(), r.Group())
 	KShareInt := curve.MakeInt(KShare)
 	// Kᵢ = Encᵢ(kᵢ;ρᵢ)
-	K, KNonce := r.Paillier[r.SelfID()].Enc(KShareInt)
+	K, KNonce := r.Paillier[r.SelfID()].Enc(r.Rand(), KShareInt)
 
 	// Zᵢ = (bᵢ⋅G, kᵢ⋅G+bᵢ⋅Yᵢ), bᵢ
-	ElGamalK, ElGamalNonce := elgamal.Encrypt(r.ElGamal[r.SelfID()], KShare)
+	ElGamalK, ElGamalNonce := elgamal.Encrypt(r.Rand(), r.ElGamal[r.SelfID()], KShare)
 
-	presignatureID, err := types.NewRID(rand.Reader)
+	presignatureID, err := types.NewRID(r.Rand())
 	if err != nil {
 		return r, err
 	}
-	commitmentID, decommitmentID, err := r.HashForID(r.SelfID()).Commit(presignatureID)
+	commitmentID, decommitmentID, err := r.HashForID(r.SelfID()).Commit(r.Rand(), presignatureID)
 	if err != nil {
 		return r, err
 	}
@@ -102,7 +100,7 @@ func (r *presign1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}
 	errs := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
 		j := otherIDs[i]
-		proof := zkencelg.NewProof(r.Group(), r.HashForID(r.SelfID()), zkencelg.Public{
+		proof := zkencelg.NewProof(r.Rand(), r.Group(), r.HashForID(r.SelfID()), zkencelg.Public{
 			C:      K,
 			A:      r.ElGamal[r.SelfID()],
 			B:      ElGamalK.L,
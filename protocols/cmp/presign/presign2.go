@@ -1,7 +1,7 @@
 package presign
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/elgamal"
@@ -112,7 +112,7 @@ func (r *presign2) VerifyMessage(msg round.Message) error {
 		Prover: r.Paillier[from],
 		Aux:    r.Pedersen[to],
 	}) {
-		return errors.New("failed to validate enc-elg proof for K")
+		return fmt.Errorf("failed to validate enc-elg proof for K: %w", round.ErrInvalidProof)
 	}
 	return nil
 }
@@ -140,11 +140,11 @@ func (r *presign2) Finalize(out chan<- *round.Message) (round.Session, error) {
 	mtaOuts := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
 		j := otherIDs[i]
 
-		DeltaBeta, DeltaD, DeltaF, DeltaProof := mta.ProveAffP(r.Group(), r.HashForID(r.SelfID()),
+		DeltaBeta, DeltaD, DeltaF, DeltaProof := mta.ProveAffP(r.Rand(), r.Group(), r.HashForID(r.SelfID()),
 			r.GammaShare, r.G[r.SelfID()], r.GNonce, r.K[j],
 			r.SecretPaillier, r.Paillier[j], r.Pedersen[j])
 
-		ChiBeta, ChiD, ChiF, ChiProof := mta.ProveAffG(r.Group(), r.HashForID(r.SelfID()),
+		ChiBeta, ChiD, ChiF, ChiProof := mta.ProveAffG(r.Rand(), r.Group(), r.HashForID(r.SelfID()),
 			curve.MakeInt(r.SecretECDSA), r.ECDSA[r.SelfID()], r.K[j],
 			r.SecretPaillier, r.Paillier[j], r.Pedersen[j])
 
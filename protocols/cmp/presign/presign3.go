@@ -1,7 +1,6 @@
 package presign
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/cronokirby/saferith"
@@ -63,7 +62,7 @@ func (r *presign3) StoreBroadcastMessage(msg round.Message) error {
 		}
 		DeltaCiphertext, ChiCiphertext := body.DeltaCiphertext[id], body.ChiCiphertext[id]
 		if !r.Paillier[id].ValidateCiphertexts(DeltaCiphertext, ChiCiphertext) {
-			return errors.New("received invalid ciphertext")
+			return fmt.Errorf("received invalid ciphertext: %w", round.ErrMalformed)
 		}
 	}
 
@@ -91,7 +90,7 @@ func (r *presign3) VerifyMessage(msg round.Message) error {
 		Verifier: r.Paillier[to],
 		Aux:      r.Pedersen[to],
 	}) {
-		return errors.New("failed to validate affp proof for Delta MtA")
+		return fmt.Errorf("failed to validate affp proof for Delta MtA: %w", round.ErrInvalidProof)
 	}
 
 	if !body.ChiProof.Verify(r.HashForID(from), zkaffg.Public{
@@ -103,7 +102,7 @@ func (r *presign3) VerifyMessage(msg round.Message) error {
 		Verifier: r.Paillier[to],
 		Aux:      r.Pedersen[to],
 	}) {
-		return errors.New("failed to validate affg proof for Chi MtA")
+		return fmt.Errorf("failed to validate affg proof for Chi MtA: %w", round.ErrInvalidProof)
 	}
 
 	return nil
@@ -158,12 +157,12 @@ func (r *presign3) Finalize(out chan<- *round.Message) (round.Session, error) {
 		ChiShare.Add(ChiShare, r.ChiShareBeta[j], -1)
 	}
 	if culprits != nil {
-		return r.AbortRound(fmt.Errorf("failed to decrypt alpha shares for mta"), culprits...), nil
+		return r.AbortRound(fmt.Errorf("failed to decrypt alpha shares for mta: %w", round.ErrInvalidShare), culprits...), nil
 	}
 
 	// ElGamalChi = Ẑⱼ = (b̂ⱼ⋅G, χᵢ+b̂ⱼ⋅Yᵢ)
 	// ElGamalChiNonce = b̂ⱼ
-	ElGamalChi, ElGamalChiNonce := elgamal.Encrypt(r.ElGamal[r.SelfID()], r.Group().NewScalar().SetNat(ChiShare.Mod(r.Group().Order())))
+	ElGamalChi, ElGamalChiNonce := elgamal.Encrypt(r.Rand(), r.ElGamal[r.SelfID()], r.Group().NewScalar().SetNat(ChiShare.Mod(r.Group().Order())))
 
 	DeltaShareScalar := r.Group().NewScalar().SetNat(DeltaShare.Mod(r.Group().Order()))
 
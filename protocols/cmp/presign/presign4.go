@@ -83,7 +83,7 @@ func (r *presign4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	errors := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
 		j := otherIDs[i]
 
-		proofLog := zklogstar.NewProof(r.Group(), r.HashForID(r.SelfID()), zklogstar.Public{
+		proofLog := zklogstar.NewProof(r.Rand(), r.Group(), r.HashForID(r.SelfID()), zklogstar.Public{
 			C:      r.G[r.SelfID()],
 			X:      BigGammaShare,
 			Prover: r.Paillier[r.SelfID()],
@@ -1,7 +1,7 @@
 package presign
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -58,7 +58,7 @@ func (r *presign5) VerifyMessage(msg round.Message) error {
 		Prover: r.Paillier[from],
 		Aux:    r.Pedersen[to],
 	}) {
-		return errors.New("failed to validate log* proof for BigGammaShare")
+		return fmt.Errorf("failed to validate log* proof for BigGammaShare: %w", round.ErrInvalidProof)
 	}
 
 	return nil
@@ -81,7 +81,7 @@ func (r *presign5) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// Δᵢ = kᵢ⋅Γ
 	BigDeltaShare := r.KShare.Act(Gamma)
 
-	proofLog := zkelog.NewProof(r.Group(), r.HashForID(r.SelfID()),
+	proofLog := zkelog.NewProof(r.Rand(), r.Group(), r.HashForID(r.SelfID()),
 		zkelog.Public{
 			E:             r.ElGamalK[r.SelfID()],
 			ElGamalPublic: r.ElGamal[r.SelfID()],
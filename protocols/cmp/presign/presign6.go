@@ -1,7 +1,7 @@
 package presign
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
@@ -49,7 +49,7 @@ func (r *presign6) StoreBroadcastMessage(msg round.Message) error {
 		Base:          r.Gamma,
 		Y:             body.BigDeltaShare,
 	}) {
-		return errors.New("failed to validate elog proof for BigDeltaShare")
+		return fmt.Errorf("failed to validate elog proof for BigDeltaShare: %w", round.ErrInvalidProof)
 	}
 
 	r.BigDeltaShares[from] = body.BigDeltaShare
@@ -95,11 +95,11 @@ func (r *presign6) Finalize(out chan<- *round.Message) (round.Session, error) {
 		DeltaProofs := make(map[party.ID]*abortNth, r.N()-1)
 		for _, j := range r.OtherPartyIDs() {
 			deltaCiphertext := r.DeltaCiphertext[j][r.SelfID()] // Dᵢⱼ
-			DeltaProofs[j] = proveNth(r.HashForID(r.SelfID()), r.SecretPaillier, deltaCiphertext)
+			DeltaProofs[j] = proveNth(r.Rand(), r.HashForID(r.SelfID()), r.SecretPaillier, deltaCiphertext)
 		}
 		msg := &broadcastAbort1{
 			GammaShare:  r.GammaShare,
-			KProof:      proveNth(r.HashForID(r.SelfID()), r.SecretPaillier, r.K[r.SelfID()]),
+			KProof:      proveNth(r.Rand(), r.HashForID(r.SelfID()), r.SecretPaillier, r.K[r.SelfID()]),
 			DeltaProofs: DeltaProofs,
 		}
 		if err := r.BroadcastMessage(out, msg); err != nil {
@@ -122,7 +122,7 @@ func (r *presign6) Finalize(out chan<- *round.Message) (round.Session, error) {
 		RBar[j] = DeltaInv.Act(BigDeltaJ)
 	}
 
-	proof := zkelog.NewProof(r.Group(), r.HashForID(r.SelfID()), zkelog.Public{
+	proof := zkelog.NewProof(r.Rand(), r.Group(), r.HashForID(r.SelfID()), zkelog.Public{
 		E:             r.ElGamalChi[r.SelfID()],
 		ElGamalPublic: r.ElGamal[r.SelfID()],
 		Base:          R,
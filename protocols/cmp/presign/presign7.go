@@ -1,7 +1,7 @@
 package presign
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
@@ -60,7 +60,7 @@ func (r *presign7) StoreBroadcastMessage(msg round.Message) error {
 		return err
 	}
 	if !r.HashForID(from).Decommit(r.CommitmentID[from], body.DecommitmentID, body.PresignatureID) {
-		return errors.New("failed to decommit presignature ID")
+		return fmt.Errorf("failed to decommit presignature ID: %w", round.ErrInvalidCommitment)
 	}
 
 	if !body.Proof.Verify(r.HashForID(from), zkelog.Public{
@@ -69,7 +69,7 @@ func (r *presign7) StoreBroadcastMessage(msg round.Message) error {
 		Base:          r.R,
 		Y:             body.S,
 	}) {
-		return errors.New("failed to validate elog proof for S")
+		return fmt.Errorf("failed to validate elog proof for S: %w", round.ErrInvalidProof)
 	}
 	r.S[from] = body.S
 	r.PresignatureID[from] = body.PresignatureID
@@ -101,7 +101,7 @@ func (r *presign7) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// ∑ⱼ Sⱼ ?= X
 	if !r.PublicKey.Equal(PublicKeyComputed) {
 		YHat := r.ElGamalChiNonce.Act(r.ElGamal[r.SelfID()])
-		YHatProof := zklog.NewProof(r.Group(), r.HashForID(r.SelfID()), zklog.Public{
+		YHatProof := zklog.NewProof(r.Rand(), r.Group(), r.HashForID(r.SelfID()), zklog.Public{
 			H: r.ElGamalChiNonce.ActOnBase(),
 			X: r.ElGamal[r.SelfID()],
 			Y: YHat,
@@ -113,12 +113,12 @@ func (r *presign7) Finalize(out chan<- *round.Message) (round.Session, error) {
 		ChiProofs := make(map[party.ID]*abortNth, r.N()-1)
 		for _, j := range r.OtherPartyIDs() {
 			chiCiphertext := r.ChiCiphertext[j][r.SelfID()] // D̂ᵢⱼ
-			ChiProofs[j] = proveNth(r.HashForID(r.SelfID()), r.SecretPaillier, chiCiphertext)
+			ChiProofs[j] = proveNth(r.Rand(), r.HashForID(r.SelfID()), r.SecretPaillier, chiCiphertext)
 		}
 		msg := &broadcastAbort2{
 			YHat:      YHat,
 			YHatProof: YHatProof,
-			KProof:    proveNth(r.HashForID(r.SelfID()), r.SecretPaillier, r.K[r.SelfID()]),
+			KProof:    proveNth(r.Rand(), r.HashForID(r.SelfID()), r.SecretPaillier, r.K[r.SelfID()]),
 			ChiProofs: ChiProofs,
 		}
 		if err := r.BroadcastMessage(out, msg); err != nil {
@@ -3,6 +3,7 @@ package presign
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
@@ -26,7 +27,7 @@ const (
 	protocolFullRounds    round.Number = 8
 )
 
-func StartPresign(c *config.Config, signers []party.ID, message []byte, pl *pool.Pool) protocol.StartFunc {
+func StartPresign(c *config.Config, signers []party.ID, message []byte, pl *pool.Pool, rand io.Reader) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		if c == nil {
 			return nil, errors.New("presign: config is nil")
@@ -37,6 +38,7 @@ func StartPresign(c *config.Config, signers []party.ID, message []byte, pl *pool
 			PartyIDs:  signers,
 			Threshold: c.Threshold,
 			Group:     c.Group,
+			Rand:      rand,
 		}
 		if len(message) == 0 {
 			info.FinalRoundNumber = protocolOfflineRounds
@@ -91,7 +93,7 @@ func StartPresign(c *config.Config, signers []party.ID, message []byte, pl *pool
 	}
 }
 
-func StartPresignOnline(c *config.Config, preSignature *ecdsa.PreSignature, message []byte, pl *pool.Pool) protocol.StartFunc {
+func StartPresignOnline(c *config.Config, preSignature *ecdsa.PreSignature, message []byte, pl *pool.Pool, rand io.Reader) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		if c == nil || preSignature == nil {
 			return nil, errors.New("presign: config or preSignature is nil")
@@ -118,6 +120,7 @@ func StartPresignOnline(c *config.Config, preSignature *ecdsa.PreSignature, mess
 			PartyIDs:         signers,
 			Threshold:        c.Threshold,
 			Group:            c.Group,
+			Rand:             rand,
 		}
 
 		helper, err := round.NewSession(
@@ -1,7 +1,7 @@
 package presign
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -57,7 +57,7 @@ func (r *sign2) Finalize(chan<- *round.Message) (round.Session, error) {
 	}
 
 	culprits := r.PreSignature.VerifySignatureShares(r.SigmaShares, r.Message)
-	return r.AbortRound(errors.New("signature failed to verify"), culprits...), nil
+	return r.AbortRound(fmt.Errorf("signature failed to verify: %w", round.ErrInvalidShare), culprits...), nil
 }
 
 // MessageContent implements round.Round.
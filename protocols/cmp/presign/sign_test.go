@@ -1,6 +1,7 @@
 package presign
 
 import (
+	"crypto/rand"
 	mrand "math/rand"
 	"testing"
 
@@ -22,34 +23,48 @@ var (
 	oneInt      = new(saferith.Int).SetNat(oneNat)
 	minusOneInt = new(saferith.Int).SetNat(oneNat).Neg(1)
 
-	N           = 4
-	T           = N - 1
-	group       = curve.Secp256k1{}
-	configs     map[party.ID]*config.Config
-	partyIDs    party.IDSlice
-	messageHash []byte
+	N = 4
+	T = N - 1
+	// curveConfigs holds a set of configs for each curve in test.Curves, indexed by name.
+	curveConfigs = make(map[string]map[party.ID]*config.Config, len(test.Curves))
+	configs      map[party.ID]*config.Config
+	partyIDs     party.IDSlice
+	messageHash  []byte
 )
 
 func init() {
 	source := mrand.New(mrand.NewSource(1))
 	pl := pool.NewPool(0)
 	defer pl.TearDown()
-	configs, partyIDs = test.GenerateConfig(group, N, T, source, pl)
-	for id, c := range configs {
-		configs[id], _ = c.DeriveBIP32(0)
+	for _, group := range test.Curves {
+		var groupConfigs map[party.ID]*config.Config
+		groupConfigs, partyIDs = test.GenerateConfig(group, N, T, source, pl)
+		// BIP-32 derivation is only defined over secp256k1
+		if _, ok := group.(curve.Secp256k1); ok {
+			for id, c := range groupConfigs {
+				groupConfigs[id], _ = c.DeriveBIP32(0)
+			}
+		}
+		curveConfigs[group.Name()] = groupConfigs
 	}
+	configs = curveConfigs[curve.Secp256k1{}.Name()]
 
 	messageHash = make([]byte, 64)
 	sha3.ShakeSum128(messageHash, []byte("hello"))
 }
 
 func TestRound(t *testing.T) {
+	for _, group := range test.Curves {
+		t.Run(group.Name(), func(t *testing.T) { testRound(t, curveConfigs[group.Name()]) })
+	}
+}
 
+func testRound(t *testing.T, configs map[party.ID]*config.Config) {
 	rounds := make([]round.Session, 0, N)
 	for _, c := range configs {
 		pl := pool.NewPool(1)
 		defer pl.TearDown()
-		r, err := StartPresign(c, partyIDs, messageHash, pl)(nil)
+		r, err := StartPresign(c, partyIDs, messageHash, pl, rand.Reader)(nil)
 		require.NoError(t, err, "round creation should not result in an error")
 		rounds = append(rounds, r)
 	}
@@ -65,6 +80,60 @@ func TestRound(t *testing.T) {
 		assert.IsType(t, &round.Output{}, r)
 		signature, ok := r.(*round.Output).Result.(*ecdsa.Signature)
 		assert.True(t, ok, "result should *ecdsa.Signature")
-		assert.True(t, signature.Verify(configs[r.SelfID()].PublicPoint(), messageHash))
+		publicPoint := configs[r.SelfID()].PublicPoint()
+		assert.True(t, signature.Verify(publicPoint, messageHash))
+		if valid, ok := test.VerifyStdlibECDSA(publicPoint, signature.R, signature.S, messageHash); ok {
+			assert.True(t, valid, "expected valid signature with crypto/ecdsa")
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	run := func(start func(i int) (round.Session, error)) []round.Session {
+		rounds := make([]round.Session, 0, N)
+		for i := range partyIDs {
+			r, err := start(i)
+			require.NoError(t, err, "round creation should not result in an error")
+			rounds = append(rounds, r)
+		}
+		for {
+			err, done := test.Rounds(rounds, nil)
+			require.NoError(t, err, "failed to process round")
+			if done {
+				break
+			}
+			// continue with rounds restored from their snapshots
+			require.NoError(t, test.Restore(rounds, start), "failed to restore round")
+		}
+		return rounds
+	}
+
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+	for _, r := range run(func(i int) (round.Session, error) {
+		return StartPresign(configs[partyIDs[i]], partyIDs, messageHash, pl, rand.Reader)([]byte("session"))
+	}) {
+		signature, ok := r.(*round.Output).Result.(*ecdsa.Signature)
+		require.True(t, ok, "result should *ecdsa.Signature")
+		assert.True(t, signature.Verify(publicPoint, messageHash))
+	}
+
+	var preSignatures []*ecdsa.PreSignature
+	for _, r := range run(func(i int) (round.Session, error) {
+		return StartPresign(configs[partyIDs[i]], partyIDs, nil, pl, rand.Reader)([]byte("session"))
+	}) {
+		preSignature, ok := r.(*round.Output).Result.(*ecdsa.PreSignature)
+		require.True(t, ok, "result should *ecdsa.PreSignature")
+		preSignatures = append(preSignatures, preSignature)
+	}
+
+	for _, r := range run(func(i int) (round.Session, error) {
+		return StartPresignOnline(configs[partyIDs[i]], preSignatures[i], messageHash, pl, rand.Reader)([]byte("session"))
+	}) {
+		signature, ok := r.(*round.Output).Result.(*ecdsa.Signature)
+		require.True(t, ok, "result should *ecdsa.Signature")
+		assert.True(t, signature.Verify(publicPoint, messageHash))
 	}
 }
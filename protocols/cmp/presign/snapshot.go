@@ -0,0 +1,302 @@
+package presign
+
+import (
+	"fmt"
+
+	"github.com/cronokirby/saferith"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/snapshot"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// state is the serialized form of the rounds of this protocol.
+//
+// The fields of presign1 and sign1 are set by the StartFunc, so only the values computed afterwards are included.
+type state struct {
+	Hash []hash.BytesWithDomain
+	// Abort is set when rounds 7 and 8 are abort1 and abort2.
+	Abort bool
+	// presign2
+	K, G           map[party.ID]*paillier.Ciphertext
+	GammaShare     *saferith.Int
+	KShare         []byte
+	KNonce         *saferith.Nat
+	GNonce         *saferith.Nat
+	ElGamalKNonce  []byte
+	ElGamalK       map[party.ID][]byte
+	PresignatureID map[party.ID]types.RID
+	CommitmentID   map[party.ID]hash.Commitment
+	DecommitmentID hash.Decommitment
+	// presign3
+	DeltaShareBeta, ChiShareBeta   map[party.ID]*saferith.Int
+	DeltaCiphertext, ChiCiphertext map[party.ID]map[party.ID]*paillier.Ciphertext
+	// presign4
+	DeltaShareAlpha, ChiShareAlpha map[party.ID]*saferith.Int
+	ElGamalChiNonce                []byte
+	ElGamalChi                     map[party.ID][]byte
+	DeltaShares                    map[party.ID][]byte
+	ChiShare                       []byte
+	// presign5
+	BigGammaShare map[party.ID][]byte
+	// presign6
+	BigDeltaShares map[party.ID][]byte
+	Gamma          []byte
+	// presign7
+	Delta []byte
+	S     map[party.ID][]byte
+	R     []byte
+	RBar  map[party.ID][]byte
+	// abort1
+	GammaShares map[party.ID]*saferith.Int
+	KSharesInt  map[party.ID]*saferith.Int
+	DeltaAlphas map[party.ID]map[party.ID]*saferith.Int
+	// abort2
+	YHat      map[party.ID][]byte
+	KShares   map[party.ID][]byte
+	ChiAlphas map[party.ID]map[party.ID][]byte
+	// sign2, which starts from a PreSignature when it follows presign7
+	PreSignature []byte
+	SigmaShares  map[party.ID][]byte
+}
+
+// snapshotter is implemented by each round, which adds its own fields to those of the previous round.
+type snapshotter interface {
+	snapshot(s *state, e *snapshot.Encoder)
+}
+
+func (r *presign1) snapshot(s *state, _ *snapshot.Encoder) {
+	s.Hash = r.HashUpdates()
+}
+
+func (r *presign2) snapshot(s *state, e *snapshot.Encoder) {
+	r.presign1.snapshot(s, e)
+	s.K = r.K
+	s.G = r.G
+	s.GammaShare = r.GammaShare
+	s.KShare = e.Scalar(r.KShare)
+	s.KNonce = r.KNonce
+	s.GNonce = r.GNonce
+	s.ElGamalKNonce = e.Scalar(r.ElGamalKNonce)
+	s.ElGamalK = e.ElGamalCiphertexts(r.ElGamalK)
+	s.PresignatureID = r.PresignatureID
+	s.CommitmentID = r.CommitmentID
+	s.DecommitmentID = r.DecommitmentID
+}
+
+func (r *presign3) snapshot(s *state, e *snapshot.Encoder) {
+	r.presign2.snapshot(s, e)
+	s.DeltaShareBeta = r.DeltaShareBeta
+	s.ChiShareBeta = r.ChiShareBeta
+	s.DeltaCiphertext = r.DeltaCiphertext
+	s.ChiCiphertext = r.ChiCiphertext
+}
+
+func (r *presign4) snapshot(s *state, e *snapshot.Encoder) {
+	r.presign3.snapshot(s, e)
+	s.DeltaShareAlpha = r.DeltaShareAlpha
+	s.ChiShareAlpha = r.ChiShareAlpha
+	s.ElGamalChiNonce = e.Scalar(r.ElGamalChiNonce)
+	s.ElGamalChi = e.ElGamalCiphertexts(r.ElGamalChi)
+	s.DeltaShares = e.Scalars(r.DeltaShares)
+	s.ChiShare = e.Scalar(r.ChiShare)
+}
+
+func (r *presign5) snapshot(s *state, e *snapshot.Encoder) {
+	r.presign4.snapshot(s, e)
+	s.BigGammaShare = e.Points(r.BigGammaShare)
+}
+
+func (r *presign6) snapshot(s *state, e *snapshot.Encoder) {
+	r.presign5.snapshot(s, e)
+	s.BigDeltaShares = e.Points(r.BigDeltaShares)
+	s.Gamma = e.Point(r.Gamma)
+}
+
+func (r *presign7) snapshot(s *state, e *snapshot.Encoder) {
+	r.presign6.snapshot(s, e)
+	s.Delta = e.Scalar(r.Delta)
+	s.S = e.Points(r.S)
+	s.R = e.Point(r.R)
+	s.RBar = e.Points(r.RBar)
+}
+
+func (r *abort1) snapshot(s *state, e *snapshot.Encoder) {
+	r.presign6.snapshot(s, e)
+	s.Abort = true
+	s.GammaShares = r.GammaShares
+	s.KSharesInt = r.KShares
+	s.DeltaAlphas = r.DeltaAlphas
+}
+
+func (r *abort2) snapshot(s *state, e *snapshot.Encoder) {
+	r.presign7.snapshot(s, e)
+	s.Abort = true
+	s.YHat = e.Points(r.YHat)
+	s.KShares = e.Scalars(r.KShares)
+	s.ChiAlphas = make(map[party.ID]map[party.ID][]byte, len(r.ChiAlphas))
+	for j, alphas := range r.ChiAlphas {
+		s.ChiAlphas[j] = e.Scalars(alphas)
+	}
+}
+
+func (r *sign1) snapshot(s *state, _ *snapshot.Encoder) {
+	s.Hash = r.HashUpdates()
+}
+
+func (r *sign2) snapshot(s *state, e *snapshot.Encoder) {
+	r.sign1.snapshot(s, e)
+	s.PreSignature = e.PreSignature(r.PreSignature)
+	s.SigmaShares = e.Scalars(r.SigmaShares)
+}
+
+// Snapshot implements round.Restorer.
+func (r *presign1) Snapshot(current round.Session) ([]byte, error) {
+	return marshalState(current)
+}
+
+// Snapshot implements round.Restorer.
+func (r *sign1) Snapshot(current round.Session) ([]byte, error) {
+	return marshalState(current)
+}
+
+func marshalState(current round.Session) ([]byte, error) {
+	c, ok := current.(snapshotter)
+	if !ok {
+		return nil, round.ErrNoSnapshot
+	}
+	var (
+		s state
+		e snapshot.Encoder
+	)
+	c.snapshot(&s, &e)
+	if err := e.Err(); err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(&s)
+}
+
+// Restore implements round.Restorer.
+func (r *presign1) Restore(number round.Number, data []byte) (round.Session, error) {
+	var s state
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if err := r.RestoreHashUpdates(s.Hash); err != nil {
+		return nil, err
+	}
+	d := snapshot.NewDecoder(r.Group())
+	r2 := &presign2{
+		presign1:       r,
+		K:              s.K,
+		G:              s.G,
+		GammaShare:     s.GammaShare,
+		KShare:         d.Scalar(s.KShare),
+		KNonce:         s.KNonce,
+		GNonce:         s.GNonce,
+		ElGamalKNonce:  d.Scalar(s.ElGamalKNonce),
+		ElGamalK:       d.ElGamalCiphertexts(s.ElGamalK),
+		PresignatureID: s.PresignatureID,
+		CommitmentID:   s.CommitmentID,
+		DecommitmentID: s.DecommitmentID,
+	}
+	r3 := &presign3{
+		presign2:        r2,
+		DeltaShareBeta:  s.DeltaShareBeta,
+		ChiShareBeta:    s.ChiShareBeta,
+		DeltaCiphertext: s.DeltaCiphertext,
+		ChiCiphertext:   s.ChiCiphertext,
+	}
+	r4 := &presign4{
+		presign3:        r3,
+		DeltaShareAlpha: s.DeltaShareAlpha,
+		ChiShareAlpha:   s.ChiShareAlpha,
+		ElGamalChiNonce: d.Scalar(s.ElGamalChiNonce),
+		ElGamalChi:      d.ElGamalCiphertexts(s.ElGamalChi),
+		DeltaShares:     d.Scalars(s.DeltaShares),
+		ChiShare:        d.Scalar(s.ChiShare),
+	}
+	r5 := &presign5{
+		presign4:      r4,
+		BigGammaShare: d.Points(s.BigGammaShare),
+	}
+	r6 := &presign6{
+		presign5:       r5,
+		BigDeltaShares: d.Points(s.BigDeltaShares),
+		Gamma:          d.Point(s.Gamma),
+	}
+	r7 := &presign7{
+		presign6: r6,
+		Delta:    d.Scalar(s.Delta),
+		S:        d.Points(s.S),
+		R:        d.Point(s.R),
+		RBar:     d.Points(s.RBar),
+	}
+	rounds := []round.Session{r, r2, r3, r4, r5, r6, r7}
+	switch {
+	case s.Abort:
+		ChiAlphas := make(map[party.ID]map[party.ID]curve.Scalar, len(s.ChiAlphas))
+		for j, alphas := range s.ChiAlphas {
+			ChiAlphas[j] = d.Scalars(alphas)
+		}
+		rounds[6] = &abort1{
+			presign6:    r6,
+			GammaShares: s.GammaShares,
+			KShares:     s.KSharesInt,
+			DeltaAlphas: s.DeltaAlphas,
+		}
+		rounds = append(rounds, &abort2{
+			presign7:  r7,
+			YHat:      d.Points(s.YHat),
+			KShares:   d.Scalars(s.KShares),
+			ChiAlphas: ChiAlphas,
+		})
+	case s.PreSignature != nil:
+		rounds = append(rounds, &sign2{
+			sign1: &sign1{
+				Helper:       r.Helper,
+				PublicKey:    r.PublicKey,
+				Message:      r.Message,
+				PreSignature: d.PreSignature(s.PreSignature),
+			},
+			SigmaShares: d.Scalars(s.SigmaShares),
+		})
+	}
+	if err := d.Err(); err != nil {
+		return nil, err
+	}
+	if number < 1 || int(number) > len(rounds) {
+		return nil, fmt.Errorf("presign: invalid round %d", number)
+	}
+	return rounds[number-1], nil
+}
+
+// Restore implements round.Restorer.
+func (r *sign1) Restore(number round.Number, data []byte) (round.Session, error) {
+	var s state
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if err := r.RestoreHashUpdates(s.Hash); err != nil {
+		return nil, err
+	}
+	d := snapshot.NewDecoder(r.Group())
+	r2 := &sign2{
+		sign1:       r,
+		SigmaShares: d.Scalars(s.SigmaShares),
+	}
+	if err := d.Err(); err != nil {
+		return nil, err
+	}
+	switch number {
+	case r.Number():
+		return r, nil
+	case r2.Number():
+		return r2, nil
+	}
+	return nil, fmt.Errorf("presign: invalid round %d", number)
+}
@@ -1,7 +1,7 @@
 package sign
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/mta"
@@ -62,7 +62,7 @@ func (r *round2) StoreBroadcastMessage(msg round.Message) error {
 	}
 
 	if !r.Paillier[from].ValidateCiphertexts(body.K, body.G) {
-		return errors.New("invalid K, G")
+		return fmt.Errorf("invalid K, G: %w", round.ErrMalformed)
 	}
 
 	r.K[from] = body.K
@@ -90,7 +90,7 @@ func (r *round2) VerifyMessage(msg round.Message) error {
 		Prover: r.Paillier[from],
 		Aux:    r.Pedersen[to],
 	}) {
-		return errors.New("failed to validate enc proof for K")
+		return fmt.Errorf("failed to validate enc proof for K: %w", round.ErrInvalidProof)
 	}
 	return nil
 }
@@ -119,14 +119,14 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 	mtaOuts := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
 		j := otherIDs[i]
 
-		DeltaBeta, DeltaD, DeltaF, DeltaProof := mta.ProveAffG(r.Group(), r.HashForID(r.SelfID()),
+		DeltaBeta, DeltaD, DeltaF, DeltaProof := mta.ProveAffG(r.Rand(), r.Group(), r.HashForID(r.SelfID()),
 			r.GammaShare, r.BigGammaShare[r.SelfID()], r.K[j],
 			r.SecretPaillier, r.Paillier[j], r.Pedersen[j])
-		ChiBeta, ChiD, ChiF, ChiProof := mta.ProveAffG(r.Group(),
+		ChiBeta, ChiD, ChiF, ChiProof := mta.ProveAffG(r.Rand(), r.Group(),
 			r.HashForID(r.SelfID()), curve.MakeInt(r.SecretECDSA), r.ECDSA[r.SelfID()], r.K[j],
 			r.SecretPaillier, r.Paillier[j], r.Pedersen[j])
 
-		proof := zklogstar.NewProof(r.Group(), r.HashForID(r.SelfID()),
+		proof := zklogstar.NewProof(r.Rand(), r.Group(), r.HashForID(r.SelfID()),
 			zklogstar.Public{
 				C:      r.G[r.SelfID()],
 				X:      r.BigGammaShare[r.SelfID()],
@@ -1,7 +1,6 @@
 package sign
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/cronokirby/saferith"
@@ -77,7 +76,7 @@ func (r *round3) VerifyMessage(msg round.Message) error {
 		Verifier: r.Paillier[to],
 		Aux:      r.Pedersen[to],
 	}) {
-		return errors.New("failed to validate affg proof for Delta MtA")
+		return fmt.Errorf("failed to validate affg proof for Delta MtA: %w", round.ErrInvalidProof)
 	}
 
 	if !body.ChiProof.Verify(r.HashForID(from), zkaffg.Public{
@@ -89,7 +88,7 @@ func (r *round3) VerifyMessage(msg round.Message) error {
 		Verifier: r.Paillier[to],
 		Aux:      r.Pedersen[to],
 	}) {
-		return errors.New("failed to validate affg proof for Chi MtA")
+		return fmt.Errorf("failed to validate affg proof for Chi MtA: %w", round.ErrInvalidProof)
 	}
 
 	if !body.ProofLog.Verify(r.HashForID(from), zklogstar.Public{
@@ -98,7 +97,7 @@ func (r *round3) VerifyMessage(msg round.Message) error {
 		Prover: r.Paillier[from],
 		Aux:    r.Pedersen[to],
 	}) {
-		return errors.New("failed to validate log proof")
+		return fmt.Errorf("failed to validate log proof: %w", round.ErrInvalidProof)
 	}
 
 	return nil
@@ -114,12 +113,12 @@ func (r *round3) StoreMessage(msg round.Message) error {
 	// αᵢⱼ
 	DeltaShareAlpha, err := r.SecretPaillier.Dec(body.DeltaD)
 	if err != nil {
-		return fmt.Errorf("failed to decrypt alpha share for delta: %w", err)
+		return fmt.Errorf("failed to decrypt alpha share for delta: %w: %w", round.ErrInvalidShare, err)
 	}
 	// α̂ᵢⱼ
 	ChiShareAlpha, err := r.SecretPaillier.Dec(body.ChiD)
 	if err != nil {
-		return fmt.Errorf("failed to decrypt alpha share for chi: %w", err)
+		return fmt.Errorf("failed to decrypt alpha share for chi: %w: %w", round.ErrInvalidShare, err)
 	}
 
 	r.DeltaShareAlpha[from] = DeltaShareAlpha
@@ -178,7 +177,7 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	errs := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
 		j := otherIDs[i]
 
-		proofLog := zklogstar.NewProof(r.Group(), r.HashForID(r.SelfID()), zklogstar.Public{
+		proofLog := zklogstar.NewProof(r.Rand(), r.Group(), r.HashForID(r.SelfID()), zklogstar.Public{
 			C:      r.K[r.SelfID()],
 			X:      BigDeltaShare,
 			G:      Gamma,
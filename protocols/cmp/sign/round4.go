@@ -1,7 +1,7 @@
 package sign
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -72,7 +72,7 @@ func (r *round4) VerifyMessage(msg round.Message) error {
 		Aux:    r.Pedersen[to],
 	}
 	if !body.ProofLog.Verify(r.HashForID(from), zkLogPublic) {
-		return errors.New("failed to validate log proof")
+		return fmt.Errorf("failed to validate log proof: %w", round.ErrInvalidProof)
 	}
 
 	return nil
@@ -102,7 +102,7 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// Δ == [δ]G
 	deltaComputed := Delta.ActOnBase()
 	if !deltaComputed.Equal(BigDelta) {
-		return r.AbortRound(errors.New("computed Δ is inconsistent with [δ]G")), nil
+		return r.AbortRound(fmt.Errorf("computed Δ is inconsistent with [δ]G: %w", round.ErrInconsistent)), nil
 	}
 
 	deltaInv := r.Group().NewScalar().Set(Delta).Invert() // δ⁻¹
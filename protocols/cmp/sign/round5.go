@@ -1,7 +1,7 @@
 package sign
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
@@ -76,7 +76,7 @@ func (r *round5) Finalize(chan<- *round.Message) (round.Session, error) {
 	}
 
 	if !signature.Verify(r.PublicKey, r.Message) {
-		return r.AbortRound(errors.New("failed to validate signature")), nil
+		return r.AbortRound(fmt.Errorf("failed to validate signature: %w", round.ErrInvalidShare)), nil
 	}
 
 	return r.ResultRound(signature), nil
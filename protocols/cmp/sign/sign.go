@@ -3,6 +3,7 @@ package sign
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
@@ -22,7 +23,7 @@ const (
 	protocolSignRounds round.Number = 5
 )
 
-func StartSign(config *config.Config, signers []party.ID, message []byte, pl *pool.Pool) protocol.StartFunc {
+func StartSign(config *config.Config, signers []party.ID, message []byte, pl *pool.Pool, rand io.Reader) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		group := config.Group
 
@@ -38,6 +39,7 @@ func StartSign(config *config.Config, signers []party.ID, message []byte, pl *po
 			PartyIDs:         signers,
 			Threshold:        config.Threshold,
 			Group:            config.Group,
+			Rand:             rand,
 		}
 
 		helper, err := round.NewSession(info, sessionID, pl, config, types.SigningMessage(message))
@@ -1,6 +1,7 @@
 package sign
 
 import (
+	"crypto/rand"
 	mrand "math/rand"
 	"testing"
 
@@ -15,9 +16,14 @@ import (
 )
 
 func TestRound(t *testing.T) {
+	for _, group := range test.Curves {
+		t.Run(group.Name(), func(t *testing.T) { testRound(t, group) })
+	}
+}
+
+func testRound(t *testing.T, group curve.Curve) {
 	pl := pool.NewPool(0)
 	defer pl.TearDown()
-	group := curve.Secp256k1{}
 
 	N := 6
 	T := N - 1
@@ -36,7 +42,7 @@ func TestRound(t *testing.T) {
 	rounds := make([]round.Session, 0, N)
 	for _, partyID := range partyIDs {
 		c := configs[partyID]
-		r, err := StartSign(c, partyIDs, messageHash, pl)(nil)
+		r, err := StartSign(c, partyIDs, messageHash, pl, rand.Reader)(nil)
 		require.NoError(t, err, "round creation should not result in an error")
 		rounds = append(rounds, r)
 	}
@@ -55,5 +61,48 @@ func TestRound(t *testing.T) {
 		require.IsType(t, &ecdsa.Signature{}, resultRound.Result, "expected taproot signature result")
 		signature := resultRound.Result.(*ecdsa.Signature)
 		assert.True(t, signature.Verify(publicPoint, messageHash), "expected valid signature")
+		if valid, ok := test.VerifyStdlibECDSA(publicPoint, signature.R, signature.S, messageHash); ok {
+			assert.True(t, valid, "expected valid signature with crypto/ecdsa")
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	group := curve.Secp256k1{}
+	N := 3
+	T := N - 1
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+
+	messageHash := make([]byte, 64)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	start := func(i int) (round.Session, error) {
+		return StartSign(configs[partyIDs[i]], partyIDs, messageHash, pl, rand.Reader)([]byte("session"))
+	}
+	rounds := make([]round.Session, 0, N)
+	for i := range partyIDs {
+		r, err := start(i)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+		// continue with rounds restored from their snapshots
+		require.NoError(t, test.Restore(rounds, start), "failed to restore round")
+	}
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		signature := r.(*round.Output).Result.(*ecdsa.Signature)
+		assert.True(t, signature.Verify(publicPoint, messageHash), "expected valid signature")
 	}
 }
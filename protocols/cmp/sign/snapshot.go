@@ -0,0 +1,155 @@
+package sign
+
+import (
+	"fmt"
+
+	"github.com/cronokirby/saferith"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/snapshot"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// state is the serialized form of the rounds of this protocol.
+//
+// The fields of round1 are set by the StartFunc, so only the values computed afterwards are included.
+type state struct {
+	Hash []hash.BytesWithDomain
+	// round2
+	K, G          map[party.ID]*paillier.Ciphertext
+	BigGammaShare map[party.ID][]byte
+	GammaShare    *saferith.Int
+	KShare        []byte
+	KNonce        *saferith.Nat
+	GNonce        *saferith.Nat
+	// round3
+	DeltaShareAlpha, DeltaShareBeta map[party.ID]*saferith.Int
+	ChiShareAlpha, ChiShareBeta     map[party.ID]*saferith.Int
+	// round4
+	DeltaShares    map[party.ID][]byte
+	BigDeltaShares map[party.ID][]byte
+	Gamma          []byte
+	ChiShare       []byte
+	// round5
+	SigmaShares map[party.ID][]byte
+	Delta       []byte
+	BigDelta    []byte
+	BigR        []byte
+	R           []byte
+}
+
+// snapshotter is implemented by each round, which adds its own fields to those of the previous round.
+type snapshotter interface {
+	snapshot(s *state, e *snapshot.Encoder)
+}
+
+func (r *round1) snapshot(s *state, _ *snapshot.Encoder) {
+	s.Hash = r.HashUpdates()
+}
+
+func (r *round2) snapshot(s *state, e *snapshot.Encoder) {
+	r.round1.snapshot(s, e)
+	s.K = r.K
+	s.G = r.G
+	s.BigGammaShare = e.Points(r.BigGammaShare)
+	s.GammaShare = r.GammaShare
+	s.KShare = e.Scalar(r.KShare)
+	s.KNonce = r.KNonce
+	s.GNonce = r.GNonce
+}
+
+func (r *round3) snapshot(s *state, e *snapshot.Encoder) {
+	r.round2.snapshot(s, e)
+	s.DeltaShareAlpha = r.DeltaShareAlpha
+	s.DeltaShareBeta = r.DeltaShareBeta
+	s.ChiShareAlpha = r.ChiShareAlpha
+	s.ChiShareBeta = r.ChiShareBeta
+}
+
+func (r *round4) snapshot(s *state, e *snapshot.Encoder) {
+	r.round3.snapshot(s, e)
+	s.DeltaShares = e.Scalars(r.DeltaShares)
+	s.BigDeltaShares = e.Points(r.BigDeltaShares)
+	s.Gamma = e.Point(r.Gamma)
+	s.ChiShare = e.Scalar(r.ChiShare)
+}
+
+func (r *round5) snapshot(s *state, e *snapshot.Encoder) {
+	r.round4.snapshot(s, e)
+	s.SigmaShares = e.Scalars(r.SigmaShares)
+	s.Delta = e.Scalar(r.Delta)
+	s.BigDelta = e.Point(r.BigDelta)
+	s.BigR = e.Point(r.BigR)
+	s.R = e.Scalar(r.R)
+}
+
+// Snapshot implements round.Restorer.
+func (r *round1) Snapshot(current round.Session) ([]byte, error) {
+	c, ok := current.(snapshotter)
+	if !ok {
+		return nil, round.ErrNoSnapshot
+	}
+	var (
+		s state
+		e snapshot.Encoder
+	)
+	c.snapshot(&s, &e)
+	if err := e.Err(); err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(&s)
+}
+
+// Restore implements round.Restorer.
+func (r *round1) Restore(number round.Number, data []byte) (round.Session, error) {
+	var s state
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if err := r.RestoreHashUpdates(s.Hash); err != nil {
+		return nil, err
+	}
+	d := snapshot.NewDecoder(r.Group())
+	r2 := &round2{
+		round1:        r,
+		K:             s.K,
+		G:             s.G,
+		BigGammaShare: d.Points(s.BigGammaShare),
+		GammaShare:    s.GammaShare,
+		KShare:        d.Scalar(s.KShare),
+		KNonce:        s.KNonce,
+		GNonce:        s.GNonce,
+	}
+	r3 := &round3{
+		round2:          r2,
+		DeltaShareAlpha: s.DeltaShareAlpha,
+		DeltaShareBeta:  s.DeltaShareBeta,
+		ChiShareAlpha:   s.ChiShareAlpha,
+		ChiShareBeta:    s.ChiShareBeta,
+	}
+	r4 := &round4{
+		round3:         r3,
+		DeltaShares:    d.Scalars(s.DeltaShares),
+		BigDeltaShares: d.Points(s.BigDeltaShares),
+		Gamma:          d.Point(s.Gamma),
+		ChiShare:       d.Scalar(s.ChiShare),
+	}
+	r5 := &round5{
+		round4:      r4,
+		SigmaShares: d.Scalars(s.SigmaShares),
+		Delta:       d.Scalar(s.Delta),
+		BigDelta:    d.Point(s.BigDelta),
+		BigR:        d.Point(s.BigR),
+		R:           d.Scalar(s.R),
+	}
+	if err := d.Err(); err != nil {
+		return nil, err
+	}
+	rounds := []round.Session{r, r2, r3, r4, r5}
+	if number < 1 || int(number) > len(rounds) {
+		return nil, fmt.Errorf("sign: invalid round %d", number)
+	}
+	return rounds[number-1], nil
+}
@@ -1,10 +1,13 @@
 package doerner
 
 import (
+	"errors"
+
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/recovery"
 	"github.com/taurusgroup/multi-party-sig/protocols/doerner/keygen"
 	"github.com/taurusgroup/multi-party-sig/protocols/doerner/sign"
 )
@@ -16,20 +19,42 @@ type (
 
 // EmptyConfigReceiver creates a ConfigReceiever that's ready to be unmarshalled.
 //
-// Because ConfigReceiver contains group dependent data, it needs to be initialized
-// with a concrete group to be unmarshalled correctly.
+// Because ConfigReceiver contains group dependent data, encodings without a curve name
+// need it to be initialized with a concrete group to be unmarshalled correctly.
+// Otherwise, UnmarshalConfigReceiver finds the group by itself.
 func EmptyConfigReceiver(group curve.Curve) *ConfigReceiver {
 	return &ConfigReceiver{SecretShare: group.NewScalar(), Public: group.NewPoint()}
 }
 
 // EmptyConfigSender creates a ConfigSender that's ready to be unmarshalled.
 //
-// Because ConfigSender contains group dependent data, it needs to be initialized
-// with a concrete group to be unmarshalled correctly.
+// Because ConfigSender contains group dependent data, encodings without a curve name
+// need it to be initialized with a concrete group to be unmarshalled correctly.
+// Otherwise, UnmarshalConfigSender finds the group by itself.
 func EmptyConfigSender(group curve.Curve) *ConfigSender {
 	return &ConfigSender{SecretShare: group.NewScalar(), Public: group.NewPoint()}
 }
 
+// UnmarshalConfigReceiver decodes a ConfigReceiver produced by ConfigReceiver.MarshalBinary,
+// using the curve name embedded in the data to find the group.
+func UnmarshalConfigReceiver(data []byte) (*ConfigReceiver, error) {
+	c := new(ConfigReceiver)
+	if err := c.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UnmarshalConfigSender decodes a ConfigSender produced by ConfigSender.MarshalBinary,
+// using the curve name embedded in the data to find the group.
+func UnmarshalConfigSender(data []byte) (*ConfigSender, error) {
+	c := new(ConfigSender)
+	if err := c.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 // Keygen initiates the Doerner key generation protocol.
 //
 // The goal of this protocol is to create a new key-pair, with the private portion
@@ -40,8 +65,8 @@ func EmptyConfigSender(group curve.Curve) *ConfigSender {
 // a ConfigReceiver, but the Sender will get a ConfigSender instead.
 //
 // A pool can be passed to this function, to parallelize certain operations and improve performance.
-func Keygen(group curve.Curve, receiver bool, selfID, otherID party.ID, pl *pool.Pool) protocol.StartFunc {
-	return keygen.StartKeygen(group, receiver, selfID, otherID, nil, nil, pl)
+func Keygen(group curve.Curve, receiver bool, selfID, otherID party.ID, pl *pool.Pool, opts ...protocol.StartOption) protocol.StartFunc {
+	return keygen.StartKeygen(group, receiver, selfID, otherID, nil, nil, pl, protocol.Rand(opts...))
 }
 
 // RefreshReceiver initiates a key-refresh protocol, from the Receiver's perspective.
@@ -51,15 +76,15 @@ func Keygen(group curve.Curve, receiver bool, selfID, otherID party.ID, pl *pool
 //
 // This won't change the value of the public key, but it will change the value of the chaining key.
 // If this isn't desirable, then the new chain key can simply be overwritten with the previous value.
-func RefreshReceiver(config *ConfigReceiver, selfID, otherID party.ID, pl *pool.Pool) protocol.StartFunc {
-	return keygen.StartKeygen(config.Group(), true, selfID, otherID, config.SecretShare, config.Public, pl)
+func RefreshReceiver(config *ConfigReceiver, selfID, otherID party.ID, pl *pool.Pool, opts ...protocol.StartOption) protocol.StartFunc {
+	return keygen.StartKeygen(config.Group(), true, selfID, otherID, config.SecretShare, config.Public, pl, protocol.Rand(opts...))
 }
 
 // RefreshSender initiates a key-refresh protocol, from the Sender's perspective.
 //
 // See RefreshReceiver.
-func RefreshSender(config *ConfigSender, selfID, otherID party.ID, pl *pool.Pool) protocol.StartFunc {
-	return keygen.StartKeygen(config.Group(), false, selfID, otherID, config.SecretShare, config.Public, pl)
+func RefreshSender(config *ConfigSender, selfID, otherID party.ID, pl *pool.Pool, opts ...protocol.StartOption) protocol.StartFunc {
+	return keygen.StartKeygen(config.Group(), false, selfID, otherID, config.SecretShare, config.Public, pl, protocol.Rand(opts...))
 }
 
 // SignReceiver initiates the signing process, given a message hash.
@@ -70,13 +95,34 @@ func RefreshSender(config *ConfigSender, selfID, otherID party.ID, pl *pool.Pool
 // The result, in both cases, will be an ecdsa.Signature type.
 //
 // A pool can be passed to this function, to parallelize certain operations and improve performance.
-func SignReceiver(config *ConfigReceiver, selfID, otherID party.ID, hash []byte, pl *pool.Pool) protocol.StartFunc {
-	return sign.StartSignReceiver(config, selfID, otherID, hash, pl)
+func SignReceiver(config *ConfigReceiver, selfID, otherID party.ID, hash []byte, pl *pool.Pool, opts ...protocol.StartOption) protocol.StartFunc {
+	return sign.StartSignReceiver(config, selfID, otherID, hash, pl, protocol.Rand(opts...))
 }
 
 // SignSender is like SignReceiver, but using the Sender's results from key generation.
 //
 // See SignReceiver for more information.
-func SignSender(config *ConfigSender, selfID, otherID party.ID, hash []byte, pl *pool.Pool) protocol.StartFunc {
-	return sign.StartSignSender(config, selfID, otherID, hash, pl)
+func SignSender(config *ConfigSender, selfID, otherID party.ID, hash []byte, pl *pool.Pool, opts ...protocol.StartOption) protocol.StartFunc {
+	return sign.StartSignSender(config, selfID, otherID, hash, pl, protocol.Rand(opts...))
+}
+
+// ReconstructKey recovers the full private key from the configs of both participants.
+//
+// The shares are additive, so there are no public shares to check them against individually,
+// only their sum is checked against the shared public key.
+//
+// This should only be used offline, for disaster recovery, since the key is no longer protected.
+func ReconstructKey(receiver *ConfigReceiver, sender *ConfigSender) (*recovery.PrivateKey, error) {
+	if receiver == nil || sender == nil {
+		return nil, errors.New("doerner.ReconstructKey: config is nil")
+	}
+	if !receiver.Public.Equal(sender.Public) {
+		return nil, errors.New("doerner.ReconstructKey: configs belong to different keys")
+	}
+	group := receiver.Group()
+	secret := group.NewScalar().Set(receiver.SecretShare).Add(sender.SecretShare)
+	if !secret.ActOnBase().Equal(receiver.Public) {
+		return nil, errors.New("doerner.ReconstructKey: reconstructed key doesn't match the public key")
+	}
+	return &recovery.PrivateKey{Scalar: secret}, nil
 }
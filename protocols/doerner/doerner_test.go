@@ -24,14 +24,18 @@ func runHandler(wg *sync.WaitGroup, id party.ID, handler protocol.Handler, netwo
 var testGroup = curve.Secp256k1{}
 
 func runKeygen(partyIDs party.IDSlice) (*ConfigSender, *ConfigReceiver, error) {
+	return runKeygenGroup(testGroup, partyIDs)
+}
+
+func runKeygenGroup(group curve.Curve, partyIDs party.IDSlice) (*ConfigSender, *ConfigReceiver, error) {
 	pl := pool.NewPool(0)
 	defer pl.TearDown()
 
-	h0, err := protocol.NewTwoPartyHandler(Keygen(testGroup, true, partyIDs[0], partyIDs[1], pl), []byte("session"), true)
+	h0, err := protocol.NewTwoPartyHandler(Keygen(group, true, partyIDs[0], partyIDs[1], pl), []byte("session"), true)
 	if err != nil {
 		return nil, nil, err
 	}
-	h1, err := protocol.NewTwoPartyHandler(Keygen(testGroup, false, partyIDs[1], partyIDs[0], pl), []byte("session"), false)
+	h1, err := protocol.NewTwoPartyHandler(Keygen(group, false, partyIDs[1], partyIDs[0], pl), []byte("session"), false)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -168,6 +172,84 @@ func TestSign(t *testing.T) {
 	require.True(t, sig.Verify(configReceiver.Public, testHash))
 }
 
+func TestMarshal(t *testing.T) {
+	for _, group := range test.Curves {
+		t.Run(group.Name(), func(t *testing.T) {
+			partyIDs := test.PartyIDs(2)
+
+			configSender, configReceiver, err := runKeygenGroup(group, partyIDs)
+			require.NoError(t, err)
+
+			data, err := configReceiver.MarshalBinary()
+			require.NoError(t, err)
+			newConfigReceiver, err := UnmarshalConfigReceiver(data)
+			require.NoError(t, err)
+			require.Equal(t, group.Name(), newConfigReceiver.Group().Name())
+			require.NoError(t, EmptyConfigReceiver(group).UnmarshalBinary(data))
+
+			data, err = configSender.MarshalBinary()
+			require.NoError(t, err)
+			newConfigSender, err := UnmarshalConfigSender(data)
+			require.NoError(t, err)
+			require.Equal(t, group.Name(), newConfigSender.Group().Name())
+			require.NoError(t, EmptyConfigSender(group).UnmarshalBinary(data))
+
+			checkKeygenOutput(t, newConfigSender, newConfigReceiver)
+			require.True(t, newConfigSender.Public.Equal(configSender.Public))
+
+			sig, err := runSign(partyIDs, newConfigSender, newConfigReceiver)
+			require.NoError(t, err)
+			require.True(t, sig.Verify(configSender.Public, testHash))
+		})
+	}
+}
+
+func TestReconstructKey(t *testing.T) {
+	partyIDs := test.PartyIDs(2)
+
+	configSender, configReceiver, err := runKeygen(partyIDs)
+	require.NoError(t, err)
+
+	key, err := ReconstructKey(configReceiver, configSender)
+	require.NoError(t, err)
+	require.True(t, key.Scalar.ActOnBase().Equal(configSender.Public))
+
+	otherSender, _, err := runKeygen(partyIDs)
+	require.NoError(t, err)
+	_, err = ReconstructKey(configReceiver, otherSender)
+	require.Error(t, err)
+}
+
+func TestDerivePath(t *testing.T) {
+	partyIDs := test.PartyIDs(2)
+
+	configSender, configReceiver, err := runKeygen(partyIDs)
+	require.NoError(t, err)
+
+	derivedSender, err := configSender.DerivePath("m/44/60/0/0/5")
+	require.NoError(t, err)
+	derivedReceiver, err := configReceiver.DerivePath("m/44/60/0/0/5")
+	require.NoError(t, err)
+	checkKeygenOutput(t, derivedSender, derivedReceiver)
+	// only the Receiver adjusts its share, and both follow the chain key along the path
+	require.True(t, derivedSender.SecretShare.Equal(configSender.SecretShare))
+	require.Equal(t, derivedSender.ChainKey, derivedReceiver.ChainKey)
+	require.NotEqual(t, configSender.ChainKey, derivedSender.ChainKey)
+
+	sig, err := runSign(partyIDs, derivedSender, derivedReceiver)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(derivedSender.Public, testHash))
+
+	_, err = configSender.DerivePath("m/44'/60'/0'/0/5")
+	require.Error(t, err)
+
+	xpubSender, err := configSender.XPub(true)
+	require.NoError(t, err)
+	xpubReceiver, err := configReceiver.XPub(true)
+	require.NoError(t, err)
+	require.Equal(t, xpubSender, xpubReceiver)
+}
+
 func BenchmarkSign(t *testing.B) {
 	t.StopTimer()
 	partyIDs := test.PartyIDs(2)
@@ -177,3 +259,53 @@ func BenchmarkSign(t *testing.B) {
 		runSign(partyIDs, configSender, configReceiver)
 	}
 }
+
+func runRestoring(t *testing.T, partyIDs party.IDSlice, createReceiver, createSender protocol.StartFunc) (interface{}, interface{}) {
+	network := test.NewNetwork(partyIDs)
+	results := make([]interface{}, 2)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for i, create := range []protocol.StartFunc{createReceiver, createSender} {
+		go func(i int, create protocol.StartFunc) {
+			defer wg.Done()
+			h, err := protocol.NewTwoPartyHandler(create, []byte("session"), i == 0)
+			require.NoError(t, err)
+			final, err := test.HandlerLoopRestoring(partyIDs[i], h, network, func(data []byte) (protocol.Handler, error) {
+				return protocol.RestoreTwoPartyHandler(data, create)
+			})
+			require.NoError(t, err)
+			results[i], err = final.Result()
+			require.NoError(t, err)
+		}(i, create)
+	}
+	wg.Wait()
+	return results[0], results[1]
+}
+
+func TestSnapshot(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	partyIDs := test.PartyIDs(2)
+
+	r0, r1 := runRestoring(t, partyIDs,
+		Keygen(testGroup, true, partyIDs[0], partyIDs[1], pl),
+		Keygen(testGroup, false, partyIDs[1], partyIDs[0], pl))
+	require.IsType(t, &ConfigReceiver{}, r0)
+	require.IsType(t, &ConfigSender{}, r1)
+	configReceiver, configSender := r0.(*ConfigReceiver), r1.(*ConfigSender)
+	checkKeygenOutput(t, configSender, configReceiver)
+
+	r0, r1 = runRestoring(t, partyIDs,
+		RefreshReceiver(configReceiver, partyIDs[0], partyIDs[1], pl),
+		RefreshSender(configSender, partyIDs[1], partyIDs[0], pl))
+	configReceiver, configSender = r0.(*ConfigReceiver), r1.(*ConfigSender)
+	checkKeygenOutput(t, configSender, configReceiver)
+
+	r0, r1 = runRestoring(t, partyIDs,
+		SignReceiver(configReceiver, partyIDs[0], partyIDs[1], testHash, pl),
+		SignSender(configSender, partyIDs[1], partyIDs[0], testHash, pl))
+	for _, r := range []interface{}{r0, r1} {
+		require.IsType(t, &ecdsa.Signature{}, r)
+		require.True(t, r.(*ecdsa.Signature).Verify(configSender.Public, testHash))
+	}
+}
@@ -1,9 +1,9 @@
 package keygen
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/taurusgroup/multi-party-sig/internal/bip32"
 	"github.com/taurusgroup/multi-party-sig/internal/ot"
@@ -38,6 +38,8 @@ func (c *ConfigReceiver) Group() curve.Curve {
 // This can support methods like BIP32, but is more general.
 //
 // Optionally, a new chain key can be passed as well.
+//
+// Since the shares are additive, only the Receiver adds the scalar to its share.
 func (c *ConfigReceiver) Derive(adjust curve.Scalar, newChainKey []byte) (*ConfigReceiver, error) {
 	if len(newChainKey) <= 0 {
 		newChainKey = c.ChainKey
@@ -52,12 +54,13 @@ func (c *ConfigReceiver) Derive(adjust curve.Scalar, newChainKey []byte) (*Confi
 		Setup:       c.Setup,
 		SecretShare: c.SecretShare.Curve().NewScalar().Set(c.SecretShare).Add(adjust),
 		Public:      c.Public.Add(adjustG),
+		ChainKey:    newChainKey,
 	}, nil
 }
 
 // DeriveChild adjusts the shares to represent the derived public key at a certain index.
 //
-// This will panic if the group is not curve.Secp256k1
+// This returns an error if the group is not curve.Secp256k1, or if i is a hardened index.
 //
 // This derivation works according to BIP-32, see:
 // https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki
@@ -73,6 +76,23 @@ func (c *ConfigReceiver) DeriveBIP32(i uint32) (*ConfigReceiver, error) {
 	return c.Derive(scalar, newChainKey)
 }
 
+// DerivePath adjusts the shares to represent the derived public key at a BIP32 path,
+// by applying DeriveBIP32 for each segment. See bip32.DerivePath.
+func (c *ConfigReceiver) DerivePath(path string) (*ConfigReceiver, error) {
+	return bip32.DerivePath(c, path, (*ConfigReceiver).DeriveBIP32)
+}
+
+// XPub returns the BIP32 extended public key made of Public and ChainKey, encoded as a master key.
+//
+// The "xpub" version is used if mainnet is true, otherwise "tpub".
+func (c *ConfigReceiver) XPub(mainnet bool) (string, error) {
+	publicKey, ok := c.Public.(*curve.Secp256k1Point)
+	if !ok {
+		return "", errors.New("XPub called on non secp256k1 curve")
+	}
+	return (&bip32.ExtendedPublicKey{Public: publicKey, ChainKey: c.ChainKey}).Encode(mainnet)
+}
+
 // ConfigSender holds the results of key generation for the sender.
 type ConfigSender struct {
 	// Setup is an implementation detail, needed to perform signing.
@@ -100,7 +120,7 @@ func (c *ConfigSender) Group() curve.Curve {
 // The Receiver plays the role of "Bob", and the Sender plays the role of "Alice".
 //
 // If the secret share and public point are not nil, a refresh is done instead.
-func StartKeygen(group curve.Curve, receiver bool, selfID, otherID party.ID, secretShare curve.Scalar, public curve.Point, pl *pool.Pool) protocol.StartFunc {
+func StartKeygen(group curve.Curve, receiver bool, selfID, otherID party.ID, secretShare curve.Scalar, public curve.Point, pl *pool.Pool, rand io.Reader) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		info := round.Info{
 			ProtocolID:       "doerner/keygen",
@@ -109,6 +129,7 @@ func StartKeygen(group curve.Curve, receiver bool, selfID, otherID party.ID, sec
 			PartyIDs:         party.NewIDSlice([]party.ID{selfID, otherID}),
 			Threshold:        1,
 			Group:            group,
+			Rand:             rand,
 		}
 
 		helper, err := round.NewSession(info, sessionID, nil)
@@ -116,18 +137,19 @@ func StartKeygen(group curve.Curve, receiver bool, selfID, otherID party.ID, sec
 			return nil, fmt.Errorf("keygen.StartKeygen: %w", err)
 		}
 
-		refresh := true
+		// the parameters are copied, so that the StartFunc can be called again
+		share, refresh := secretShare, true
 		if secretShare == nil && public == nil {
-			secretShare = sample.Scalar(rand.Reader, group)
+			share = sample.Scalar(helper.Rand(), group)
 			refresh = false
 		}
-		publicShare := secretShare.ActOnBase()
+		publicShare := share.ActOnBase()
 
 		if receiver {
 			return &round1R{
 				Helper:      helper,
 				refresh:     refresh,
-				secretShare: secretShare,
+				secretShare: share,
 				publicShare: publicShare,
 				public:      public,
 				receiver:    ot.NewCorreOTSetupReceiver(pl, helper.Hash(), helper.Group()),
@@ -136,7 +158,7 @@ func StartKeygen(group curve.Curve, receiver bool, selfID, otherID party.ID, sec
 		return &round1S{
 			Helper:      helper,
 			refresh:     refresh,
-			secretShare: secretShare,
+			secretShare: share,
 			publicShare: publicShare,
 			public:      public,
 			sender:      ot.NewCorreOTSetupSender(pl, helper.Hash()),
@@ -149,6 +171,9 @@ func StartKeygen(group curve.Curve, receiver bool, selfID, otherID party.ID, sec
 // This can support methods like BIP32, but is more general.
 //
 // Optionally, a new chain key can be passed as well.
+//
+// Since the shares are additive, only the Receiver adds the scalar to its share,
+// the Sender only adjusts the public key.
 func (c *ConfigSender) Derive(adjust curve.Scalar, newChainKey []byte) (*ConfigSender, error) {
 	if len(newChainKey) <= 0 {
 		newChainKey = c.ChainKey
@@ -161,14 +186,15 @@ func (c *ConfigSender) Derive(adjust curve.Scalar, newChainKey []byte) (*ConfigS
 
 	return &ConfigSender{
 		Setup:       c.Setup,
-		SecretShare: c.SecretShare.Curve().NewScalar().Set(c.SecretShare).Add(adjust),
+		SecretShare: c.SecretShare.Curve().NewScalar().Set(c.SecretShare),
 		Public:      c.Public.Add(adjustG),
+		ChainKey:    newChainKey,
 	}, nil
 }
 
 // DeriveChild adjusts the shares to represent the derived public key at a certain index.
 //
-// This will panic if the group is not curve.Secp256k1
+// This returns an error if the group is not curve.Secp256k1, or if i is a hardened index.
 //
 // This derivation works according to BIP-32, see:
 // https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki
@@ -183,3 +209,20 @@ func (c *ConfigSender) DeriveBIP32(i uint32) (*ConfigSender, error) {
 	}
 	return c.Derive(scalar, newChainKey)
 }
+
+// DerivePath adjusts the shares to represent the derived public key at a BIP32 path,
+// by applying DeriveBIP32 for each segment. See bip32.DerivePath.
+func (c *ConfigSender) DerivePath(path string) (*ConfigSender, error) {
+	return bip32.DerivePath(c, path, (*ConfigSender).DeriveBIP32)
+}
+
+// XPub returns the BIP32 extended public key made of Public and ChainKey, encoded as a master key.
+//
+// The "xpub" version is used if mainnet is true, otherwise "tpub".
+func (c *ConfigSender) XPub(mainnet bool) (string, error) {
+	publicKey, ok := c.Public.(*curve.Secp256k1Point)
+	if !ok {
+		return "", errors.New("XPub called on non secp256k1 curve")
+	}
+	return (&bip32.ExtendedPublicKey{Public: publicKey, ChainKey: c.ChainKey}).Encode(mainnet)
+}
@@ -0,0 +1,136 @@
+package keygen
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/ot"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+type configReceiverMarshal struct {
+	// Group is the name of the curve, see curve.ByName.
+	Group       string
+	Setup       *ot.CorreOTReceiveSetup
+	SecretShare curve.Scalar
+	Public      curve.Point
+	ChainKey    []byte
+}
+
+type configSenderMarshal struct {
+	// Group is the name of the curve, see curve.ByName.
+	Group       string
+	Setup       *ot.CorreOTSendSetup
+	SecretShare curve.Scalar
+	Public      curve.Point
+	ChainKey    []byte
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, including the name of the curve.
+func (c *ConfigReceiver) MarshalBinary() ([]byte, error) {
+	return cbor.Marshal(&configReceiverMarshal{
+		Group:       c.Group().Name(),
+		Setup:       c.Setup,
+		SecretShare: c.SecretShare,
+		Public:      c.Public,
+		ChainKey:    c.ChainKey,
+	})
+}
+
+// UnmarshalBinary decodes a ConfigReceiver, using the curve name embedded in the data.
+//
+// Data produced without a curve name can still be decoded, if the group was set using EmptyConfigReceiver.
+func (c *ConfigReceiver) UnmarshalBinary(data []byte) error {
+	var group curve.Curve
+	if c.Public != nil {
+		group = c.Public.Curve()
+	}
+	group, err := unmarshalGroup(data, group)
+	if err != nil {
+		return fmt.Errorf("doerner config: %w", err)
+	}
+	cm := &configReceiverMarshal{
+		SecretShare: group.NewScalar(),
+		Public:      group.NewPoint(),
+	}
+	if err := cbor.Unmarshal(data, cm); err != nil {
+		return fmt.Errorf("doerner config: %w", err)
+	}
+	if cm.Setup == nil {
+		return errors.New("doerner config: missing setup")
+	}
+	*c = ConfigReceiver{
+		Setup:       cm.Setup,
+		SecretShare: cm.SecretShare,
+		Public:      cm.Public,
+		ChainKey:    cm.ChainKey,
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, including the name of the curve.
+func (c *ConfigSender) MarshalBinary() ([]byte, error) {
+	return cbor.Marshal(&configSenderMarshal{
+		Group:       c.Group().Name(),
+		Setup:       c.Setup,
+		SecretShare: c.SecretShare,
+		Public:      c.Public,
+		ChainKey:    c.ChainKey,
+	})
+}
+
+// UnmarshalBinary decodes a ConfigSender, using the curve name embedded in the data.
+//
+// Data produced without a curve name can still be decoded, if the group was set using EmptyConfigSender.
+func (c *ConfigSender) UnmarshalBinary(data []byte) error {
+	var group curve.Curve
+	if c.Public != nil {
+		group = c.Public.Curve()
+	}
+	group, err := unmarshalGroup(data, group)
+	if err != nil {
+		return fmt.Errorf("doerner config: %w", err)
+	}
+	cm := &configSenderMarshal{
+		SecretShare: group.NewScalar(),
+		Public:      group.NewPoint(),
+	}
+	if err := cbor.Unmarshal(data, cm); err != nil {
+		return fmt.Errorf("doerner config: %w", err)
+	}
+	if cm.Setup == nil {
+		return errors.New("doerner config: missing setup")
+	}
+	*c = ConfigSender{
+		Setup:       cm.Setup,
+		SecretShare: cm.SecretShare,
+		Public:      cm.Public,
+		ChainKey:    cm.ChainKey,
+	}
+	return nil
+}
+
+// unmarshalGroup returns the group named in the encoding of a config.
+//
+// If the encoding has no curve name, group is returned instead, which must then be set.
+func unmarshalGroup(data []byte, group curve.Curve) (curve.Curve, error) {
+	var header struct{ Group string }
+	if err := cbor.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	if header.Group != "" {
+		named, err := curve.ByName(header.Group)
+		if err != nil {
+			return nil, err
+		}
+		if group != nil && group.Name() != named.Name() {
+			return nil, fmt.Errorf("expected curve %s, found %s", group.Name(), named.Name())
+		}
+		group = named
+	}
+	if group == nil {
+		return nil, errors.New("must be initialized with a group")
+	}
+	return group, nil
+}
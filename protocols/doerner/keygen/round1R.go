@@ -1,7 +1,7 @@
 package keygen
 
 import (
-	"crypto/rand"
+	"io"
 
 	"github.com/taurusgroup/multi-party-sig/internal/ot"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
@@ -50,23 +50,23 @@ func (r *round1R) VerifyMessage(round.Message) error { return nil }
 func (r *round1R) StoreMessage(round.Message) error { return nil }
 
 func (r *round1R) Finalize(out chan<- *round.Message) (round.Session, error) {
-	proof := zksch.NewProof(r.Hash(), r.publicShare, r.secretShare, nil)
-	commit, decommit, err := r.Hash().Commit(r.publicShare)
+	proof := zksch.NewProof(r.Rand(), r.Hash(), r.publicShare, r.secretShare, nil)
+	commit, decommit, err := r.Hash().Commit(r.Rand(), r.publicShare)
 	if err != nil {
 		return r, err
 	}
 	chainKey := make([]byte, params.SecBytes)
-	_, _ = rand.Read(chainKey)
-	chainKeyCommit, chainKeyDecommit, err := r.Hash().Commit(chainKey)
+	_, _ = io.ReadFull(r.Rand(), chainKey)
+	chainKeyCommit, chainKeyDecommit, err := r.Hash().Commit(r.Rand(), chainKey)
 	if err != nil {
 		return r, err
 	}
-	refreshScalar := sample.Scalar(rand.Reader, r.Group())
-	refreshCommit, refreshDecommit, err := r.Hash().Commit(refreshScalar)
+	refreshScalar := sample.Scalar(r.Rand(), r.Group())
+	refreshCommit, refreshDecommit, err := r.Hash().Commit(r.Rand(), refreshScalar)
 	if err != nil {
 		return r, err
 	}
-	otMsg := r.receiver.Round1()
+	otMsg := r.receiver.Round1(r.Rand())
 	if err := r.SendMessage(out, &message1R{commit, chainKeyCommit, refreshCommit, otMsg}, ""); err != nil {
 		return r, err
 	}
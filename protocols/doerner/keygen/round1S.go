@@ -1,7 +1,7 @@
 package keygen
 
 import (
-	"crypto/rand"
+	"io"
 
 	"github.com/taurusgroup/multi-party-sig/internal/ot"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
@@ -74,7 +74,7 @@ func (r *round1S) VerifyMessage(msg round.Message) error {
 
 func (r *round1S) StoreMessage(msg round.Message) (err error) {
 	body := msg.Content.(*message1R)
-	r.otMsg, err = r.sender.Round1(body.OtMsg)
+	r.otMsg, err = r.sender.Round1(r.Rand(), body.OtMsg)
 	if err != nil {
 		return err
 	}
@@ -85,10 +85,10 @@ func (r *round1S) StoreMessage(msg round.Message) (err error) {
 }
 
 func (r *round1S) Finalize(out chan<- *round.Message) (round.Session, error) {
-	proof := zksch.NewProof(r.Hash(), r.publicShare, r.secretShare, nil)
+	proof := zksch.NewProof(r.Rand(), r.Hash(), r.publicShare, r.secretShare, nil)
 	chainKey := make([]byte, params.SecBytes)
-	_, _ = rand.Read(chainKey)
-	refreshScalar := sample.Scalar(rand.Reader, r.Group())
+	_, _ = io.ReadFull(r.Rand(), chainKey)
+	refreshScalar := sample.Scalar(r.Rand(), r.Group())
 	if err := r.SendMessage(out, &message1S{r.publicShare, chainKey, refreshScalar, proof, r.otMsg}, ""); err != nil {
 		return r, err
 	}
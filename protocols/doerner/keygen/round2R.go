@@ -1,7 +1,7 @@
 package keygen
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/ot"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
@@ -60,10 +60,10 @@ func (r *round2R) VerifyMessage(msg round.Message) error {
 		return round.ErrNilFields
 	}
 	if !body.Proof.Verify(r.Hash(), body.PublicShare, nil) {
-		return errors.New("invalid Schnorr proof")
+		return fmt.Errorf("invalid Schnorr proof: %w", round.ErrInvalidProof)
 	}
 	if len(body.ChainKey) != params.SecBytes {
-		return errors.New("chain key too short")
+		return fmt.Errorf("chain key too short: %w", round.ErrMalformed)
 	}
 	return nil
 }
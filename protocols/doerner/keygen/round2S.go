@@ -1,7 +1,7 @@
 package keygen
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/ot"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
@@ -39,19 +39,19 @@ func (r *round2S) VerifyMessage(msg round.Message) error {
 		return err
 	}
 	if !r.Hash().Decommit(r.receiverCommit, body.Decommit, body.PublicShare) {
-		return errors.New("invalid commitment")
+		return fmt.Errorf("invalid commitment: %w", round.ErrInvalidCommitment)
 	}
 	if len(body.ChainKey) != params.SecBytes {
-		return errors.New("chain key too short")
+		return fmt.Errorf("chain key too short: %w", round.ErrMalformed)
 	}
 	if !r.Hash().Decommit(r.chainKeyCommit, body.ChainKeyDecommit, body.ChainKey) {
-		return errors.New("invalid commitment")
+		return fmt.Errorf("invalid commitment: %w", round.ErrInvalidCommitment)
 	}
 	if !r.Hash().Decommit(r.refreshCommit, body.RefreshDecommit, body.RefreshScalar) {
-		return errors.New("invalid commitment")
+		return fmt.Errorf("invalid commitment: %w", round.ErrInvalidCommitment)
 	}
 	if !body.Proof.Verify(r.Hash(), body.PublicShare, nil) {
-		return errors.New("invalid Schnorr proof")
+		return fmt.Errorf("invalid Schnorr proof: %w", round.ErrInvalidProof)
 	}
 	return nil
 }
@@ -0,0 +1,184 @@
+package keygen
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/snapshot"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+)
+
+// state is the serialized form of the rounds of this protocol, for both the Receiver and the Sender.
+//
+// The OT setup messages are only held between StoreMessage and Finalize, so they aren't included.
+type state struct {
+	Hash []hash.BytesWithDomain
+	// round1R and round1S, which are modified by later rounds
+	SecretShare []byte
+	PublicShare []byte
+	Public      []byte
+	OT          []byte
+	// round1S
+	ReceiverCommit hash.Commitment
+	ChainKeyCommit hash.Commitment
+	RefreshCommit  hash.Commitment
+	// round2R
+	Proof            []byte
+	Decommit         hash.Decommitment
+	ChainKeyDecommit hash.Decommitment
+	RefreshDecommit  hash.Decommitment
+	OurChainKey      []byte
+	// round2R and round2S
+	RefreshScalar []byte
+	ChainKey      []byte
+}
+
+// snapshotter is implemented by each round, which adds its own fields to those of the previous round.
+type snapshotter interface {
+	snapshot(s *state, e *snapshot.Encoder)
+}
+
+func (r *round1R) snapshot(s *state, e *snapshot.Encoder) {
+	s.Hash = r.HashUpdates()
+	s.SecretShare = e.Scalar(r.secretShare)
+	s.PublicShare = e.Point(r.publicShare)
+	s.Public = e.Point(r.public)
+	s.OT = e.Binary(r.receiver)
+}
+
+func (r *round2R) snapshot(s *state, e *snapshot.Encoder) {
+	r.round1R.snapshot(s, e)
+	s.Proof = e.SchnorrProof(r.proof)
+	s.Decommit = r.decommit
+	s.ChainKeyDecommit = r.chainKeyDecommit
+	s.RefreshDecommit = r.refreshDecommit
+	s.RefreshScalar = e.Scalar(r.refreshScalar)
+	s.OurChainKey = r.ourChainKey
+	s.ChainKey = r.chainKey
+}
+
+func (r *round3R) snapshot(s *state, e *snapshot.Encoder) {
+	r.round2R.snapshot(s, e)
+}
+
+func (r *round1S) snapshot(s *state, e *snapshot.Encoder) {
+	s.Hash = r.HashUpdates()
+	s.SecretShare = e.Scalar(r.secretShare)
+	s.PublicShare = e.Point(r.publicShare)
+	s.Public = e.Point(r.public)
+	s.ReceiverCommit = r.receiverCommit
+	s.ChainKeyCommit = r.chainKeyCommit
+	s.RefreshCommit = r.refreshCommit
+	s.OT = e.Binary(r.sender)
+}
+
+func (r *round2S) snapshot(s *state, e *snapshot.Encoder) {
+	r.round1S.snapshot(s, e)
+	s.RefreshScalar = e.Scalar(r.refreshScalar)
+	s.ChainKey = r.chainKey
+}
+
+func (r *round3S) snapshot(s *state, e *snapshot.Encoder) {
+	r.round2S.snapshot(s, e)
+}
+
+// Snapshot implements round.Restorer.
+func (r *round1R) Snapshot(current round.Session) ([]byte, error) {
+	return marshalState(current)
+}
+
+// Snapshot implements round.Restorer.
+func (r *round1S) Snapshot(current round.Session) ([]byte, error) {
+	return marshalState(current)
+}
+
+func marshalState(current round.Session) ([]byte, error) {
+	c, ok := current.(snapshotter)
+	if !ok {
+		return nil, round.ErrNoSnapshot
+	}
+	var (
+		s state
+		e snapshot.Encoder
+	)
+	c.snapshot(&s, &e)
+	if err := e.Err(); err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(&s)
+}
+
+// unmarshalState decodes the state, and restores the hash state of the helper.
+func unmarshalState(helper *round.Helper, data []byte) (*state, error) {
+	var s state
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if err := helper.RestoreHashUpdates(s.Hash); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Restore implements round.Restorer.
+func (r *round1R) Restore(number round.Number, data []byte) (round.Session, error) {
+	s, err := unmarshalState(r.Helper, data)
+	if err != nil {
+		return nil, err
+	}
+	d := snapshot.NewDecoder(r.Group())
+	d.Binary(s.OT, r.receiver, "ot setup")
+	r.secretShare = d.Scalar(s.SecretShare)
+	r.publicShare = d.Point(s.PublicShare)
+	r.public = d.Point(s.Public)
+	r2 := &round2R{
+		round1R:          r,
+		proof:            d.SchnorrProof(s.Proof),
+		decommit:         s.Decommit,
+		chainKeyDecommit: s.ChainKeyDecommit,
+		refreshDecommit:  s.RefreshDecommit,
+		refreshScalar:    d.Scalar(s.RefreshScalar),
+		ourChainKey:      s.OurChainKey,
+		chainKey:         s.ChainKey,
+	}
+	r3 := &round3R{round2R: r2}
+	if err = d.Err(); err != nil {
+		return nil, err
+	}
+	rounds := []round.Session{r, r2, r3}
+	if number < 1 || int(number) > len(rounds) {
+		return nil, fmt.Errorf("keygen: invalid round %d", number)
+	}
+	return rounds[number-1], nil
+}
+
+// Restore implements round.Restorer.
+func (r *round1S) Restore(number round.Number, data []byte) (round.Session, error) {
+	s, err := unmarshalState(r.Helper, data)
+	if err != nil {
+		return nil, err
+	}
+	d := snapshot.NewDecoder(r.Group())
+	d.Binary(s.OT, r.sender, "ot setup")
+	r.secretShare = d.Scalar(s.SecretShare)
+	r.publicShare = d.Point(s.PublicShare)
+	r.public = d.Point(s.Public)
+	r.receiverCommit = s.ReceiverCommit
+	r.chainKeyCommit = s.ChainKeyCommit
+	r.refreshCommit = s.RefreshCommit
+	r2 := &round2S{
+		round1S:       r,
+		chainKey:      s.ChainKey,
+		refreshScalar: d.Scalar(s.RefreshScalar),
+	}
+	r3 := &round3S{round2S: r2}
+	if err = d.Err(); err != nil {
+		return nil, err
+	}
+	rounds := []round.Session{r, r2, r3}
+	if number < 1 || int(number) > len(rounds) {
+		return nil, fmt.Errorf("keygen: invalid round %d", number)
+	}
+	return rounds[number-1], nil
+}
@@ -1,7 +1,7 @@
 package sign
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/ot"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
@@ -75,7 +75,7 @@ func (r *round2R) Finalize(out chan<- *round.Message) (round.Session, error) {
 	_ = hash.WriteAny(r.RPrime)
 	R := sample.Scalar(hash.Digest(), group).Act(r.D).Add(r.RPrime)
 	if !r.RProof.Verify(r.Hash(), R, r.D) {
-		return nil, errors.New("failed to verify schnorr proof")
+		return nil, fmt.Errorf("failed to verify schnorr proof: %w", round.ErrInvalidProof)
 	}
 
 	tB1, err := r.multiply0.Round2(r.MulMsg0)
@@ -115,7 +115,7 @@ func (r *round2R) Finalize(out chan<- *round.Message) (round.Session, error) {
 
 	sig := ecdsa.Signature{R: R, S: sigAB}
 	if !sig.Verify(r.config.Public, r.hash) {
-		return nil, errors.New("failed to verify signature")
+		return nil, fmt.Errorf("failed to verify signature: %w", round.ErrInvalidShare)
 	}
 	if err := r.SendMessage(out, &message2R{sig}, ""); err != nil {
 		return r, err
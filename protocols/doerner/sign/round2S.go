@@ -1,7 +1,7 @@
 package sign
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
@@ -19,7 +19,7 @@ func (r *round2S) VerifyMessage(msg round.Message) error {
 		return round.ErrInvalidContent
 	}
 	if !body.Sig.Verify(r.config.Public, r.hash) {
-		return errors.New("failed to verify signature")
+		return fmt.Errorf("failed to verify signature: %w", round.ErrInvalidShare)
 	}
 	return nil
 }
@@ -2,6 +2,7 @@ package sign
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
@@ -16,7 +17,7 @@ import (
 // because we use a simple additive sharing instead of a polynomial sharing.
 //
 // The Receiver plays the role of "Bob".
-func StartSignReceiver(config *keygen.ConfigReceiver, selfID, otherID party.ID, hash []byte, pl *pool.Pool) protocol.StartFunc {
+func StartSignReceiver(config *keygen.ConfigReceiver, selfID, otherID party.ID, hash []byte, pl *pool.Pool, rand io.Reader) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		info := round.Info{
 			ProtocolID:       "doerner/keygen",
@@ -25,6 +26,7 @@ func StartSignReceiver(config *keygen.ConfigReceiver, selfID, otherID party.ID,
 			PartyIDs:         party.NewIDSlice([]party.ID{selfID, otherID}),
 			Threshold:        1,
 			Group:            config.Group(),
+			Rand:             rand,
 		}
 
 		helper, err := round.NewSession(info, sessionID, nil)
@@ -42,7 +44,7 @@ func StartSignReceiver(config *keygen.ConfigReceiver, selfID, otherID party.ID,
 // because we use a simple additive sharing instead of a polynomial sharing.
 //
 // The Sender plays the role of "Alice".
-func StartSignSender(config *keygen.ConfigSender, selfID, otherID party.ID, hash []byte, pl *pool.Pool) protocol.StartFunc {
+func StartSignSender(config *keygen.ConfigSender, selfID, otherID party.ID, hash []byte, pl *pool.Pool, rand io.Reader) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		info := round.Info{
 			ProtocolID:       "doerner/keygen",
@@ -51,6 +53,7 @@ func StartSignSender(config *keygen.ConfigSender, selfID, otherID party.ID, hash
 			PartyIDs:         party.NewIDSlice([]party.ID{selfID, otherID}),
 			Threshold:        1,
 			Group:            config.Group(),
+			Rand:             rand,
 		}
 
 		helper, err := round.NewSession(info, sessionID, nil)
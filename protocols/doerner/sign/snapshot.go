@@ -0,0 +1,135 @@
+package sign
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/ot"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/snapshot"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+)
+
+// state is the serialized form of the rounds of this protocol, for both the Receiver and the Sender.
+//
+// The messages received are only held between StoreMessage and Finalize, so they aren't included,
+// which leaves nothing else for the Sender.
+type state struct {
+	Hash []hash.BytesWithDomain
+	// round2R
+	KBInv    []byte
+	D        []byte
+	Multiply [][]byte
+}
+
+// snapshotter is implemented by each round, which adds its own fields to those of the previous round.
+type snapshotter interface {
+	snapshot(s *state, e *snapshot.Encoder)
+}
+
+func (r *round1R) snapshot(s *state, _ *snapshot.Encoder) {
+	s.Hash = r.HashUpdates()
+}
+
+func (r *round2R) snapshot(s *state, e *snapshot.Encoder) {
+	r.round1R.snapshot(s, e)
+	s.KBInv = e.Scalar(r.kBInv)
+	s.D = e.Point(r.D)
+	s.Multiply = [][]byte{e.Binary(r.multiply0), e.Binary(r.multiply1), e.Binary(r.multiply2)}
+}
+
+func (r *round1S) snapshot(s *state, _ *snapshot.Encoder) {
+	s.Hash = r.HashUpdates()
+}
+
+func (r *round2S) snapshot(s *state, e *snapshot.Encoder) {
+	r.round1S.snapshot(s, e)
+}
+
+// Snapshot implements round.Restorer.
+func (r *round1R) Snapshot(current round.Session) ([]byte, error) {
+	return marshalState(current)
+}
+
+// Snapshot implements round.Restorer.
+func (r *round1S) Snapshot(current round.Session) ([]byte, error) {
+	return marshalState(current)
+}
+
+func marshalState(current round.Session) ([]byte, error) {
+	c, ok := current.(snapshotter)
+	if !ok {
+		return nil, round.ErrNoSnapshot
+	}
+	var (
+		s state
+		e snapshot.Encoder
+	)
+	c.snapshot(&s, &e)
+	if err := e.Err(); err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(&s)
+}
+
+// unmarshalState decodes the state, and restores the hash state of the helper.
+func unmarshalState(helper *round.Helper, data []byte) (*state, error) {
+	var s state
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if err := helper.RestoreHashUpdates(s.Hash); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Restore implements round.Restorer.
+func (r *round1R) Restore(number round.Number, data []byte) (round.Session, error) {
+	s, err := unmarshalState(r.Helper, data)
+	if err != nil {
+		return nil, err
+	}
+	switch number {
+	case 1:
+		return r, nil
+	case 2:
+	default:
+		return nil, fmt.Errorf("sign: invalid round %d", number)
+	}
+	if len(s.Multiply) != 3 {
+		return nil, fmt.Errorf("sign: expected 3 multiplications, got %d", len(s.Multiply))
+	}
+	// the tags are the same as in round1R.Finalize
+	tags := []string{"Multiply0", "Multiply1", "Multiply1"}
+	multiply := make([]*ot.MultiplyReceiver, len(tags))
+	d := snapshot.NewDecoder(r.Group())
+	for i, tag := range tags {
+		multiply[i] = ot.EmptyMultiplyReceiver(r.Hash().Fork(&hash.BytesWithDomain{TheDomain: tag, Bytes: nil}), r.config.Setup, r.Group())
+		d.Binary(s.Multiply[i], multiply[i], "multiply receiver")
+	}
+	r2 := &round2R{
+		round1R:   r,
+		kBInv:     d.Scalar(s.KBInv),
+		D:         d.Point(s.D),
+		multiply0: multiply[0],
+		multiply1: multiply[1],
+		multiply2: multiply[2],
+	}
+	if err = d.Err(); err != nil {
+		return nil, err
+	}
+	return r2, nil
+}
+
+// Restore implements round.Restorer.
+func (r *round1S) Restore(number round.Number, data []byte) (round.Session, error) {
+	if _, err := unmarshalState(r.Helper, data); err != nil {
+		return nil, err
+	}
+	rounds := []round.Session{r, &round2S{round1S: r}}
+	if number < 1 || int(number) > len(rounds) {
+		return nil, fmt.Errorf("sign: invalid round %d", number)
+	}
+	return rounds[number-1], nil
+}
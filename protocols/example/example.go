@@ -15,13 +15,14 @@ const (
 )
 
 // StartXOR is a function that creates the first round with all necessary information to create a protocol.Handler.
-func StartXOR(selfID party.ID, partyIDs party.IDSlice) protocol.StartFunc {
+func StartXOR(selfID party.ID, partyIDs party.IDSlice, opts ...protocol.StartOption) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		info := round.Info{
 			ProtocolID:       protocolID,
 			FinalRoundNumber: protocolRounds,
 			SelfID:           selfID,
 			PartyIDs:         partyIDs,
+			Rand:             protocol.Rand(opts...),
 		}
 		// create the helper with a description of the protocol
 		helper, err := round.NewSession(info, sessionID, nil)
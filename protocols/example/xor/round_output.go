@@ -1,7 +1,7 @@
 package xor
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
@@ -28,7 +28,7 @@ func (r *Round2) VerifyMessage(msg round.Message) error {
 	}
 
 	if len(body.XOR) != 32 {
-		return errors.New("xor should be 32 bytes long")
+		return fmt.Errorf("xor should be 32 bytes long: %w", round.ErrMalformed)
 	}
 	return nil
 }
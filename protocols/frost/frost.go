@@ -1,10 +1,16 @@
 package frost
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/recovery"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
 	"github.com/taurusgroup/multi-party-sig/protocols/frost/keygen"
 	"github.com/taurusgroup/multi-party-sig/protocols/frost/sign"
 )
@@ -27,6 +33,21 @@ func EmptyConfig(group curve.Curve) *Config {
 	}
 }
 
+// UnmarshalConfig decodes a Config produced by Config.MarshalBinary,
+// using the curve name embedded in the data to find the group.
+func UnmarshalConfig(data []byte) (*Config, error) {
+	c := new(Config)
+	if err := c.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// EmptySignature creates an empty Signature with a specific group, ready to be unmarshalled.
+func EmptySignature(group curve.Curve) Signature {
+	return sign.EmptySignature(group)
+}
+
 // Keygen initiates the Frost key generation protocol.
 //
 // This protocol establishes a new threshold signature key among a set of participants.
@@ -44,8 +65,8 @@ func EmptyConfig(group curve.Curve) *Config {
 //
 // This protocol corresponds to Figure 1 of the Frost paper:
 //   https://eprint.iacr.org/2020/852.pdf
-func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, threshold int) protocol.StartFunc {
-	return keygen.StartKeygenCommon(false, group, participants, threshold, selfID, nil, nil, nil)
+func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, opts ...protocol.StartOption) protocol.StartFunc {
+	return keygen.StartKeygenCommon(false, group, participants, threshold, selfID, nil, nil, nil, nil, protocol.Rand(opts...))
 }
 
 // KeygenTaproot is like Keygen, but will make Taproot / BIP-340 compatible keys.
@@ -53,13 +74,30 @@ func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, thresho
 // This will also return TaprootResult instead of Result, at the end of the protocol.
 //
 // See: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki#specification
-func KeygenTaproot(selfID party.ID, participants []party.ID, threshold int) protocol.StartFunc {
-	return keygen.StartKeygenCommon(true, curve.Secp256k1{}, participants, threshold, selfID, nil, nil, nil)
+func KeygenTaproot(selfID party.ID, participants []party.ID, threshold int, opts ...protocol.StartOption) protocol.StartFunc {
+	return keygen.StartKeygenCommon(true, curve.Secp256k1{}, participants, threshold, selfID, nil, nil, nil, nil, protocol.Rand(opts...))
+}
+
+// Import shares an existing private key among `participants`, instead of generating a new one.
+//
+// The `dealer` holds the full `secret` and deals it out, while the other participants pass a nil secret.
+// The dealer should discard the secret afterwards. The resulting public key is secret⋅G.
+func Import(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, dealer party.ID, secret curve.Scalar, opts ...protocol.StartOption) protocol.StartFunc {
+	return keygen.StartImportCommon(false, group, participants, threshold, selfID, dealer, secret, protocol.Rand(opts...))
+}
+
+// ImportTaproot is like Import, but will make Taproot / BIP-340 compatible keys.
+//
+// The resulting public key is the x-only encoding of secret⋅G.
+func ImportTaproot(selfID party.ID, participants []party.ID, threshold int, dealer party.ID, secret curve.Scalar, opts ...protocol.StartOption) protocol.StartFunc {
+	return keygen.StartImportCommon(true, curve.Secp256k1{}, participants, threshold, selfID, dealer, secret, protocol.Rand(opts...))
 }
 
-// Refresh
-func Refresh(config *Config, participants []party.ID) protocol.StartFunc {
-	return keygen.StartKeygenCommon(false, config.Curve(), participants, config.Threshold, config.ID, config.PrivateShare, config.PublicKey, config.VerificationShares.Points)
+// Refresh creates new shares of the key in config, for the same participants.
+//
+// The public key and chain key remain the same, so that derived keys don't change.
+func Refresh(config *Config, participants []party.ID, opts ...protocol.StartOption) protocol.StartFunc {
+	return keygen.StartKeygenCommon(false, config.Curve(), participants, config.Threshold, config.ID, config.PrivateShare, config.PublicKey, config.VerificationShares.Points, config.ChainKey, protocol.Rand(opts...))
 }
 
 // RefreshTaproot is like Refresh, but will make Taproot / BIP-340 compatible keys.
@@ -67,7 +105,7 @@ func Refresh(config *Config, participants []party.ID) protocol.StartFunc {
 // This will also return TaprootResult instead of Result, at the end of the protocol.
 //
 // See: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki#specification
-func RefreshTaproot(config *TaprootConfig, participants []party.ID) protocol.StartFunc {
+func RefreshTaproot(config *TaprootConfig, participants []party.ID, opts ...protocol.StartOption) protocol.StartFunc {
 	publicKey, err := curve.Secp256k1{}.LiftX(config.PublicKey)
 	if err != nil {
 		return func([]byte) (round.Session, error) {
@@ -78,7 +116,75 @@ func RefreshTaproot(config *TaprootConfig, participants []party.ID) protocol.Sta
 	for k, v := range config.VerificationShares {
 		verificationShares[k] = v
 	}
-	return keygen.StartKeygenCommon(true, curve.Secp256k1{}, participants, config.Threshold, config.ID, config.PrivateShare, publicKey, verificationShares)
+	return keygen.StartKeygenCommon(true, curve.Secp256k1{}, participants, config.Threshold, config.ID, config.PrivateShare, publicKey, verificationShares, config.ChainKey, protocol.Rand(opts...))
+}
+
+// Reshare transfers the key held by `oldParties` to `newParties`, with a new threshold.
+// The public key and chain key remain the same, while the shares of the previous committee are rendered useless.
+//
+// At least threshold+1 of the previous parties must take part, each providing its existing Config.
+// Parties joining without a share should use the Config created by NewcomerConfig.
+// Parties can be part of both sets.
+//
+// Returns *frost.Config if successful, which is nil for parties that are not in `newParties`.
+func Reshare(config *Config, oldParties, newParties []party.ID, newThreshold int, opts ...protocol.StartOption) protocol.StartFunc {
+	var verificationShares map[party.ID]curve.Point
+	if config.VerificationShares != nil {
+		verificationShares = config.VerificationShares.Points
+	}
+	return keygen.StartReshareCommon(false, config.Curve(), oldParties, newParties, newThreshold, config.ID, config.PrivateShare, config.PublicKey, verificationShares, config.ChainKey, protocol.Rand(opts...))
+}
+
+// ReshareTaproot is like Reshare, but for Taproot / BIP-340 compatible keys.
+//
+// Parties joining without a share should use the TaprootConfig created by NewcomerTaprootConfig.
+//
+// Returns *frost.TaprootConfig if successful, which is nil for parties that are not in `newParties`.
+func ReshareTaproot(config *TaprootConfig, oldParties, newParties []party.ID, newThreshold int, opts ...protocol.StartOption) protocol.StartFunc {
+	publicKey, err := curve.Secp256k1{}.LiftX(config.PublicKey)
+	if err != nil {
+		return func([]byte) (round.Session, error) {
+			return nil, err
+		}
+	}
+	verificationShares := make(map[party.ID]curve.Point, len(config.VerificationShares))
+	for k, v := range config.VerificationShares {
+		verificationShares[k] = v
+	}
+	if config.PrivateShare == nil {
+		return keygen.StartReshareCommon(true, curve.Secp256k1{}, oldParties, newParties, newThreshold, config.ID, nil, publicKey, verificationShares, nil, protocol.Rand(opts...))
+	}
+	return keygen.StartReshareCommon(true, curve.Secp256k1{}, oldParties, newParties, newThreshold, config.ID, config.PrivateShare, publicKey, verificationShares, config.ChainKey, protocol.Rand(opts...))
+}
+
+// NewcomerConfig creates the Config a party without a share of the key provides to Reshare,
+// in order to join the committee.
+//
+// publicKey is the key the newcomer expects to receive a share of, and verificationShares
+// the verification shares of at least the old parties, as in their configs.
+// Reshare aborts if the old parties deal a different key, and blames any old party
+// which doesn't deal its part of the key.
+func NewcomerConfig(selfID party.ID, publicKey curve.Point, verificationShares map[party.ID]curve.Point) *Config {
+	return &Config{
+		ID:                 selfID,
+		PublicKey:          publicKey,
+		VerificationShares: party.NewPointMap(verificationShares),
+	}
+}
+
+// NewcomerTaprootConfig creates the TaprootConfig a party without a share of the key provides to ReshareTaproot,
+// in order to join the committee.
+//
+// publicKey is the key the newcomer expects to receive a share of, and verificationShares
+// the verification shares of at least the old parties, as in their configs.
+// ReshareTaproot aborts if the old parties deal a different key, and blames any old party
+// which doesn't deal its part of the key.
+func NewcomerTaprootConfig(selfID party.ID, publicKey taproot.PublicKey, verificationShares map[party.ID]*curve.Secp256k1Point) *TaprootConfig {
+	return &TaprootConfig{
+		ID:                 selfID,
+		PublicKey:          publicKey,
+		VerificationShares: verificationShares,
+	}
 }
 
 // Sign initiates the protocol for producing a threshold signature, with Frost.
@@ -101,8 +207,8 @@ func RefreshTaproot(config *TaprootConfig, participants []party.ID) protocol.Sta
 // Instead, each participant independently verifies and broadcasts items as necessary.
 //
 // Differences stemming from this change are commented throughout the protocol.
-func Sign(config *Config, signers []party.ID, messageHash []byte) protocol.StartFunc {
-	return sign.StartSignCommon(false, config, signers, messageHash)
+func Sign(config *Config, signers []party.ID, messageHash []byte, opts ...protocol.StartOption) protocol.StartFunc {
+	return sign.StartSignCommon(sign.Generic, config, signers, messageHash, protocol.Rand(opts...))
 }
 
 // SignTaproot is like Sign, but will generate a Taproot / BIP-340 compatible signature.
@@ -110,7 +216,7 @@ func Sign(config *Config, signers []party.ID, messageHash []byte) protocol.Start
 // This needs to result of a Taproot compatible key generation phase, naturally.
 //
 // See: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
-func SignTaproot(config *TaprootConfig, signers []party.ID, messageHash []byte) protocol.StartFunc {
+func SignTaproot(config *TaprootConfig, signers []party.ID, messageHash []byte, opts ...protocol.StartOption) protocol.StartFunc {
 	publicKey, err := curve.Secp256k1{}.LiftX(config.PublicKey)
 	if err != nil {
 		return func([]byte) (round.Session, error) {
@@ -128,5 +234,107 @@ func SignTaproot(config *TaprootConfig, signers []party.ID, messageHash []byte)
 		PublicKey:          publicKey,
 		VerificationShares: party.NewPointMap(genericVerificationShares),
 	}
-	return sign.StartSignCommon(true, normalResult, signers, messageHash)
+	return sign.StartSignCommon(sign.Taproot, normalResult, signers, messageHash, protocol.Rand(opts...))
+}
+
+// SignEd25519 is like Sign, but will generate an Ed25519 signature, as specified in RFC 8032.
+//
+// This needs the result of a key generation phase using curve.Ed25519. The resulting
+// signature is a 64 byte slice, which can be verified with crypto/ed25519.Verify, using
+// the encoding of config.PublicKey as the public key.
+//
+// Unlike Sign and SignTaproot, message is signed directly, and should not be hashed beforehand.
+//
+// This is the same as SignRFC9591 with an Ed25519 config, since FROST(Ed25519, SHA-512) produces RFC 8032 signatures.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc8032
+func SignEd25519(config *Config, signers []party.ID, message []byte, opts ...protocol.StartOption) protocol.StartFunc {
+	return sign.StartSignCommon(sign.Ed25519SHA512, config, signers, message, protocol.Rand(opts...))
+}
+
+// SignRFC9591 is like Sign, but follows the FROST ciphersuite of RFC 9591 for the group of config,
+// so that the signature can be verified by other implementations of the standard.
+//
+// This needs the result of a key generation phase using curve.Secp256k1, for FROST(secp256k1, SHA-256),
+// or curve.Ed25519, for FROST(Ed25519, SHA-512). The resulting signature is the encoding R || z
+// of the ciphersuite, which can be verified using sign.VerifyRFC9591. The message is signed directly, instead of a hash.
+//
+// Unlike the *sign.Signature returned by Sign, whose challenge and encoding are specific to this library,
+// the result is a []byte, since the standard defines the signature by this encoding, which is what
+// other implementations expect.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc9591
+func SignRFC9591(config *Config, signers []party.ID, message []byte, opts ...protocol.StartOption) protocol.StartFunc {
+	variant := sign.Secp256k1SHA256
+	if _, ok := config.Curve().(curve.Ed25519); ok {
+		variant = sign.Ed25519SHA512
+	}
+	return sign.StartSignCommon(variant, config, signers, message, protocol.Rand(opts...))
+}
+
+// ReconstructKey recovers the full private key from the configs of at least threshold+1 parties.
+//
+// Each share is checked against the verification shares of the first config, and the result against its PublicKey.
+// If a share is inconsistent, or a config is missing some of these fields, the error is a *recovery.ShareError naming its holder.
+//
+// This should only be used offline, for disaster recovery, since the key is no longer protected by the threshold.
+func ReconstructKey(configs []*Config) (*recovery.PrivateKey, error) {
+	if len(configs) == 0 || configs[0] == nil {
+		return nil, errors.New("frost.ReconstructKey: no configs given")
+	}
+	if configs[0].PublicKey == nil {
+		return nil, &recovery.ShareError{ID: configs[0].ID, Reason: "missing public key"}
+	}
+	if configs[0].VerificationShares == nil {
+		return nil, &recovery.ShareError{ID: configs[0].ID, Reason: "missing verification shares"}
+	}
+	publicShares := configs[0].VerificationShares.Points
+	shares := make(map[party.ID]curve.Scalar, len(configs))
+	for _, c := range configs {
+		if c == nil {
+			return nil, errors.New("frost.ReconstructKey: config is nil")
+		}
+		if _, ok := shares[c.ID]; ok {
+			return nil, &recovery.ShareError{ID: c.ID, Reason: "given more than once"}
+		}
+		if c.PublicKey == nil || !c.PublicKey.Equal(configs[0].PublicKey) {
+			return nil, &recovery.ShareError{ID: c.ID, Reason: "config belongs to a different key"}
+		}
+		shares[c.ID] = c.PrivateShare
+	}
+	return recovery.Reconstruct(configs[0].Curve(), shares, publicShares, configs[0].PublicKey)
+}
+
+// ReconstructTaprootKey is like ReconstructKey, but for Taproot / BIP-340 compatible keys.
+//
+// The resulting secret x is such that x⋅G has an even y coordinate, and the x-only encoding PublicKey.
+func ReconstructTaprootKey(configs []*TaprootConfig) (*recovery.PrivateKey, error) {
+	if len(configs) == 0 || configs[0] == nil {
+		return nil, errors.New("frost.ReconstructTaprootKey: no configs given")
+	}
+	publicKey, err := curve.Secp256k1{}.LiftX(configs[0].PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("frost.ReconstructTaprootKey: %w", err)
+	}
+	publicShares := make(map[party.ID]curve.Point, len(configs[0].VerificationShares))
+	for id, share := range configs[0].VerificationShares {
+		publicShares[id] = share
+	}
+	shares := make(map[party.ID]curve.Scalar, len(configs))
+	for _, c := range configs {
+		if c == nil {
+			return nil, errors.New("frost.ReconstructTaprootKey: config is nil")
+		}
+		if _, ok := shares[c.ID]; ok {
+			return nil, &recovery.ShareError{ID: c.ID, Reason: "given more than once"}
+		}
+		if !bytes.Equal(c.PublicKey, configs[0].PublicKey) {
+			return nil, &recovery.ShareError{ID: c.ID, Reason: "config belongs to a different key"}
+		}
+		if c.PrivateShare == nil {
+			return nil, &recovery.ShareError{ID: c.ID, Reason: "missing secret share"}
+		}
+		shares[c.ID] = c.PrivateShare
+	}
+	return recovery.Reconstruct(curve.Secp256k1{}, shares, publicShares, publicKey)
 }
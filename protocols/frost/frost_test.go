@@ -2,17 +2,28 @@ package frost
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/base58"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/test"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/recovery"
 	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost/sign"
 )
 
 func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte, n *test.Network, wg *sync.WaitGroup) {
@@ -33,6 +44,12 @@ func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte
 	require.IsType(t, &Config{}, r)
 	c := r.(*Config)
 	require.True(t, c0.PublicKey.Equal(c.PublicKey))
+	require.Equal(t, c0.ChainKey, c.ChainKey, "refresh should keep the chain key")
+	derived0, err := c0.DerivePath("m/0/1")
+	require.NoError(t, err)
+	derived, err := c.DerivePath("m/0/1")
+	require.NoError(t, err)
+	require.True(t, derived0.PublicKey.Equal(derived.PublicKey), "refresh should keep the derived keys")
 
 	h, err = protocol.NewMultiHandler(KeygenTaproot(id, ids, threshold), nil)
 	require.NoError(t, err)
@@ -54,6 +71,12 @@ func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte
 
 	cTaproot := r.(*TaprootConfig)
 	require.True(t, bytes.Equal(c0Taproot.PublicKey, cTaproot.PublicKey))
+	require.Equal(t, c0Taproot.ChainKey, cTaproot.ChainKey, "refresh should keep the chain key")
+	derived0Taproot, err := c0Taproot.DerivePath("m/0/1")
+	require.NoError(t, err)
+	derivedTaproot, err := cTaproot.DerivePath("m/0/1")
+	require.NoError(t, err)
+	require.Equal(t, derived0Taproot.PublicKey, derivedTaproot.PublicKey, "refresh should keep the derived keys")
 
 	h, err = protocol.NewMultiHandler(Sign(c, ids, message), nil)
 	require.NoError(t, err)
@@ -94,3 +117,675 @@ func TestFrost(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func doTweak(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte, n *test.Network, wg *sync.WaitGroup) {
+	defer wg.Done()
+	h, err := protocol.NewMultiHandler(KeygenTaproot(id, ids, threshold), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	r, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &TaprootConfig{}, r)
+	c := r.(*TaprootConfig)
+
+	// The output keys of several scripts, to have both even and odd y coordinates.
+	merkleRoots := [][]byte{nil}
+	for i := 0; i < 4; i++ {
+		root := sha256.Sum256([]byte{byte(i)})
+		merkleRoots = append(merkleRoots, root[:])
+	}
+	for _, merkleRoot := range merkleRoots {
+		tweaked, err := c.Tweak(merkleRoot)
+		require.NoError(t, err)
+		outputKey, err := c.PublicKey.Tweak(merkleRoot)
+		require.NoError(t, err)
+		require.Equal(t, outputKey, tweaked.PublicKey)
+
+		h, err = protocol.NewMultiHandler(SignTaproot(tweaked, ids, message), nil)
+		require.NoError(t, err)
+		test.HandlerLoop(c.ID, h, n)
+
+		signResult, err := h.Result()
+		require.NoError(t, err)
+		require.IsType(t, taproot.Signature{}, signResult)
+		assert.True(t, outputKey.Verify(signResult.(taproot.Signature), message), "expected valid signature for the output key")
+	}
+
+	bip86, err := c.TweakBIP86()
+	require.NoError(t, err)
+	tweaked, err := c.Tweak(nil)
+	require.NoError(t, err)
+	assert.Equal(t, tweaked.PublicKey, bip86.PublicKey)
+	assert.True(t, tweaked.PrivateShare.Equal(bip86.PrivateShare))
+}
+
+func TestFrostTweak(t *testing.T) {
+	N := 3
+	T := N - 1
+	message := []byte("hello")
+
+	partyIDs := test.PartyIDs(N)
+
+	n := test.NewNetwork(partyIDs)
+
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for _, id := range partyIDs {
+		go doTweak(t, id, partyIDs, T, message, n, &wg)
+	}
+	wg.Wait()
+}
+
+func doEd25519(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte, n *test.Network, wg *sync.WaitGroup) {
+	defer wg.Done()
+	h, err := protocol.NewMultiHandler(Keygen(curve.Ed25519{}, id, ids, threshold), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	r, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &Config{}, r)
+	c0 := r.(*Config)
+
+	h, err = protocol.NewMultiHandler(Refresh(c0, ids), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	r, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &Config{}, r)
+	c := r.(*Config)
+	require.True(t, c0.PublicKey.Equal(c.PublicKey))
+
+	h, err = protocol.NewMultiHandler(SignEd25519(c, ids, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+
+	signResult, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, []byte{}, signResult)
+	signature := signResult.([]byte)
+	publicKey, err := c.PublicKey.MarshalBinary()
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(publicKey, message, signature))
+	assert.True(t, sign.VerifyRFC9591(sign.Ed25519SHA512, c.PublicKey, message, signature))
+
+	derived, err := c.DerivePath("m/0/1")
+	require.NoError(t, err)
+	require.False(t, derived.PublicKey.Equal(c.PublicKey))
+
+	h, err = protocol.NewMultiHandler(SignEd25519(derived, ids, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	signature = signResult.([]byte)
+	publicKey, err = derived.PublicKey.MarshalBinary()
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(publicKey, message, signature), "expected valid signature for derived key")
+}
+
+func TestFrostEd25519(t *testing.T) {
+	N := 5
+	T := N - 1
+	message := []byte("hello")
+
+	partyIDs := test.PartyIDs(N)
+
+	n := test.NewNetwork(partyIDs)
+
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for _, id := range partyIDs {
+		go doEd25519(t, id, partyIDs, T, message, n, &wg)
+	}
+	wg.Wait()
+}
+
+func doRFC9591(t *testing.T, group curve.Curve, id party.ID, ids []party.ID, threshold int, message []byte, n *test.Network, wg *sync.WaitGroup) {
+	defer wg.Done()
+	h, err := protocol.NewMultiHandler(Keygen(group, id, ids, threshold), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	r, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &Config{}, r)
+	c := r.(*Config)
+
+	h, err = protocol.NewMultiHandler(SignRFC9591(c, ids, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+
+	signResult, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, []byte{}, signResult)
+	signature := signResult.([]byte)
+	variant := sign.Secp256k1SHA256
+	if _, ok := group.(curve.Ed25519); ok {
+		variant = sign.Ed25519SHA512
+		publicKey, err := c.PublicKey.MarshalBinary()
+		require.NoError(t, err)
+		assert.True(t, ed25519.Verify(publicKey, message, signature))
+	}
+	assert.True(t, sign.VerifyRFC9591(variant, c.PublicKey, message, signature))
+}
+
+func TestFrostRFC9591(t *testing.T) {
+	N := 3
+	T := N - 1
+	message := []byte("hello")
+
+	partyIDs := test.PartyIDs(N)
+
+	for _, group := range []curve.Curve{curve.Secp256k1{}, curve.Ed25519{}} {
+		n := test.NewNetwork(partyIDs)
+
+		var wg sync.WaitGroup
+		wg.Add(N)
+		for _, id := range partyIDs {
+			go doRFC9591(t, group, id, partyIDs, T, message, n, &wg)
+		}
+		wg.Wait()
+	}
+}
+
+func doReshareKeygen(t *testing.T, id party.ID, oldIDs []party.ID, oldThreshold int, n *test.Network, configs map[party.ID]*Config, taprootConfigs map[party.ID]*TaprootConfig, mtx *sync.Mutex, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	h, err := protocol.NewMultiHandler(Keygen(curve.Secp256k1{}, id, oldIDs, oldThreshold), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	r, err := h.Result()
+	require.NoError(t, err)
+	c := r.(*Config)
+
+	h, err = protocol.NewMultiHandler(KeygenTaproot(id, oldIDs, oldThreshold), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	r, err = h.Result()
+	require.NoError(t, err)
+	cTaproot := r.(*TaprootConfig)
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	configs[id] = c
+	taprootConfigs[id] = cTaproot
+}
+
+func doReshare(t *testing.T, id party.ID, c0 *Config, c0Taproot *TaprootConfig, oldIDs, newIDs, signers []party.ID, newThreshold int, message []byte, nAll, nSign *test.Network, wg *sync.WaitGroup) {
+	defer wg.Done()
+	isNew := party.NewIDSlice(newIDs).Contains(id)
+
+	h, err := protocol.NewMultiHandler(Reshare(c0, oldIDs, newIDs, newThreshold), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, nAll)
+	r, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &Config{}, r)
+	c := r.(*Config)
+
+	h, err = protocol.NewMultiHandler(ReshareTaproot(c0Taproot, oldIDs, newIDs, newThreshold), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, nAll)
+	r, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &TaprootConfig{}, r)
+	cTaproot := r.(*TaprootConfig)
+
+	if !isNew {
+		require.Nil(t, c)
+		require.Nil(t, cTaproot)
+		return
+	}
+	require.Equal(t, newThreshold, c.Threshold)
+	require.True(t, c0.PublicKey.Equal(c.PublicKey))
+	require.Equal(t, c0Taproot.PublicKey, cTaproot.PublicKey)
+	if c0.PrivateShare != nil {
+		require.Equal(t, c0.ChainKey, c.ChainKey)
+		require.Equal(t, c0Taproot.ChainKey, cTaproot.ChainKey)
+	}
+
+	if !party.NewIDSlice(signers).Contains(id) {
+		return
+	}
+	h, err = protocol.NewMultiHandler(Sign(c, signers, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, nSign)
+	signResult, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, Signature{}, signResult)
+	assert.True(t, signResult.(Signature).Verify(c.PublicKey, message))
+
+	h, err = protocol.NewMultiHandler(SignTaproot(cTaproot, signers, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, nSign)
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, taproot.Signature{}, signResult)
+	assert.True(t, cTaproot.PublicKey.Verify(signResult.(taproot.Signature), message))
+}
+
+func TestFrostReshare(t *testing.T) {
+	message := []byte("hello")
+
+	partyIDs := test.PartyIDs(6)
+	// the first 4 parties hold a key with threshold 1,
+	// which is transferred to the last 4 parties with threshold 2.
+	oldIDs := partyIDs[:4]
+	newIDs := partyIDs[2:]
+	signers := newIDs[1:]
+
+	nOld := test.NewNetwork(oldIDs)
+	nAll := test.NewNetwork(partyIDs)
+	nSign := test.NewNetwork(signers)
+
+	configs := make(map[party.ID]*Config, len(partyIDs))
+	taprootConfigs := make(map[party.ID]*TaprootConfig, len(partyIDs))
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(len(oldIDs))
+	for _, id := range oldIDs {
+		go doReshareKeygen(t, id, oldIDs, 1, nOld, configs, taprootConfigs, &mtx, &wg)
+	}
+	wg.Wait()
+
+	// the newcomers expect the key held by the old parties
+	for _, id := range partyIDs[len(oldIDs):] {
+		configs[id] = NewcomerConfig(id, configs[oldIDs[0]].PublicKey, configs[oldIDs[0]].VerificationShares.Points)
+		taprootConfigs[id] = NewcomerTaprootConfig(id, taprootConfigs[oldIDs[0]].PublicKey, taprootConfigs[oldIDs[0]].VerificationShares)
+	}
+
+	wg.Add(len(partyIDs))
+	for _, id := range partyIDs {
+		go doReshare(t, id, configs[id], taprootConfigs[id], oldIDs, newIDs, signers, 2, message, nAll, nSign, &wg)
+	}
+	wg.Wait()
+}
+
+func TestFrostReshareWrongKey(t *testing.T) {
+	partyIDs := test.PartyIDs(3)
+	oldIDs := partyIDs[:2]
+	newcomer := partyIDs[2]
+
+	configs := make(map[party.ID]*Config, len(partyIDs))
+	otherConfigs := make(map[party.ID]*Config, len(oldIDs))
+	taprootConfigs := make(map[party.ID]*TaprootConfig, len(oldIDs))
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(2 * len(oldIDs))
+	n, nOther := test.NewNetwork(oldIDs), test.NewNetwork(oldIDs)
+	for _, id := range oldIDs {
+		go doReshareKeygen(t, id, oldIDs, 1, n, configs, taprootConfigs, &mtx, &wg)
+		go doReshareKeygen(t, id, oldIDs, 1, nOther, otherConfigs, map[party.ID]*TaprootConfig{}, &mtx, &wg)
+	}
+	wg.Wait()
+
+	// the newcomer expects another key
+	configs[newcomer] = NewcomerConfig(newcomer, otherConfigs[oldIDs[0]].PublicKey, otherConfigs[oldIDs[0]].VerificationShares.Points)
+
+	rounds := make([]round.Session, 0, len(partyIDs))
+	for _, id := range partyIDs {
+		r, err := Reshare(configs[id], oldIDs, partyIDs, 1)(nil)
+		require.NoError(t, err)
+		rounds = append(rounds, r)
+	}
+	for {
+		err, done := test.Rounds(rounds, nil)
+		if err != nil {
+			assert.True(t, errors.Is(err, round.ErrInvalidShare), "expected an invalid share, got %v", err)
+			return
+		}
+		require.False(t, done, "the newcomer should not accept a different key")
+	}
+}
+
+func TestFrostReshareCheatingDealer(t *testing.T) {
+	partyIDs := test.PartyIDs(3)
+	oldIDs := partyIDs[:2]
+	cheater, honest, newcomer := oldIDs[0], oldIDs[1], partyIDs[2]
+
+	configs := make(map[party.ID]*Config, len(partyIDs))
+	taprootConfigs := make(map[party.ID]*TaprootConfig, len(oldIDs))
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(len(oldIDs))
+	nOld := test.NewNetwork(oldIDs)
+	for _, id := range oldIDs {
+		go doReshareKeygen(t, id, oldIDs, 1, nOld, configs, taprootConfigs, &mtx, &wg)
+	}
+	wg.Wait()
+
+	// the cheater deals a share it doesn't hold, so the key would change
+	cheating := *configs[cheater]
+	cheating.PrivateShare = sample.Scalar(rand.Reader, curve.Secp256k1{})
+	configs[cheater] = &cheating
+	configs[newcomer] = NewcomerConfig(newcomer, configs[honest].PublicKey, configs[honest].VerificationShares.Points)
+
+	n := test.NewNetwork(partyIDs)
+	handlers := make(map[party.ID]*protocol.MultiHandler, len(partyIDs))
+	for _, id := range partyIDs {
+		h, err := protocol.NewMultiHandler(Reshare(configs[id], oldIDs, partyIDs, 1), nil)
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	wg.Add(len(partyIDs))
+	for id, h := range handlers {
+		go func(id party.ID, h *protocol.MultiHandler) {
+			defer wg.Done()
+			test.HandlerLoop(id, h, n)
+		}(id, h)
+	}
+	wg.Wait()
+
+	// both the remaining dealer and the newcomer blame the cheater
+	for _, id := range []party.ID{honest, newcomer} {
+		_, err := handlers[id].Result()
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, round.ErrInvalidShare), "expected an invalid share, got %v", err)
+		var protocolErr protocol.Error
+		require.True(t, errors.As(err, &protocolErr))
+		assert.Equal(t, []party.ID{cheater}, protocolErr.Culprits)
+	}
+}
+
+func doImport(t *testing.T, id, dealer party.ID, ids []party.ID, threshold int, secret curve.Scalar, message []byte, n *test.Network, wg *sync.WaitGroup) {
+	defer wg.Done()
+	var s curve.Scalar
+	if id == dealer {
+		s = secret
+	}
+
+	h, err := protocol.NewMultiHandler(Import(curve.Secp256k1{}, id, ids, threshold, dealer, s), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	r, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &Config{}, r)
+	c := r.(*Config)
+	require.True(t, secret.ActOnBase().Equal(c.PublicKey))
+
+	h, err = protocol.NewMultiHandler(ImportTaproot(id, ids, threshold, dealer, s), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	r, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &TaprootConfig{}, r)
+	cTaproot := r.(*TaprootConfig)
+	require.Equal(t, secret.ActOnBase().(*curve.Secp256k1Point).XBytes(), []byte(cTaproot.PublicKey))
+
+	h, err = protocol.NewMultiHandler(Sign(c, ids, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	signResult, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, Signature{}, signResult)
+	assert.True(t, signResult.(Signature).Verify(c.PublicKey, message))
+
+	h, err = protocol.NewMultiHandler(SignTaproot(cTaproot, ids, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, taproot.Signature{}, signResult)
+	assert.True(t, cTaproot.PublicKey.Verify(signResult.(taproot.Signature), message))
+}
+
+func TestFrostImport(t *testing.T) {
+	N := 4
+	T := N - 1
+	message := []byte("hello")
+	secret := sample.Scalar(rand.Reader, curve.Secp256k1{})
+
+	partyIDs := test.PartyIDs(N)
+
+	n := test.NewNetwork(partyIDs)
+
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for _, id := range partyIDs {
+		go doImport(t, id, partyIDs[0], partyIDs, T, secret, message, n, &wg)
+	}
+	wg.Wait()
+}
+
+// importConfigs shares secret among N parties, with both Import and ImportTaproot.
+func importConfigs(t *testing.T, partyIDs party.IDSlice, T int, secret curve.Scalar) (map[party.ID]*Config, map[party.ID]*TaprootConfig) {
+	N := len(partyIDs)
+	n := test.NewNetwork(partyIDs)
+
+	var mtx sync.Mutex
+	configs := make(map[party.ID]*Config, N)
+	taprootConfigs := make(map[party.ID]*TaprootConfig, N)
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for _, id := range partyIDs {
+		go func(id party.ID) {
+			defer wg.Done()
+			var s curve.Scalar
+			if id == partyIDs[0] {
+				s = secret
+			}
+			h, err := protocol.NewMultiHandler(Import(curve.Secp256k1{}, id, partyIDs, T, partyIDs[0], s), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(id, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+
+			h, err = protocol.NewMultiHandler(ImportTaproot(id, partyIDs, T, partyIDs[0], s), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(id, h, n)
+			rTaproot, err := h.Result()
+			require.NoError(t, err)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			configs[id] = r.(*Config)
+			taprootConfigs[id] = rTaproot.(*TaprootConfig)
+		}(id)
+	}
+	wg.Wait()
+	return configs, taprootConfigs
+}
+
+func TestReconstructKey(t *testing.T) {
+	secret := sample.Scalar(rand.Reader, curve.Secp256k1{})
+	partyIDs := test.PartyIDs(4)
+	configs, taprootConfigs := importConfigs(t, partyIDs, 2, secret)
+
+	key, err := ReconstructKey([]*Config{configs[partyIDs[3]], configs[partyIDs[1]], configs[partyIDs[2]]})
+	require.NoError(t, err)
+	assert.True(t, secret.Equal(key.Scalar))
+
+	_, err = ReconstructKey([]*Config{configs[partyIDs[3]], configs[partyIDs[1]]})
+	assert.Error(t, err)
+
+	tampered := *configs[partyIDs[1]]
+	tampered.PrivateShare = sample.Scalar(rand.Reader, curve.Secp256k1{})
+	_, err = ReconstructKey([]*Config{configs[partyIDs[0]], &tampered, configs[partyIDs[2]]})
+	var shareErr *recovery.ShareError
+	require.ErrorAs(t, err, &shareErr)
+	assert.Equal(t, partyIDs[1], shareErr.ID)
+
+	taprootKey, err := ReconstructTaprootKey([]*TaprootConfig{taprootConfigs[partyIDs[0]], taprootConfigs[partyIDs[1]], taprootConfigs[partyIDs[2]]})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(taprootConfigs[partyIDs[0]].PublicKey), taprootKey.Scalar.ActOnBase().(*curve.Secp256k1Point).XBytes())
+}
+
+func TestReconstructKeyMissingFields(t *testing.T) {
+	partyIDs := test.PartyIDs(3)
+	configs, taprootConfigs := importConfigs(t, partyIDs, 1, sample.Scalar(rand.Reader, curve.Secp256k1{}))
+
+	noVerificationShares := *configs[partyIDs[0]]
+	noVerificationShares.VerificationShares = nil
+	noPublicKey := *configs[partyIDs[1]]
+	noPublicKey.PublicKey = nil
+	noPrivateShare := *configs[partyIDs[1]]
+	noPrivateShare.PrivateShare = nil
+	for _, tc := range []struct {
+		name    string
+		configs []*Config
+		culprit party.ID
+	}{
+		{"verification shares", []*Config{&noVerificationShares, configs[partyIDs[1]]}, partyIDs[0]},
+		{"first public key", []*Config{&noPublicKey, configs[partyIDs[0]]}, partyIDs[1]},
+		{"public key", []*Config{configs[partyIDs[0]], &noPublicKey}, partyIDs[1]},
+		{"private share", []*Config{configs[partyIDs[0]], &noPrivateShare}, partyIDs[1]},
+		{"duplicate", []*Config{configs[partyIDs[0]], configs[partyIDs[0]]}, partyIDs[0]},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReconstructKey(tc.configs)
+			var shareErr *recovery.ShareError
+			require.ErrorAs(t, err, &shareErr)
+			assert.Equal(t, tc.culprit, shareErr.ID)
+		})
+	}
+
+	_, err := ReconstructKey(nil)
+	assert.Error(t, err)
+	_, err = ReconstructKey([]*Config{configs[partyIDs[0]], nil})
+	assert.Error(t, err)
+
+	noTaprootShare := *taprootConfigs[partyIDs[1]]
+	noTaprootShare.PrivateShare = nil
+	_, err = ReconstructTaprootKey([]*TaprootConfig{taprootConfigs[partyIDs[0]], &noTaprootShare})
+	var shareErr *recovery.ShareError
+	require.ErrorAs(t, err, &shareErr)
+	assert.Equal(t, partyIDs[1], shareErr.ID)
+	noTaprootKey := *taprootConfigs[partyIDs[0]]
+	noTaprootKey.PublicKey = nil
+	_, err = ReconstructTaprootKey([]*TaprootConfig{&noTaprootKey, taprootConfigs[partyIDs[1]]})
+	assert.Error(t, err)
+}
+
+func TestDerivePath(t *testing.T) {
+	partyIDs := test.PartyIDs(3)
+	configs, taprootConfigs := importConfigs(t, partyIDs, 1, sample.Scalar(rand.Reader, curve.Secp256k1{}))
+
+	derived := make([]*Config, 0, len(partyIDs))
+	derivedTaproot := make([]*TaprootConfig, 0, len(partyIDs))
+	for _, id := range partyIDs {
+		c, err := configs[id].DerivePath("m/44/60/0/0/5")
+		require.NoError(t, err)
+		expected := configs[id]
+		for _, i := range []uint32{44, 60, 0, 0, 5} {
+			expected, err = expected.DeriveChild(i)
+			require.NoError(t, err)
+		}
+		assert.True(t, expected.PublicKey.Equal(c.PublicKey))
+		derived = append(derived, c)
+
+		cTaproot, err := taprootConfigs[id].DerivePath("m/86/0/0/0/5")
+		require.NoError(t, err)
+		derivedTaproot = append(derivedTaproot, cTaproot)
+
+		_, err = configs[id].DerivePath("m/44'/60'/0'/0/5")
+		assert.Error(t, err, "hardened derivation should fail")
+		_, err = taprootConfigs[id].DerivePath("m/86h/0/0/0/5")
+		assert.Error(t, err, "hardened derivation should fail")
+	}
+	_, err := ReconstructKey(derived)
+	require.NoError(t, err, "derived shares should be consistent")
+	_, err = ReconstructTaprootKey(derivedTaproot)
+	require.NoError(t, err, "derived shares should be consistent")
+
+	xpub, err := configs[partyIDs[0]].XPub(true)
+	require.NoError(t, err)
+	assert.Equal(t, "xpub", xpub[:4])
+	xpub, err = taprootConfigs[partyIDs[0]].XPub(true)
+	require.NoError(t, err)
+	assert.Equal(t, "xpub", xpub[:4])
+}
+
+// ckdPub derives the public key at path from xpub, with the public derivation of BIP-32.
+func ckdPub(t *testing.T, xpub string, path []uint32) *curve.Secp256k1Point {
+	data, err := base58.CheckDecode(xpub)
+	require.NoError(t, err)
+	require.Len(t, data, 78)
+	chainKey := data[13:45]
+	public := new(curve.Secp256k1Point)
+	require.NoError(t, public.UnmarshalBinary(data[45:]))
+	for _, i := range path {
+		compressed, err := public.MarshalBinary()
+		require.NoError(t, err)
+		mac := hmac.New(sha512.New, chainKey)
+		_, _ = mac.Write(compressed)
+		_, _ = mac.Write([]byte{byte(i >> 24), byte(i >> 16), byte(i >> 8), byte(i)})
+		out := mac.Sum(nil)
+		tweak := new(curve.Secp256k1Scalar)
+		require.NoError(t, tweak.UnmarshalBinary(out[:32]))
+		public = public.Add(tweak.ActOnBase()).(*curve.Secp256k1Point)
+		chainKey = out[32:]
+	}
+	return public
+}
+
+func TestDerivePathXPub(t *testing.T) {
+	partyIDs := test.PartyIDs(2)
+	path := []uint32{86, 0, 0, 0, 5}
+	// the intermediate keys have an odd y coordinate for some of the keys
+	for k := 0; k < 4; k++ {
+		configs, taprootConfigs := importConfigs(t, partyIDs, 1, sample.Scalar(rand.Reader, curve.Secp256k1{}))
+		c, cTaproot := configs[partyIDs[0]], taprootConfigs[partyIDs[0]]
+
+		xpub, err := c.XPub(true)
+		require.NoError(t, err)
+		derived, err := c.DerivePath("m/86/0/0/0/5")
+		require.NoError(t, err)
+		assert.True(t, ckdPub(t, xpub, path).Equal(derived.PublicKey))
+
+		xpub, err = cTaproot.XPub(true)
+		require.NoError(t, err)
+		derivedTaproot, err := cTaproot.DerivePath("m/86/0/0/0/5")
+		require.NoError(t, err)
+		assert.Equal(t, ckdPub(t, xpub, path).XBytes(), []byte(derivedTaproot.PublicKey))
+
+		child, err := cTaproot.DeriveChild(86)
+		require.NoError(t, err)
+		assert.Equal(t, ckdPub(t, xpub, path[:1]).XBytes(), []byte(child.PublicKey))
+	}
+}
+
+// runRestoring runs a protocol, restoring the handler from a snapshot after every message.
+func runRestoring(t *testing.T, id party.ID, create protocol.StartFunc, n *test.Network) interface{} {
+	h, err := protocol.NewMultiHandler(create, nil)
+	require.NoError(t, err)
+	final, err := test.HandlerLoopRestoring(id, h, n, func(data []byte) (protocol.Handler, error) {
+		return protocol.RestoreMultiHandler(data, create)
+	})
+	require.NoError(t, err)
+	r, err := final.Result()
+	require.NoError(t, err)
+	return r
+}
+
+func TestFrostSnapshot(t *testing.T) {
+	N := 3
+	T := N - 1
+	message := []byte("hello")
+
+	partyIDs := test.PartyIDs(N)
+	n := test.NewNetwork(partyIDs)
+
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for _, id := range partyIDs {
+		go func(id party.ID) {
+			defer wg.Done()
+			r := runRestoring(t, id, Keygen(curve.Secp256k1{}, id, partyIDs, T), n)
+			require.IsType(t, &Config{}, r)
+			c := r.(*Config)
+
+			r = runRestoring(t, id, Sign(c, partyIDs, message), n)
+			require.IsType(t, Signature{}, r)
+			assert.True(t, r.(Signature).Verify(c.PublicKey, message))
+
+			r = runRestoring(t, id, KeygenTaproot(id, partyIDs, T), n)
+			require.IsType(t, &TaprootConfig{}, r)
+			cTaproot := r.(*TaprootConfig)
+
+			r = runRestoring(t, id, SignTaproot(cTaproot, partyIDs, message), n)
+			require.IsType(t, taproot.Signature{}, r)
+			assert.True(t, cTaproot.PublicKey.Verify(r.(taproot.Signature), message))
+		}(id)
+	}
+	wg.Wait()
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/taurusgroup/multi-party-sig/internal/bip32"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -14,8 +15,9 @@ import (
 // Config contains all the information produced after key generation, from the perspective
 // of a single participant.
 //
-// When unmarshalling, EmptyResult needs to be called to set the group, before
-// calling cbor.Unmarshal, or equivalent methods.
+// The encoding produced by MarshalBinary contains the name of the curve, so UnmarshalBinary
+// can be called on new(Config) directly. Older encodings without a curve name need
+// EmptyConfig to be called first, to set the group.
 type Config struct {
 	// ID is the identifier for this participant.
 	ID party.ID
@@ -49,6 +51,73 @@ func EmptyConfig(group curve.Curve) *Config {
 	}
 }
 
+type configMarshal struct {
+	// Group is the name of the curve, see curve.ByName.
+	Group              string
+	ID                 party.ID
+	Threshold          int
+	PrivateShare       curve.Scalar
+	PublicKey          curve.Point
+	ChainKey           []byte
+	VerificationShares *party.PointMap
+}
+
+func (r *Config) MarshalBinary() ([]byte, error) {
+	return cbor.Marshal(&configMarshal{
+		Group:              r.Curve().Name(),
+		ID:                 r.ID,
+		Threshold:          r.Threshold,
+		PrivateShare:       r.PrivateShare,
+		PublicKey:          r.PublicKey,
+		ChainKey:           r.ChainKey,
+		VerificationShares: r.VerificationShares,
+	})
+}
+
+func (r *Config) UnmarshalBinary(data []byte) error {
+	var header struct{ Group string }
+	if err := cbor.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("frost config: %w", err)
+	}
+	var group curve.Curve
+	if r.PublicKey != nil {
+		group = r.PublicKey.Curve()
+	}
+	if header.Group != "" {
+		named, err := curve.ByName(header.Group)
+		if err != nil {
+			return fmt.Errorf("frost config: %w", err)
+		}
+		if group != nil && group.Name() != named.Name() {
+			return fmt.Errorf("frost config: expected curve %s, found %s", group.Name(), named.Name())
+		}
+		group = named
+	}
+	if group == nil {
+		return errors.New("frost config: must be initialized using EmptyConfig")
+	}
+	cm := &configMarshal{
+		PrivateShare:       group.NewScalar(),
+		PublicKey:          group.NewPoint(),
+		VerificationShares: party.EmptyPointMap(group),
+	}
+	if err := cbor.Unmarshal(data, cm); err != nil {
+		return fmt.Errorf("frost config: %w", err)
+	}
+	if _, ok := cm.VerificationShares.Points[cm.ID]; !ok {
+		return errors.New("frost config: no verification share for this party")
+	}
+	*r = Config{
+		ID:                 cm.ID,
+		Threshold:          cm.Threshold,
+		PrivateShare:       cm.PrivateShare,
+		PublicKey:          cm.PublicKey,
+		ChainKey:           cm.ChainKey,
+		VerificationShares: cm.VerificationShares,
+	}
+	return nil
+}
+
 // Curve returns the Elliptic Curve Group associated with this result.
 func (r *Config) Curve() curve.Curve {
 	return r.PublicKey.Curve()
@@ -83,24 +152,77 @@ func (r *Config) Derive(adjust curve.Scalar, newChainKey []byte) (*Config, error
 	}, nil
 }
 
-// DeriveChild adjusts the shares to represent the derived public key at a certain index.
+// Tweak adjusts the shares to represent the output key Q = P + t⋅G of a Taproot output,
+// where P is PublicKey, used as the internal key, and t = hash_TapTweak(P || merkleRoot).
 //
-// This will panic if the group is not curve.Secp256k1
+// merkleRoot is the 32 byte root of the script tree of the output. Signatures produced
+// with the result verify against the output key, as returned by taproot.PublicKey.Tweak,
+// allowing the output to be spent with the key path.
 //
-// This derivation works according to BIP-32, see:
+// As in Derive, the shares are negated if Q has an odd y coordinate.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki#constructing-and-spending-taproot-outputs
+func (r *TaprootConfig) Tweak(merkleRoot []byte) (*TaprootConfig, error) {
+	t, err := taproot.TweakScalar(r.PublicKey, merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	return r.adjust(t, r.ChainKey)
+}
+
+// TweakBIP86 is like Tweak, for an output without a script path, as specified in BIP-86.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki
+func (r *TaprootConfig) TweakBIP86() (*TaprootConfig, error) {
+	return r.Tweak(nil)
+}
+
+// DeriveChild adjusts the shares to represent the derived public key at a certain index.
+//
+// For curve.Secp256k1, this derivation works according to BIP-32, see:
 // https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki
+//
+// For curve.P256, this uses the public derivation of SLIP-10, which is additive,
+// so that it can be computed from the shares.
+//
+// For curve.Ed25519, SLIP-10 only defines hardened derivation, so this uses a derivation
+// specific to this library. The keys it derives differ from those of other Ed25519 wallets.
+// See bip32.DeriveScalarEd25519.
+//
+// This returns an error if i is a hardened index.
 func (r *Config) DeriveChild(i uint32) (*Config, error) {
-	publicKey, ok := r.PublicKey.(*curve.Secp256k1Point)
-	if !ok {
-		return nil, errors.New("DeriveChild called on non secp256k1 curve")
+	if publicKey, ok := r.PublicKey.(*curve.Secp256k1Point); ok {
+		scalar, newChainKey, err := bip32.DeriveScalar(publicKey, r.ChainKey, i)
+		if err != nil {
+			return nil, err
+		}
+		return r.Derive(scalar, newChainKey)
 	}
-	scalar, newChainKey, err := bip32.DeriveScalar(publicKey, r.ChainKey, i)
+	scalar, newChainKey, err := bip32.DeriveScalarGeneric(r.PublicKey, r.ChainKey, i)
 	if err != nil {
 		return nil, err
 	}
 	return r.Derive(scalar, newChainKey)
 }
 
+// DerivePath adjusts the shares to represent the derived public key at a BIP32 path,
+// by applying DeriveChild for each segment. See bip32.DerivePath.
+func (r *Config) DerivePath(path string) (*Config, error) {
+	return bip32.DerivePath(r, path, (*Config).DeriveChild)
+}
+
+// XPub returns the BIP32 extended public key made of PublicKey and ChainKey, encoded as a master key.
+//
+// Watch-only wallets can use it to derive the same public keys as DerivePath.
+// The "xpub" version is used if mainnet is true, otherwise "tpub".
+func (r *Config) XPub(mainnet bool) (string, error) {
+	publicKey, ok := r.PublicKey.(*curve.Secp256k1Point)
+	if !ok {
+		return "", errors.New("XPub called on non secp256k1 curve")
+	}
+	return (&bip32.ExtendedPublicKey{Public: publicKey, ChainKey: r.ChainKey}).Encode(mainnet)
+}
+
 // TaprootConfig is like result, but for Taproot / BIP-340 keys.
 //
 // The main difference is that our public key is an actual taproot public key.
@@ -157,7 +279,11 @@ func (r *TaprootConfig) Derive(adjust *curve.Secp256k1Scalar, newChainKey []byte
 	if len(newChainKey) != params.SecBytes {
 		return nil, fmt.Errorf("expecte %d bytes for chain key, found %d", params.SecBytes, len(newChainKey))
 	}
+	return r.adjust(adjust, newChainKey)
+}
 
+// adjust returns the config for the x-only public key lift_x(PublicKey) + adjust⋅G.
+func (r *TaprootConfig) adjust(adjust *curve.Secp256k1Scalar, newChainKey []byte) (*TaprootConfig, error) {
 	adjustG := adjust.ActOnBase()
 	verificationShares := make(map[party.ID]*curve.Secp256k1Point, len(r.VerificationShares))
 	for k, v := range r.VerificationShares {
@@ -199,14 +325,82 @@ func (r *TaprootConfig) Derive(adjust *curve.Secp256k1Scalar, newChainKey []byte
 // ECDSA key, with the y coordinate byte set to 0x02. We also only look at the x
 // coordinate of the derived public key, making sure that the corresponding secret
 // key matches the version of this point with an even y coordinate.
+//
+// Since the y coordinate of the child is lost, calling DeriveChild repeatedly
+// doesn't match BIP-32 beyond the first level. Use DerivePath for longer paths.
 func (r *TaprootConfig) DeriveChild(i uint32) (*TaprootConfig, error) {
+	start, err := r.derivation()
+	if err != nil {
+		return nil, err
+	}
+	derived, err := start.deriveChild(i)
+	if err != nil {
+		return nil, err
+	}
+	return r.adjust(derived.adjust, derived.chainKey)
+}
+
+// taprootDerivation is an intermediate key of TaprootConfig.DerivePath.
+type taprootDerivation struct {
+	// publicKey is the actual point, whose y coordinate may be odd.
+	publicKey *curve.Secp256k1Point
+	chainKey  []byte
+	// adjust is the sum of the scalars added to the key so far.
+	adjust *curve.Secp256k1Scalar
+}
+
+// derivation returns the intermediate key for the Taproot key with an even y coordinate, before any derivation.
+func (r *TaprootConfig) derivation() (taprootDerivation, error) {
 	publicKey, err := curve.Secp256k1{}.LiftX(r.PublicKey)
+	if err != nil {
+		return taprootDerivation{}, err
+	}
+	return taprootDerivation{
+		publicKey: publicKey,
+		chainKey:  r.ChainKey,
+		adjust:    curve.Secp256k1{}.NewScalar().(*curve.Secp256k1Scalar),
+	}, nil
+}
+
+// deriveChild applies public BIP-32 derivation to the intermediate key d.
+func (d taprootDerivation) deriveChild(i uint32) (taprootDerivation, error) {
+	scalar, newChainKey, err := bip32.DeriveScalar(d.publicKey, d.chainKey, i)
+	if err != nil {
+		return d, err
+	}
+	return taprootDerivation{
+		publicKey: d.publicKey.Add(scalar.ActOnBase()).(*curve.Secp256k1Point),
+		chainKey:  newChainKey,
+		adjust:    curve.Secp256k1{}.NewScalar().Set(d.adjust).Add(scalar).(*curve.Secp256k1Scalar),
+	}, nil
+}
+
+// DerivePath adjusts the shares to represent the derived public key at a BIP32 path.
+// See bip32.DerivePath.
+//
+// As in DeriveChild, the Taproot key is interpreted as the point with an even y coordinate.
+// Every level is derived from the actual intermediate point, as BIP-32 does with the key returned by XPub,
+// and only the final key is made to have an even y coordinate.
+func (r *TaprootConfig) DerivePath(path string) (*TaprootConfig, error) {
+	start, err := r.derivation()
 	if err != nil {
 		return nil, err
 	}
-	scalar, newChainKey, err := bip32.DeriveScalar(publicKey, r.ChainKey, i)
+	derived, err := bip32.DerivePath(start, path, taprootDerivation.deriveChild)
 	if err != nil {
 		return nil, err
 	}
-	return r.Derive(scalar, newChainKey)
+	return r.adjust(derived.adjust, derived.chainKey)
+}
+
+// XPub returns the BIP32 extended public key made of PublicKey and ChainKey, encoded as a master key.
+//
+// As in DeriveChild, the Taproot key is interpreted as the point with an even y coordinate.
+// The "xpub" version is used if mainnet is true, otherwise "tpub".
+func (r *TaprootConfig) XPub(mainnet bool) (string, error) {
+	publicKey, err := curve.Secp256k1{}.LiftX(r.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	return (&bip32.ExtendedPublicKey{Public: publicKey, ChainKey: r.ChainKey}).Encode(mainnet)
 }
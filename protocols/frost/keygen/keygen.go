@@ -1,10 +1,15 @@
 package keygen
 
 import (
+	"errors"
 	"fmt"
+	"io"
 
+	"github.com/taurusgroup/multi-party-sig/internal/params"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
 )
@@ -13,6 +18,12 @@ const (
 	// Frost KeyGen with Threshold.
 	protocolID        = "frost/keygen-threshold"
 	protocolIDTaproot = "frost/keygen-threshold-taproot"
+	// Frost resharing to a new set of participants.
+	protocolIDReshare        = "frost/reshare-threshold"
+	protocolIDReshareTaproot = "frost/reshare-threshold-taproot"
+	// Frost sharing of an existing secret.
+	protocolIDImport        = "frost/import-threshold"
+	protocolIDImportTaproot = "frost/import-threshold-taproot"
 	// This protocol has 3 concrete rounds.
 	protocolRounds round.Number = 3
 )
@@ -24,7 +35,12 @@ var (
 	_ round.Round = (*round3)(nil)
 )
 
-func StartKeygenCommon(taproot bool, group curve.Curve, participants []party.ID, threshold int, selfID party.ID, privateShare curve.Scalar, publicKey curve.Point, verificationShares map[party.ID]curve.Point) protocol.StartFunc {
+// StartKeygenCommon creates a new key, or refreshes the shares of an existing one.
+//
+// When refreshing, privateShare, publicKey and verificationShares are those of the existing config,
+// and the existing chainKey is kept. Configs created before the chain key was set pass an empty one,
+// in which case the participants agree on a new chain key.
+func StartKeygenCommon(taproot bool, group curve.Curve, participants []party.ID, threshold int, selfID party.ID, privateShare curve.Scalar, publicKey curve.Point, verificationShares map[party.ID]curve.Point, chainKey []byte, rand io.Reader) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		info := round.Info{
 			FinalRoundNumber: protocolRounds,
@@ -32,6 +48,7 @@ func StartKeygenCommon(taproot bool, group curve.Curve, participants []party.ID,
 			PartyIDs:         participants,
 			Threshold:        threshold,
 			Group:            group,
+			Rand:             rand,
 		}
 		if taproot {
 			info.ProtocolID = protocolIDTaproot
@@ -49,14 +66,23 @@ func StartKeygenCommon(taproot bool, group curve.Curve, participants []party.ID,
 			verificationSharesCopy[k] = v
 		}
 
+		// the parameters are copied, so that the StartFunc can be called again
 		refresh := true
-		if privateShare == nil || publicKey == nil {
+		var previousChainKey []byte
+		var share curve.Scalar
+		public := publicKey
+		if privateShare != nil {
+			share = group.NewScalar().Set(privateShare)
+		}
+		if share == nil || public == nil {
 			refresh = false
-			privateShare = group.NewScalar()
-			publicKey = group.NewPoint()
+			share = group.NewScalar()
+			public = group.NewPoint()
 			for _, k := range participants {
 				verificationSharesCopy[k] = group.NewPoint()
 			}
+		} else if len(chainKey) > 0 {
+			previousChainKey = append([]byte{}, chainKey...)
 		}
 
 		return &round1{
@@ -64,9 +90,157 @@ func StartKeygenCommon(taproot bool, group curve.Curve, participants []party.ID,
 			taproot:            taproot,
 			threshold:          threshold,
 			refresh:            refresh,
-			privateShare:       privateShare,
+			privateShare:       share,
 			verificationShares: verificationSharesCopy,
-			publicKey:          publicKey,
+			publicKey:          public,
+			previousChainKey:   previousChainKey,
 		}, nil
 	}
 }
+
+// StartReshareCommon transfers an existing key from a set of dealers to a set of receivers, with a new threshold.
+//
+// Each dealer j shares λⱼ⋅sⱼ, where λⱼ is its Lagrange coefficient among the dealers, and only
+// the receivers obtain shares of the result. The public key and chain key remain the same.
+//
+// Dealers must provide their existing privateShare, publicKey, verificationShares, and chainKey.
+// Receivers without a share only provide the publicKey they expect, and the verificationShares
+// of the dealers, and pass nil for the others. Every party checks that each dealer j shares λⱼ⋅Yⱼ,
+// so that a dealer sharing something else is blamed.
+//
+// Parties which aren't receivers end up with a nil *Config, or *TaprootConfig.
+func StartReshareCommon(taproot bool, group curve.Curve, dealers, receivers []party.ID, threshold int, selfID party.ID, privateShare curve.Scalar, publicKey curve.Point, verificationShares map[party.ID]curve.Point, chainKey []byte, rand io.Reader) protocol.StartFunc {
+	protocolID := protocolIDReshare
+	if taproot {
+		protocolID = protocolIDReshareTaproot
+	}
+	if publicKey == nil || publicKey.IsIdentity() {
+		return func([]byte) (round.Session, error) {
+			return nil, errors.New("keygen.StartReshare: the expected public key must be provided")
+		}
+	}
+	if party.NewIDSlice(dealers).Contains(selfID) && len(chainKey) != params.SecBytes {
+		return func([]byte) (round.Session, error) {
+			return nil, errors.New("keygen.StartReshare: old parties must provide their existing chain key")
+		}
+	}
+	return startReshare(protocolID, taproot, group, dealers, receivers, threshold, selfID, privateShare, publicKey, verificationShares, chainKey, rand)
+}
+
+// StartImportCommon shares an existing secret held by a single dealer among all participants.
+//
+// This is a reshare where the dealer is the only previous participant. The dealer also samples a fresh chain key.
+// The dealer must provide the secret, the other participants pass nil.
+func StartImportCommon(taproot bool, group curve.Curve, participants []party.ID, threshold int, selfID, dealer party.ID, secret curve.Scalar, rand io.Reader) protocol.StartFunc {
+	protocolID := protocolIDImport
+	if taproot {
+		protocolID = protocolIDImportTaproot
+	}
+	dealers := []party.ID{dealer}
+	if selfID != dealer {
+		return startReshare(protocolID, taproot, group, dealers, participants, threshold, selfID, nil, nil, nil, nil, rand)
+	}
+	if secret == nil || secret.IsZero() {
+		return func([]byte) (round.Session, error) {
+			return nil, errors.New("keygen.StartImport: dealer must provide a non zero secret")
+		}
+	}
+	publicKey := secret.ActOnBase()
+	verificationShares := map[party.ID]curve.Point{dealer: publicKey}
+	return startReshare(protocolID, taproot, group, dealers, participants, threshold, selfID, secret, publicKey, verificationShares, nil, rand)
+}
+
+// startReshare creates the first round of a reshare.
+//
+// A dealer passing a nil chainKey samples a fresh one, as the dealer of an import does.
+func startReshare(protocolID string, taproot bool, group curve.Curve, dealers, receivers []party.ID, threshold int, selfID party.ID, privateShare curve.Scalar, publicKey curve.Point, verificationShares map[party.ID]curve.Point, chainKey []byte, rand io.Reader) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		dealerIDs := party.NewIDSlice(dealers)
+		receiverIDs := party.NewIDSlice(receivers)
+		if len(dealerIDs) == 0 || !dealerIDs.Valid() || len(receiverIDs) == 0 || !receiverIDs.Valid() {
+			return nil, errors.New("keygen.StartReshare: duplicate or empty party ids")
+		}
+		if threshold < 0 || threshold > len(receiverIDs)-1 {
+			return nil, fmt.Errorf("keygen.StartReshare: threshold %d is invalid for %d new parties", threshold, len(receiverIDs))
+		}
+
+		participants := append(party.IDSlice{}, dealerIDs...)
+		for _, id := range receiverIDs {
+			if !dealerIDs.Contains(id) {
+				participants = append(participants, id)
+			}
+		}
+		info := round.Info{
+			ProtocolID:       protocolID,
+			FinalRoundNumber: protocolRounds,
+			SelfID:           selfID,
+			PartyIDs:         participants,
+			Threshold:        threshold,
+			Group:            group,
+			Rand:             rand,
+		}
+
+		helper, err := round.NewSession(info, sessionID, nil, dealerIDs, receiverIDs)
+		if err != nil {
+			return nil, fmt.Errorf("keygen.StartReshare: %w", err)
+		}
+
+		r := &round1{
+			Helper:             helper,
+			taproot:            taproot,
+			threshold:          threshold,
+			reshare:            true,
+			dealers:            dealerIDs,
+			receivers:          receiverIDs,
+			privateShare:       group.NewScalar(),
+			verificationShares: make(map[party.ID]curve.Point, len(receiverIDs)),
+			publicKey:          group.NewPoint(),
+		}
+		for _, k := range receiverIDs {
+			r.verificationShares[k] = group.NewPoint()
+		}
+
+		isDealer := dealerIDs.Contains(selfID)
+		if isDealer && (privateShare == nil || publicKey == nil) {
+			return nil, errors.New("keygen.StartReshare: old parties must provide their existing share")
+		}
+		if publicKey == nil {
+			// the key is only known in advance when resharing, not when importing
+			return r, nil
+		}
+		previousVerificationShares := make(map[party.ID]curve.Point, len(dealerIDs))
+		for _, j := range dealerIDs {
+			share, ok := verificationShares[j]
+			if !ok || share == nil {
+				return nil, fmt.Errorf("keygen.StartReshare: missing verification share of old party %s", j)
+			}
+			previousVerificationShares[j] = share
+		}
+		// the dealers can only reconstruct the key if there's more than threshold of them
+		if !interpolate(group, dealerIDs, previousVerificationShares).Equal(publicKey) {
+			return nil, errors.New("keygen.StartReshare: not enough old parties to reconstruct the key")
+		}
+		r.previousPublicKey = publicKey
+		r.previousVerificationShares = previousVerificationShares
+		if !isDealer {
+			return r, nil
+		}
+		if chainKey == nil {
+			if chainKey, err = types.NewRID(helper.Rand()); err != nil {
+				return nil, fmt.Errorf("keygen.StartReshare: failed to sample chain key: %w", err)
+			}
+		}
+		r.previousShare = privateShare
+		r.previousChainKey = chainKey
+		return r, nil
+	}
+}
+
+// interpolate computes ∑ⱼ λⱼ⋅Yⱼ, where λⱼ are the Lagrange coefficients for the given parties.
+func interpolate(group curve.Curve, partyIDs []party.ID, shares map[party.ID]curve.Point) curve.Point {
+	result := group.NewPoint()
+	for j, lagrange := range polynomial.Lagrange(group, partyIDs) {
+		result = result.Add(lagrange.Act(shares[j]))
+	}
+	return result
+}
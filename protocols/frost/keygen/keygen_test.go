@@ -1,6 +1,7 @@
 package keygen
 
 import (
+	"crypto/rand"
 	"testing"
 
 	"github.com/fxamacker/cbor/v2"
@@ -66,6 +67,14 @@ func checkOutput(t *testing.T, rounds []round.Session, parties party.IDSlice) {
 			expected := shares[id].ActOnBase()
 			require.True(t, unmarshalledResult.VerificationShares.Points[id].Equal(expected))
 		}
+
+		marshalled, err = result.MarshalBinary()
+		require.NoError(t, err)
+		decodedResult := new(Config)
+		require.NoError(t, decodedResult.UnmarshalBinary(marshalled))
+		require.Equal(t, group.Name(), decodedResult.Curve().Name())
+		require.True(t, decodedResult.PrivateShare.Equal(result.PrivateShare))
+		require.True(t, decodedResult.PublicKey.Equal(result.PublicKey))
 	}
 }
 
@@ -76,7 +85,7 @@ func TestKeygen(t *testing.T) {
 
 	rounds := make([]round.Session, 0, N)
 	for _, partyID := range partyIDs {
-		r, err := StartKeygenCommon(false, group, partyIDs, N-1, partyID, nil, nil, nil)(nil)
+		r, err := StartKeygenCommon(false, group, partyIDs, N-1, partyID, nil, nil, nil, nil, rand.Reader)(nil)
 		require.NoError(t, err, "round creation should not result in an error")
 		rounds = append(rounds, r)
 	}
@@ -148,7 +157,7 @@ func TestKeygenTaproot(t *testing.T) {
 
 	rounds := make([]round.Session, 0, N)
 	for _, partyID := range partyIDs {
-		r, err := StartKeygenCommon(true, group, partyIDs, N-1, partyID, nil, nil, nil)(nil)
+		r, err := StartKeygenCommon(true, group, partyIDs, N-1, partyID, nil, nil, nil, nil, rand.Reader)(nil)
 		require.NoError(t, err, "round creation should not result in an error")
 		rounds = append(rounds, r)
 
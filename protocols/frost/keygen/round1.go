@@ -1,7 +1,6 @@
 package keygen
 
 import (
-	"crypto/rand"
 	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
@@ -43,6 +42,40 @@ type round1 struct {
 	verificationShares map[party.ID]curve.Point
 	// publicKey should be the previous public key when refreshing, and 0 otherwise.
 	publicKey curve.Point
+
+	// reshare indicates whether or not we're transferring an existing key to a new set of participants.
+	reshare bool
+	// dealers are the previous participants sharing their part of the existing key, when resharing.
+	dealers party.IDSlice
+	// receivers are the participants obtaining a share of the key, when resharing.
+	receivers party.IDSlice
+	// These fields are only set when resharing. previousShare is only set if we're one of the dealers,
+	// and previousChainKey is also set when refreshing. When importing, only the dealer sets them.
+
+	// previousShare is our share of the existing key.
+	previousShare curve.Scalar
+	// previousPublicKey is the existing public key, which must not change.
+	previousPublicKey curve.Point
+	// previousVerificationShares holds the existing verification shares Yⱼ of the dealers,
+	// against which we check that each dealer j shares λⱼ⋅Yⱼ.
+	previousVerificationShares map[party.ID]curve.Point
+	// previousChainKey is the existing chain key, which must not change.
+	previousChainKey []byte
+}
+
+// isDealer returns true if the polynomial of party l contributes to the secret.
+func (r *round1) isDealer(l party.ID) bool {
+	return !r.reshare || r.dealers.Contains(l)
+}
+
+// isReceiver returns true if party l obtains a share of the secret.
+func (r *round1) isReceiver(l party.ID) bool {
+	return !r.reshare || r.receivers.Contains(l)
+}
+
+// expectsProof returns true if party l needs to prove knowledge of its constant coefficient.
+func (r *round1) expectsProof(l party.ID) bool {
+	return !r.refresh && r.isDealer(l)
 }
 
 // VerifyMessage implements round.Round.
@@ -70,13 +103,24 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// that t + 1 participants are needed to create a signature.
 
 	// Refresh: Instead of creating a new secret, instead use 0, so that our result doesn't change.
+	// Reshare: Dealers use λᵢ⋅sᵢ, so that the secret doesn't change, and others use the zero polynomial
+	// of degree 0, so that they don't contribute anything.
 	a_i0 := group.NewScalar()
 	a_i0_times_G := group.NewPoint()
-	if !r.refresh {
-		a_i0 = sample.Scalar(rand.Reader, r.Group())
+	if r.reshare {
+		if r.isDealer(r.SelfID()) {
+			a_i0 = polynomial.LagrangeSingle(group, r.dealers, r.SelfID()).Mul(r.previousShare)
+			a_i0_times_G = a_i0.ActOnBase()
+		}
+	} else if !r.refresh {
+		a_i0 = sample.Scalar(r.Rand(), r.Group())
 		a_i0_times_G = a_i0.ActOnBase()
 	}
-	f_i := polynomial.NewPolynomial(r.Group(), r.threshold, a_i0)
+	degree := r.threshold
+	if !r.isDealer(r.SelfID()) {
+		degree = 0
+	}
+	f_i := polynomial.NewPolynomial(r.Rand(), r.Group(), degree, a_i0)
 
 	// 2. "Every Pᵢ computes a proof of knowledge to the corresponding secret aᵢ₀
 	// by calculating σᵢ = (Rᵢ, μᵢ), such that:
@@ -94,9 +138,10 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// add in our own ID, and then we're good to go.
 
 	// Refresh: Don't create a proof.
+	// Reshare: Only dealers have a secret to prove knowledge of.
 	var Sigma_i *zksch.Proof
-	if !r.refresh {
-		Sigma_i = zksch.NewProof(r.Helper.HashForID(r.SelfID()), a_i0_times_G, a_i0, nil)
+	if r.expectsProof(r.SelfID()) {
+		Sigma_i = zksch.NewProof(r.Rand(), r.Helper.HashForID(r.SelfID()), a_i0_times_G, a_i0, nil)
 	}
 
 	// 3. "Every participant Pᵢ computes a public comment Φᵢ = <ϕᵢ₀, ..., ϕᵢₜ>
@@ -111,11 +156,16 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	Phi_i := polynomial.NewPolynomialExponent(f_i)
 
 	// c_i is our contribution to the chaining key
-	c_i, err := types.NewRID(rand.Reader)
+	//
+	// Reshare: Dealers contribute the existing chain key, which everyone checks.
+	c_i, err := types.NewRID(r.Rand())
 	if err != nil {
 		return r, fmt.Errorf("failed to sample ChainKey")
 	}
-	commitment, decommitment, err := r.HashForID(r.SelfID()).Commit(c_i)
+	if r.reshare && r.isDealer(r.SelfID()) {
+		c_i = append(types.RID{}, r.previousChainKey...)
+	}
+	commitment, decommitment, err := r.HashForID(r.SelfID()).Commit(r.Rand(), c_i)
 	if err != nil {
 		return r, fmt.Errorf("failed to commit to chain key")
 	}
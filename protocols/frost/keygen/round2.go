@@ -53,12 +53,12 @@ func (r *round2) StoreBroadcastMessage(msg round.Message) error {
 	}
 
 	// check nil
-	if (!r.refresh && !body.Sigma_i.IsValid()) || body.Phi_i == nil {
+	if (r.expectsProof(from) && !body.Sigma_i.IsValid()) || body.Phi_i == nil {
 		return round.ErrNilFields
 	}
 
 	if err := body.Commitment.Validate(); err != nil {
-		return fmt.Errorf("commitment: %w", err)
+		return fmt.Errorf("commitment: %w: %w", round.ErrMalformed, err)
 	}
 
 	// These steps come from Figure 1, Round 1 of the Frost paper
@@ -77,13 +77,27 @@ func (r *round2) StoreBroadcastMessage(msg round.Message) error {
 	// but this time with the ID of the message sender.
 
 	// Refresh: There's no proof to verify, but instead check that the constant is identity
-	if r.refresh {
+	// Reshare: Participants which aren't dealers must send the zero polynomial.
+	if r.reshare && !r.isDealer(from) {
+		if !body.Phi_i.IsConstant || body.Phi_i.Degree() != 0 {
+			return fmt.Errorf("party %s isn't a dealer, but sent a non-zero polynomial: %w", from, round.ErrInvalidShare)
+		}
+	} else if !r.expectsProof(from) {
 		if !body.Phi_i.Constant().IsIdentity() {
-			return fmt.Errorf("party %s sent a non-zero constant while refreshing", from)
+			return fmt.Errorf("party %s sent a non-zero constant while refreshing: %w", from, round.ErrInvalidShare)
 		}
 	} else {
 		if !body.Sigma_i.Verify(r.Helper.HashForID(from), body.Phi_i.Constant(), nil) {
-			return fmt.Errorf("failed to verify Schnorr proof for party %s", from)
+			return fmt.Errorf("failed to verify Schnorr proof for party %s: %w", from, round.ErrInvalidProof)
+		}
+	}
+
+	// Reshare: Unless we're importing a key we don't know yet, check that the dealer
+	// shares λₗ⋅sₗ, by checking ϕₗ₀ = λₗ⋅Yₗ, so that a cheating dealer is blamed.
+	if r.reshare && r.isDealer(from) && r.previousVerificationShares != nil {
+		lagrange := polynomial.LagrangeSingle(r.Group(), r.dealers, from)
+		if !lagrange.Act(r.previousVerificationShares[from]).Equal(body.Phi_i.Constant()) {
+			return fmt.Errorf("party %s sent a constant not matching its verification share: %w", from, round.ErrInvalidShare)
 		}
 	}
 
@@ -112,18 +126,26 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 		return r, err
 	}
 
+	// Reshare: Only receivers get a share, which is 0 if we aren't a dealer.
 	for _, l := range r.OtherPartyIDs() {
+		var F_li curve.Scalar
+		if r.isReceiver(l) {
+			F_li = r.f_i.Evaluate(l.Scalar(r.Group()))
+		}
 		if err := r.SendMessage(out, &message3{
-			F_li: r.f_i.Evaluate(l.Scalar(r.Group())),
+			F_li: F_li,
 		}, l); err != nil {
 			return r, err
 		}
 	}
 
-	selfShare := r.f_i.Evaluate(r.SelfID().Scalar(r.Group()))
+	shareFrom := make(map[party.ID]curve.Scalar)
+	if r.isReceiver(r.SelfID()) {
+		shareFrom[r.SelfID()] = r.f_i.Evaluate(r.SelfID().Scalar(r.Group()))
+	}
 	return &round3{
 		round2:    r,
-		shareFrom: map[party.ID]curve.Scalar{r.SelfID(): selfShare},
+		shareFrom: shareFrom,
 	}, nil
 }
 
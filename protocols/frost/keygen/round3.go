@@ -1,6 +1,7 @@
 package keygen
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
@@ -50,7 +51,7 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 	// Verify that the commitment to the chain key contribution matches, and then xor
 	// it into the accumulated chain key so far.
 	if !r.HashForID(from).Decommit(r.ChainKeyCommitments[from], body.Decommitment, body.C_l) {
-		return fmt.Errorf("failed to verify chain key commitment")
+		return fmt.Errorf("failed to verify chain key commitment: %w", round.ErrInvalidCommitment)
 	}
 	r.ChainKeys[from] = body.C_l
 	return nil
@@ -64,6 +65,12 @@ func (r *round3) VerifyMessage(msg round.Message) error {
 	}
 
 	// check nil
+	if !r.isReceiver(msg.To) {
+		if body.F_li != nil {
+			return fmt.Errorf("unexpected share: %w", round.ErrMalformed)
+		}
+		return nil
+	}
 	if body.F_li == nil {
 		return round.ErrNilFields
 	}
@@ -76,6 +83,9 @@ func (r *round3) VerifyMessage(msg round.Message) error {
 // Verify the VSS condition here since we will not be sending this message to other parties for verification.
 func (r *round3) StoreMessage(msg round.Message) error {
 	from, body := msg.From, msg.Content.(*message3)
+	if !r.isReceiver(r.SelfID()) {
+		return nil
+	}
 
 	// These steps come from Figure 1, Round 2 of the Frost paper
 
@@ -87,7 +97,7 @@ func (r *round3) StoreMessage(msg round.Message) error {
 	expected := body.F_li.ActOnBase()
 	actual := r.Phi[from].Evaluate(r.SelfID().Scalar(r.Group()))
 	if !expected.Equal(actual) {
-		return fmt.Errorf("VSS failed to validate")
+		return fmt.Errorf("VSS failed to validate: %w", round.ErrInvalidShare)
 	}
 
 	r.shareFrom[from] = body.F_li
@@ -98,8 +108,21 @@ func (r *round3) StoreMessage(msg round.Message) error {
 // Finalize implements round.Round.
 func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
 	ChainKey := types.EmptyRID()
-	for _, j := range r.PartyIDs() {
-		ChainKey.XOR(r.ChainKeys[j])
+	if r.reshare {
+		// Reshare: All dealers must have sent the existing chain key.
+		ChainKey = r.ChainKeys[r.dealers[0]]
+		for _, j := range r.dealers {
+			if !bytes.Equal(ChainKey, r.ChainKeys[j]) {
+				return r.AbortRound(fmt.Errorf("party %s sent a different chain key: %w", j, round.ErrInconsistent), j), nil
+			}
+		}
+	} else if r.refresh && r.previousChainKey != nil {
+		// Refresh: Keep the existing chain key, so that derived keys don't change.
+		ChainKey = append(types.RID{}, r.previousChainKey...)
+	} else {
+		for _, j := range r.PartyIDs() {
+			ChainKey.XOR(r.ChainKeys[j])
+		}
 	}
 
 	// These steps come from Figure 1, Round 2 of the Frost paper
@@ -125,9 +148,13 @@ func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
 
 	// This accomplishes the same sum as in the paper, by first summing
 	// together the exponent coefficients, and then evaluating.
+	//
+	// Reshare: Only the dealers' polynomials are summed, the others are zero.
 	exponents := make([]*polynomial.Exponent, 0, r.PartyIDs().Len())
-	for _, phi_j := range r.Phi {
-		exponents = append(exponents, phi_j)
+	for j, phi_j := range r.Phi {
+		if r.isDealer(j) {
+			exponents = append(exponents, phi_j)
+		}
 	}
 	verificationExponent, err := polynomial.Sum(exponents)
 	if err != nil {
@@ -137,6 +164,17 @@ func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
 		r.verificationShares[k] = v.Add(verificationExponent.Evaluate(k.Scalar(r.Group())))
 	}
 
+	if r.previousPublicKey != nil && !r.previousPublicKey.Equal(r.publicKey) {
+		return r.AbortRound(fmt.Errorf("reshared public key is different: %w", round.ErrInconsistent)), nil
+	}
+
+	if !r.isReceiver(r.SelfID()) {
+		if r.taproot {
+			return r.ResultRound((*TaprootConfig)(nil)), nil
+		}
+		return r.ResultRound((*Config)(nil)), nil
+	}
+
 	if r.taproot {
 		// BIP-340 adjustment: If our public key is odd, then the underlying secret
 		// needs to be negated. Since this secret is ∑ᵢ aᵢ₀, we can negated each
@@ -162,6 +200,7 @@ func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
 			Threshold:          r.threshold,
 			PrivateShare:       r.privateShare.(*curve.Secp256k1Scalar),
 			PublicKey:          YSecp.XBytes()[:],
+			ChainKey:           ChainKey,
 			VerificationShares: secpVerificationShares,
 		}), nil
 	}
@@ -171,6 +210,7 @@ func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
 		Threshold:          r.threshold,
 		PrivateShare:       r.privateShare,
 		PublicKey:          r.publicKey,
+		ChainKey:           ChainKey,
 		VerificationShares: party.NewPointMap(r.verificationShares),
 	}), nil
 }
@@ -180,6 +220,10 @@ func (message3) RoundNumber() round.Number { return 3 }
 
 // MessageContent implements round.Round.
 func (r *round3) MessageContent() round.Content {
+	// Reshare: parties which don't obtain a share receive empty messages.
+	if !r.isReceiver(r.SelfID()) {
+		return &message3{}
+	}
 	return &message3{
 		F_li: r.Group().NewScalar(),
 	}
@@ -0,0 +1,97 @@
+package keygen
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/snapshot"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// state is the serialized form of the rounds of this protocol.
+//
+// The fields of round1 are set by the StartFunc, so only the values computed afterwards are included.
+type state struct {
+	Hash                 []hash.BytesWithDomain
+	F_i                  []byte
+	Phi                  map[party.ID][]byte
+	ChainKeyDecommitment hash.Decommitment
+	ChainKeys            map[party.ID]types.RID
+	ChainKeyCommitments  map[party.ID]hash.Commitment
+	ShareFrom            map[party.ID][]byte
+}
+
+// snapshotter is implemented by each round, which adds its own fields to those of the previous round.
+type snapshotter interface {
+	snapshot(s *state, e *snapshot.Encoder)
+}
+
+func (r *round1) snapshot(s *state, _ *snapshot.Encoder) {
+	s.Hash = r.HashUpdates()
+}
+
+func (r *round2) snapshot(s *state, e *snapshot.Encoder) {
+	r.round1.snapshot(s, e)
+	s.F_i = e.Polynomial(r.f_i)
+	s.Phi = e.Exponents(r.Phi)
+	s.ChainKeyDecommitment = r.ChainKeyDecommitment
+	s.ChainKeys = r.ChainKeys
+	s.ChainKeyCommitments = r.ChainKeyCommitments
+}
+
+func (r *round3) snapshot(s *state, e *snapshot.Encoder) {
+	r.round2.snapshot(s, e)
+	s.ShareFrom = e.Scalars(r.shareFrom)
+}
+
+// Snapshot implements round.Restorer.
+func (r *round1) Snapshot(current round.Session) ([]byte, error) {
+	c, ok := current.(snapshotter)
+	if !ok {
+		return nil, round.ErrNoSnapshot
+	}
+	var (
+		s state
+		e snapshot.Encoder
+	)
+	c.snapshot(&s, &e)
+	if err := e.Err(); err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(&s)
+}
+
+// Restore implements round.Restorer.
+func (r *round1) Restore(number round.Number, data []byte) (round.Session, error) {
+	var s state
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if err := r.RestoreHashUpdates(s.Hash); err != nil {
+		return nil, err
+	}
+	d := snapshot.NewDecoder(r.Group())
+	r2 := &round2{
+		round1:               r,
+		f_i:                  d.Polynomial(s.F_i),
+		Phi:                  d.Exponents(s.Phi),
+		ChainKeyDecommitment: s.ChainKeyDecommitment,
+		ChainKeys:            s.ChainKeys,
+		ChainKeyCommitments:  s.ChainKeyCommitments,
+	}
+	r3 := &round3{
+		round2:    r2,
+		shareFrom: d.Scalars(s.ShareFrom),
+	}
+	if err := d.Err(); err != nil {
+		return nil, err
+	}
+	rounds := []round.Session{r, r2, r3}
+	if number < 1 || int(number) > len(rounds) {
+		return nil, fmt.Errorf("keygen: invalid round %d", number)
+	}
+	return rounds[number-1], nil
+}
@@ -0,0 +1,318 @@
+package sign
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"hash"
+	"io"
+	"sort"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// ciphersuite contains the hash functions and encodings of a FROST ciphersuite, as specified in RFC 9591.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc9591#section-6
+type ciphersuite interface {
+	// group is the prime order group of this ciphersuite.
+	group() curve.Curve
+	// h1 computes binding factors.
+	h1(m []byte) curve.Scalar
+	// h2 computes the challenge.
+	h2(m []byte) curve.Scalar
+	// h3 computes nonces.
+	h3(m []byte) curve.Scalar
+	// h4 hashes the message.
+	h4(m []byte) []byte
+	// h5 hashes the list of commitments.
+	h5(m []byte) []byte
+	// serializeScalar returns the canonical encoding of a scalar.
+	serializeScalar(s curve.Scalar) []byte
+	// serializeElement returns the canonical encoding of a point, which may not be the identity.
+	serializeElement(p curve.Point) ([]byte, error)
+}
+
+// ciphersuite returns the RFC 9591 ciphersuite of this variant, or nil if it doesn't follow one.
+func (v Variant) ciphersuite() ciphersuite {
+	switch v {
+	case Secp256k1SHA256:
+		return secp256k1SHA256{}
+	case Ed25519SHA512:
+		return ed25519SHA512{}
+	default:
+		return nil
+	}
+}
+
+// nonceGenerate returns H3(random_bytes(32) || SerializeScalar(secret)).
+//
+// See: https://datatracker.ietf.org/doc/html/rfc9591#section-4.1
+func nonceGenerate(cs ciphersuite, secret curve.Scalar, rand io.Reader) (curve.Scalar, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := io.ReadFull(rand, randomBytes); err != nil {
+		return nil, err
+	}
+	return cs.h3(append(randomBytes, cs.serializeScalar(secret)...)), nil
+}
+
+// sortedIdentifiers returns the parties sorted by the value of their identifier.
+func sortedIdentifiers(group curve.Curve, ids []party.ID) []party.ID {
+	type identifier struct {
+		id    party.ID
+		value []byte
+	}
+	identifiers := make([]identifier, 0, len(ids))
+	for _, id := range ids {
+		// MarshalBinary is big endian for all our groups.
+		value, _ := id.Scalar(group).MarshalBinary()
+		identifiers = append(identifiers, identifier{id, value})
+	}
+	sort.Slice(identifiers, func(i, j int) bool {
+		return bytes.Compare(identifiers[i].value, identifiers[j].value) < 0
+	})
+	sorted := make([]party.ID, 0, len(ids))
+	for _, i := range identifiers {
+		sorted = append(sorted, i.id)
+	}
+	return sorted
+}
+
+// bindingFactors returns the binding factor ρₗ of each party l, for the commitments (Dₗ, Eₗ).
+//
+// See: https://datatracker.ietf.org/doc/html/rfc9591#section-4.4
+func bindingFactors(cs ciphersuite, Y curve.Point, ids []party.ID, D, E map[party.ID]curve.Point, m []byte) (map[party.ID]curve.Scalar, error) {
+	group := cs.group()
+	ids = sortedIdentifiers(group, ids)
+
+	// encode_group_commitment_list
+	var commitments []byte
+	for _, l := range ids {
+		DBytes, err := cs.serializeElement(D[l])
+		if err != nil {
+			return nil, err
+		}
+		EBytes, err := cs.serializeElement(E[l])
+		if err != nil {
+			return nil, err
+		}
+		commitments = append(commitments, cs.serializeScalar(l.Scalar(group))...)
+		commitments = append(commitments, DBytes...)
+		commitments = append(commitments, EBytes...)
+	}
+
+	YBytes, err := cs.serializeElement(Y)
+	if err != nil {
+		return nil, err
+	}
+	prefix := append(YBytes, cs.h4(m)...)
+	prefix = append(prefix, cs.h5(commitments)...)
+
+	rho := make(map[party.ID]curve.Scalar, len(ids))
+	for _, l := range ids {
+		input := append(append([]byte{}, prefix...), cs.serializeScalar(l.Scalar(group))...)
+		rho[l] = cs.h1(input)
+	}
+	return rho, nil
+}
+
+// challenge returns H2(SerializeElement(R) || SerializeElement(Y) || m).
+//
+// See: https://datatracker.ietf.org/doc/html/rfc9591#section-4.6
+func challenge(cs ciphersuite, R, Y curve.Point, m []byte) (curve.Scalar, error) {
+	RBytes, err := cs.serializeElement(R)
+	if err != nil {
+		return nil, err
+	}
+	YBytes, err := cs.serializeElement(Y)
+	if err != nil {
+		return nil, err
+	}
+	input := append(RBytes, YBytes...)
+	return cs.h2(append(input, m...)), nil
+}
+
+// encodeSignature returns SerializeElement(R) || SerializeScalar(z).
+func encodeSignature(cs ciphersuite, R curve.Point, z curve.Scalar) ([]byte, error) {
+	RBytes, err := cs.serializeElement(R)
+	if err != nil {
+		return nil, err
+	}
+	return append(RBytes, cs.serializeScalar(z)...), nil
+}
+
+// VerifyRFC9591 checks a signature produced by one of the RFC 9591 variants,
+// encoded as SerializeElement(R) || SerializeScalar(z), for the public key Y and the message m.
+//
+// Ed25519SHA512 signatures can also be verified using crypto/ed25519.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc9591#section-6
+func VerifyRFC9591(variant Variant, Y curve.Point, m, sig []byte) bool {
+	cs := variant.ciphersuite()
+	if cs == nil || Y.Curve().Name() != cs.group().Name() || Y.IsIdentity() {
+		return false
+	}
+	group := cs.group()
+	R := group.NewPoint()
+	z := group.NewScalar()
+	RLen := len(sig) - 32
+	if RLen <= 0 {
+		return false
+	}
+	if err := R.UnmarshalBinary(sig[:RLen]); err != nil || R.IsIdentity() {
+		return false
+	}
+	zBytes := sig[RLen:]
+	if _, ok := cs.(ed25519SHA512); ok {
+		zBytes = reverse(zBytes)
+	}
+	if err := z.UnmarshalBinary(zBytes); err != nil {
+		return false
+	}
+	c, err := challenge(cs, R, Y, m)
+	if err != nil {
+		return false
+	}
+	return z.ActOnBase().Equal(R.Add(c.Act(Y)))
+}
+
+// secp256k1SHA256 is the FROST(secp256k1, SHA-256) ciphersuite.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc9591#section-6.5
+type secp256k1SHA256 struct{}
+
+const secp256k1SHA256Context = "FROST-secp256k1-SHA256-v1"
+
+func (secp256k1SHA256) group() curve.Curve { return curve.Secp256k1{} }
+
+func (cs secp256k1SHA256) h1(m []byte) curve.Scalar { return cs.hashToScalar(m, "rho") }
+
+func (cs secp256k1SHA256) h2(m []byte) curve.Scalar { return cs.hashToScalar(m, "chal") }
+
+func (cs secp256k1SHA256) h3(m []byte) curve.Scalar { return cs.hashToScalar(m, "nonce") }
+
+func (secp256k1SHA256) h4(m []byte) []byte {
+	return hashWithPrefix(sha256.New(), secp256k1SHA256Context+"msg", m)
+}
+
+func (secp256k1SHA256) h5(m []byte) []byte {
+	return hashWithPrefix(sha256.New(), secp256k1SHA256Context+"com", m)
+}
+
+// hashToScalar implements hash_to_field from RFC 9380, with expand_message_xmd using SHA-256, and L = 48.
+func (cs secp256k1SHA256) hashToScalar(m []byte, tag string) curve.Scalar {
+	uniform := expandMessageXMD(m, []byte(secp256k1SHA256Context+tag), 48)
+	return cs.group().NewScalar().SetNat(new(saferith.Nat).SetBytes(uniform))
+}
+
+func (secp256k1SHA256) serializeScalar(s curve.Scalar) []byte {
+	data, _ := s.MarshalBinary()
+	return data
+}
+
+func (secp256k1SHA256) serializeElement(p curve.Point) ([]byte, error) {
+	if p.IsIdentity() {
+		return nil, errors.New("cannot serialize the identity")
+	}
+	return p.MarshalBinary()
+}
+
+// expandMessageXMD implements expand_message_xmd from RFC 9380 using SHA-256.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc9380#section-5.3.1
+func expandMessageXMD(m, dst []byte, length int) []byte {
+	const bInBytes, sInBytes = sha256.Size, sha256.BlockSize
+	ell := (length + bInBytes - 1) / bInBytes
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h := sha256.New()
+	_, _ = h.Write(make([]byte, sInBytes))
+	_, _ = h.Write(m)
+	_, _ = h.Write([]byte{byte(length >> 8), byte(length), 0})
+	_, _ = h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	out := make([]byte, 0, ell*bInBytes)
+	b := make([]byte, bInBytes)
+	for i := 1; i <= ell; i++ {
+		for j := range b {
+			b[j] ^= b0[j]
+		}
+		h.Reset()
+		_, _ = h.Write(b)
+		_, _ = h.Write([]byte{byte(i)})
+		_, _ = h.Write(dstPrime)
+		b = h.Sum(nil)
+		out = append(out, b...)
+	}
+	return out[:length]
+}
+
+// ed25519SHA512 is the FROST(Ed25519, SHA-512) ciphersuite, whose signatures are valid RFC 8032 signatures.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc9591#section-6.1
+type ed25519SHA512 struct{}
+
+const ed25519SHA512Context = "FROST-ED25519-SHA512-v1"
+
+func (ed25519SHA512) group() curve.Curve { return curve.Ed25519{} }
+
+func (cs ed25519SHA512) h1(m []byte) curve.Scalar {
+	return cs.hashToScalar(hashWithPrefix(sha512.New(), ed25519SHA512Context+"rho", m))
+}
+
+// h2 has no prefix, so that the challenge is the same as in RFC 8032.
+func (cs ed25519SHA512) h2(m []byte) curve.Scalar {
+	return cs.hashToScalar(hashWithPrefix(sha512.New(), "", m))
+}
+
+func (cs ed25519SHA512) h3(m []byte) curve.Scalar {
+	return cs.hashToScalar(hashWithPrefix(sha512.New(), ed25519SHA512Context+"nonce", m))
+}
+
+func (ed25519SHA512) h4(m []byte) []byte {
+	return hashWithPrefix(sha512.New(), ed25519SHA512Context+"msg", m)
+}
+
+func (ed25519SHA512) h5(m []byte) []byte {
+	return hashWithPrefix(sha512.New(), ed25519SHA512Context+"com", m)
+}
+
+// hashToScalar interprets a 64 byte digest as a little endian integer modulo the group order.
+func (ed25519SHA512) hashToScalar(digest []byte) curve.Scalar {
+	s, err := new(curve.Ed25519Scalar).SetBytesWide(digest)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (ed25519SHA512) serializeScalar(s curve.Scalar) []byte {
+	return s.(*curve.Ed25519Scalar).BytesLE()
+}
+
+func (ed25519SHA512) serializeElement(p curve.Point) ([]byte, error) {
+	if p.IsIdentity() {
+		return nil, errors.New("cannot serialize the identity")
+	}
+	return p.MarshalBinary()
+}
+
+// hashWithPrefix returns H(prefix || m).
+func hashWithPrefix(h hash.Hash, prefix string, m []byte) []byte {
+	_, _ = h.Write([]byte(prefix))
+	_, _ = h.Write(m)
+	return h.Sum(nil)
+}
+
+// reverse returns a reversed copy of data, converting between Big and Little Endian.
+func reverse(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[len(data)-1-i] = b
+	}
+	return out
+}
@@ -0,0 +1,274 @@
+package sign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost/keygen"
+)
+
+// rfc9591Vector is a test vector from Appendix E of RFC 9591, where participants 1 and 3 sign.
+type rfc9591Vector struct {
+	variant Variant
+	// groupSecretKey and the coefficient define the polynomial of the shares.
+	groupSecretKey, coefficient string
+	groupPublicKey              string
+	message                     string
+	// hidingRandomness and bindingRandomness are indexed by participant.
+	hidingRandomness, bindingRandomness map[party.ID]string
+	hidingNonce, bindingNonce           map[party.ID]string
+	bindingFactor                       map[party.ID]string
+	sigShare                            map[party.ID]string
+	sig                                 string
+}
+
+var rfc9591Vectors = []rfc9591Vector{
+	// https://datatracker.ietf.org/doc/html/rfc9591#appendix-E.1
+	{
+		variant:        Ed25519SHA512,
+		groupSecretKey: "7b1c33d3f5291d85de664833beb1ad469f7fb6025a0ec78b3a790c6e13a98304",
+		coefficient:    "178199860edd8c62f5212ee91eff1295d0d670ab4ed4506866bae57e7030b204",
+		groupPublicKey: "15d21ccd7ee42959562fc8aa63224c8851fb3ec85a3faf66040d380fb9738673",
+		message:        "74657374",
+		hidingRandomness: map[party.ID]string{
+			"\x01": "0fd2e39e111cdc266f6c0f4d0fd45c947761f1f5d3cb583dfcb9bbaf8d4c9fec",
+			"\x03": "86d64a260059e495d0fb4fcc17ea3da7452391baa494d4b00321098ed2a0062f",
+		},
+		bindingRandomness: map[party.ID]string{
+			"\x01": "69cd85f631d5f7f2721ed5e40519b1366f340a87c2f6856363dbdcda348a7501",
+			"\x03": "13e6b25afb2eba51716a9a7d44130c0dbae0004a9ef8d7b5550c8a0e07c61775",
+		},
+		hidingNonce: map[party.ID]string{
+			"\x01": "812d6104142944d5a55924de6d49940956206909f2acaeedecda2b726e630407",
+			"\x03": "c256de65476204095ebdc01bd11dc10e57b36bc96284595b8215222374f99c0e",
+		},
+		bindingNonce: map[party.ID]string{
+			"\x01": "b1110165fc2334149750b28dd813a39244f315cff14d4e89e6142f262ed83301",
+			"\x03": "243d71944d929063bc51205714ae3c2218bd3451d0214dfb5aeec2a90c35180d",
+		},
+		bindingFactor: map[party.ID]string{
+			"\x01": "f2cb9d7dd9beff688da6fcc83fa89046b3479417f47f55600b106760eb3b5603",
+			"\x03": "b087686bf35a13f3dc78e780a34b0fe8a77fef1b9938c563f5573d71d8d7890f",
+		},
+		sigShare: map[party.ID]string{
+			"\x01": "001719ab5a53ee1a12095cd088fd149702c0720ce5fd2f29dbecf24b7281b603",
+			"\x03": "bd86125de990acc5e1f13781d8e32c03a9bbd4c53539bbc106058bfd14326007",
+		},
+		sig: "36282629c383bb820a88b71cae937d41f2f2adfcc3d02e55507e2fb9e2dd3cbe" +
+			"bd9d2b0844e49ae0f3fa935161e1419aab7b47d21a37ebeae1f17d4987b3160b",
+	},
+	// https://datatracker.ietf.org/doc/html/rfc9591#appendix-E.5
+	{
+		variant:        Secp256k1SHA256,
+		groupSecretKey: "0d004150d27c3bf2a42f312683d35fac7394b1e9e318249c1bfe7f0795a83114",
+		coefficient:    "fbf85eadae3058ea14f19148bb72b45e4399c0b16028acaf0395c9b03c823579",
+		groupPublicKey: "02f37c34b66ced1fb51c34a90bdae006901f10625cc06c4f64663b0eae87d87b4f",
+		message:        "74657374",
+		hidingRandomness: map[party.ID]string{
+			"\x01": "7ea5ed09af19f6ff21040c07ec2d2adbd35b759da5a401d4c99dd26b82391cb2",
+			"\x03": "e6cc56ccbd0502b3f6f831d91e2ebd01c4de0479e0191b66895a4ffd9b68d544",
+		},
+		bindingRandomness: map[party.ID]string{
+			"\x01": "47acab018f116020c10cb9b9abdc7ac10aae1b48ca6e36dc15acb6ec9be5cdc5",
+			"\x03": "7203d55eb82a5ca0d7d83674541ab55f6e76f1b85391d2c13706a89a064fd5b9",
+		},
+		hidingNonce: map[party.ID]string{
+			"\x01": "841d3a6450d7580b4da83c8e618414d0f024391f2aeb511d7579224420aa81f0",
+			"\x03": "2b19b13f193f4ce83a399362a90cdc1e0ddcd83e57089a7af0bdca71d47869b2",
+		},
+		bindingNonce: map[party.ID]string{
+			"\x01": "8d2624f532af631377f33cf44b5ac5f849067cae2eacb88680a31e77c79b5a80",
+			"\x03": "7a443bde83dc63ef52dda354005225ba0e553243402a4705ce28ffaafe0f5b98",
+		},
+		bindingFactor: map[party.ID]string{
+			"\x01": "3e08fe561e075c653cbfd46908a10e7637c70c74f0a77d5fd45d1a750c739ec6",
+			"\x03": "93f79041bb3fd266105be251adaeb5fd7f8b104fb554a4ba9a0becea48ddbfd7",
+		},
+		sigShare: map[party.ID]string{
+			"\x01": "c4fce1775a1e141fb579944166eab0d65eefe7b98d480a569bbbfcb14f91c197",
+			"\x03": "0160fd0d388932f4826d2ebcd6b9eaba734f7c71cf25b4279a4ca2581e47b18d",
+		},
+		sig: "0205b6d04d3774c8929413e3c76024d54149c372d57aae62574ed74319b5ea14d0" +
+			"c65dde8492a7471437e6c2fe3da49b90d23f642b5c6dbe7e36089f096dd97324",
+	},
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	data, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return data
+}
+
+// decodeScalar decodes a scalar serialized by the ciphersuite.
+func decodeScalar(t *testing.T, cs ciphersuite, s string) curve.Scalar {
+	data := decodeHex(t, s)
+	if _, ok := cs.(ed25519SHA512); ok {
+		data = reverse(data)
+	}
+	scalar := cs.group().NewScalar()
+	require.NoError(t, scalar.UnmarshalBinary(data))
+	return scalar
+}
+
+func TestSignRFC9591Vectors(t *testing.T) {
+	for _, v := range rfc9591Vectors {
+		cs := v.variant.ciphersuite()
+		group := cs.group()
+		signers := []party.ID{"\x01", "\x03"}
+		m := decodeHex(t, v.message)
+
+		secret := decodeScalar(t, cs, v.groupSecretKey)
+		coefficient := decodeScalar(t, cs, v.coefficient)
+		Y := secret.ActOnBase()
+		YBytes, err := cs.serializeElement(Y)
+		require.NoError(t, err)
+		require.Equal(t, v.groupPublicKey, hex.EncodeToString(YBytes))
+
+		privateShares := make(map[party.ID]curve.Scalar, len(signers))
+		verificationShares := make(map[party.ID]curve.Point, len(signers))
+		for _, id := range signers {
+			privateShares[id] = group.NewScalar().Set(coefficient).Mul(id.Scalar(group)).Add(secret)
+			verificationShares[id] = privateShares[id].ActOnBase()
+		}
+
+		rounds := make([]round.Session, 0, len(signers))
+		for _, id := range signers {
+			config := &keygen.Config{
+				ID:                 id,
+				Threshold:          1,
+				PublicKey:          Y,
+				PrivateShare:       privateShares[id],
+				VerificationShares: party.NewPointMap(verificationShares),
+			}
+			randomness := append(decodeHex(t, v.hidingRandomness[id]), decodeHex(t, v.bindingRandomness[id])...)
+			r, err := StartSignCommon(v.variant, config, signers, m, bytes.NewReader(randomness))(nil)
+			require.NoError(t, err, "round creation should not result in an error")
+			rounds = append(rounds, r)
+		}
+
+		for {
+			err, done := test.Rounds(rounds, nil)
+			require.NoError(t, err, "failed to process round")
+			if done {
+				break
+			}
+			if r3, ok := rounds[0].(*round3); ok {
+				for _, id := range signers {
+					assert.Equal(t, v.sigShare[id], hex.EncodeToString(cs.serializeScalar(r3.z[id])), "sig share of %x", id)
+				}
+			}
+		}
+
+		for _, r := range rounds {
+			require.IsType(t, &round.Output{}, r, "expected result round")
+			sig := r.(*round.Output).Result.([]byte)
+			assert.Equal(t, v.sig, hex.EncodeToString(sig))
+			assert.True(t, VerifyRFC9591(v.variant, Y, m, sig))
+			if v.variant == Ed25519SHA512 {
+				assert.True(t, ed25519.Verify(YBytes, m, sig), "expected valid RFC 8032 signature")
+			}
+		}
+	}
+}
+
+func TestRFC9591Steps(t *testing.T) {
+	for _, v := range rfc9591Vectors {
+		cs := v.variant.ciphersuite()
+		group := cs.group()
+		signers := []party.ID{"\x03", "\x01"}
+		m := decodeHex(t, v.message)
+
+		secret := decodeScalar(t, cs, v.groupSecretKey)
+		coefficient := decodeScalar(t, cs, v.coefficient)
+		Y := secret.ActOnBase()
+
+		D := make(map[party.ID]curve.Point, len(signers))
+		E := make(map[party.ID]curve.Point, len(signers))
+		for _, id := range signers {
+			share := group.NewScalar().Set(coefficient).Mul(id.Scalar(group)).Add(secret)
+			d, err := nonceGenerate(cs, share, bytes.NewReader(decodeHex(t, v.hidingRandomness[id])))
+			require.NoError(t, err)
+			e, err := nonceGenerate(cs, share, bytes.NewReader(decodeHex(t, v.bindingRandomness[id])))
+			require.NoError(t, err)
+			assert.Equal(t, v.hidingNonce[id], hex.EncodeToString(cs.serializeScalar(d)))
+			assert.Equal(t, v.bindingNonce[id], hex.EncodeToString(cs.serializeScalar(e)))
+			D[id] = d.ActOnBase()
+			E[id] = e.ActOnBase()
+		}
+
+		// the order of the signers must not matter
+		rho, err := bindingFactors(cs, Y, signers, D, E, m)
+		require.NoError(t, err)
+		for _, id := range signers {
+			assert.Equal(t, v.bindingFactor[id], hex.EncodeToString(cs.serializeScalar(rho[id])))
+		}
+	}
+}
+
+func TestExpandMessageXMD(t *testing.T) {
+	// https://datatracker.ietf.org/doc/html/rfc9380#appendix-K.1
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	assert.Equal(t, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235",
+		hex.EncodeToString(expandMessageXMD([]byte(""), dst, 0x20)))
+}
+
+func TestSignRFC9591(t *testing.T) {
+	for _, variant := range []Variant{Secp256k1SHA256, Ed25519SHA512} {
+		group := variant.ciphersuite().group()
+		N := 5
+		threshold := 2
+		partyIDs := test.PartyIDs(N)
+
+		secret := sample.Scalar(rand.Reader, group)
+		f := polynomial.NewPolynomial(rand.Reader, group, threshold, secret)
+		publicKey := secret.ActOnBase()
+		m := []byte("hello")
+
+		privateShares := make(map[party.ID]curve.Scalar, N)
+		verificationShares := make(map[party.ID]curve.Point, N)
+		for _, id := range partyIDs {
+			privateShares[id] = f.Evaluate(id.Scalar(group))
+			verificationShares[id] = privateShares[id].ActOnBase()
+		}
+
+		rounds := make([]round.Session, 0, N)
+		for _, id := range partyIDs {
+			config := &keygen.Config{
+				ID:                 id,
+				Threshold:          threshold,
+				PublicKey:          publicKey,
+				PrivateShare:       privateShares[id],
+				VerificationShares: party.NewPointMap(verificationShares),
+			}
+			r, err := StartSignCommon(variant, config, partyIDs, m, rand.Reader)(nil)
+			require.NoError(t, err, "round creation should not result in an error")
+			rounds = append(rounds, r)
+		}
+
+		for {
+			err, done := test.Rounds(rounds, nil)
+			require.NoError(t, err, "failed to process round")
+			if done {
+				break
+			}
+		}
+
+		for _, r := range rounds {
+			require.IsType(t, &round.Output{}, r, "expected result round")
+			sig := r.(*round.Output).Result.([]byte)
+			assert.True(t, VerifyRFC9591(variant, publicKey, m, sig), "expected valid signature")
+			assert.False(t, VerifyRFC9591(variant, publicKey, []byte("other"), sig))
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package sign
 
 import (
-	"crypto/rand"
+	"io"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -21,12 +21,13 @@ import (
 // namely that these commitments are broadcast, instead of stored with the authority.
 type round1 struct {
 	*round.Helper
-	// taproot indicates whether or not we need to generate Taproot / BIP-340 signatures.
+	// variant indicates which kind of signature we need to generate.
 	//
-	// If so, we have a few slight tweaks to make around the evenness of points,
-	// and we need to make sure to generate our challenge in the correct way. Naturally,
-	// we also return a taproot.Signature instead a generic signature.
-	taproot bool
+	// For Taproot / BIP-340 signatures, we have a few slight tweaks to make around
+	// the evenness of points. For both Taproot and the RFC 9591 ciphersuites, we need to make sure
+	// to generate our challenge in the correct way. Naturally, we also return
+	// a taproot.Signature or the encoding of the ciphersuite instead a generic signature.
+	variant Variant
 	// M is the hash of the message we're signing.
 	//
 	// This plays the same role as m in the Frost paper. One slight difference
@@ -56,6 +57,42 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// to generate two nonces (dᵢ, eᵢ) in Z/(q)ˣ, then two commitments
 	// Dᵢ = dᵢ * G, Eᵢ = eᵢ * G, and then broadcast them.
 
+	var (
+		d_i, e_i curve.Scalar
+		err      error
+	)
+	if cs := r.variant.ciphersuite(); cs != nil {
+		// RFC 9591 specifies how nonces are generated, see:
+		// https://datatracker.ietf.org/doc/html/rfc9591#section-5.1
+		if d_i, err = nonceGenerate(cs, r.s_i, r.Rand()); err != nil {
+			return r, err
+		}
+		if e_i, err = nonceGenerate(cs, r.s_i, r.Rand()); err != nil {
+			return r, err
+		}
+	} else if d_i, e_i, err = r.hedgedNonces(); err != nil {
+		return r, err
+	}
+
+	D_i := d_i.ActOnBase()
+	E_i := e_i.ActOnBase()
+
+	// Broadcast the commitments
+	err = r.BroadcastMessage(out, &broadcast2{D_i: D_i, E_i: E_i})
+	if err != nil {
+		return r, err
+	}
+	return &round2{
+		round1: r,
+		d_i:    d_i,
+		e_i:    e_i,
+		D:      map[party.ID]curve.Point{r.SelfID(): D_i},
+		E:      map[party.ID]curve.Point{r.SelfID(): E_i},
+	}, nil
+}
+
+// hedgedNonces returns the nonces (dᵢ, eᵢ) for the variants which don't follow RFC 9591.
+func (r *round1) hedgedNonces() (curve.Scalar, curve.Scalar, error) {
 	// We use a hedged deterministic process, instead of simply sampling (d_i, e_i):
 	//
 	//   a = random()
@@ -66,7 +103,7 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// and fault attacks against the hash function, because of the randomness.
 	s_iBytes, err := r.s_i.MarshalBinary()
 	if err != nil {
-		return r, err
+		return nil, nil, err
 	}
 
 	hashKey := make([]byte, 32)
@@ -75,28 +112,13 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	_, _ = nonceHasher.Write(r.Hash().Sum())
 	_, _ = nonceHasher.Write(r.M)
 	a := make([]byte, 32)
-	_, _ = rand.Read(a)
+	_, _ = io.ReadFull(r.Rand(), a)
 	_, _ = nonceHasher.Write(a)
 	nonceDigest := nonceHasher.Digest()
 
 	d_i := sample.ScalarUnit(nonceDigest, r.Group())
 	e_i := sample.ScalarUnit(nonceDigest, r.Group())
-
-	D_i := d_i.ActOnBase()
-	E_i := e_i.ActOnBase()
-
-	// Broadcast the commitments
-	err = r.BroadcastMessage(out, &broadcast2{D_i: D_i, E_i: E_i})
-	if err != nil {
-		return r, err
-	}
-	return &round2{
-		round1: r,
-		d_i:    d_i,
-		e_i:    e_i,
-		D:      map[party.ID]curve.Point{r.SelfID(): D_i},
-		E:      map[party.ID]curve.Point{r.SelfID(): E_i},
-	}, nil
+	return d_i, e_i, nil
 }
 
 // MessageContent implements round.Round.
@@ -65,7 +65,7 @@ func (r *round2) StoreBroadcastMessage(msg round.Message) error {
 	// We also receive each Dₗ, Eₗ from the participant l directly, instead of
 	// an entire bundle from a signing authority.
 	if body.D_i.IsIdentity() || body.E_i.IsIdentity() {
-		return fmt.Errorf("nonce commitment is the identity point")
+		return fmt.Errorf("nonce commitment is the identity point: %w", round.ErrMalformed)
 	}
 
 	r.D[msg.From] = body.D_i
@@ -92,18 +92,30 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 	//
 	// We also use a hash of the message, instead of the message directly.
 
-	rho := make(map[party.ID]curve.Scalar)
-	// This calculates H(m, B), allowing us to avoid re-hashing this data for
-	// each extra party l.
-	rhoPreHash := hash.New()
-	_ = rhoPreHash.WriteAny(r.M)
-	for _, l := range r.PartyIDs() {
-		_ = rhoPreHash.WriteAny(r.D[l], r.E[l])
-	}
-	for _, l := range r.PartyIDs() {
-		rhoHash := rhoPreHash.Clone()
-		_ = rhoHash.WriteAny(l)
-		rho[l] = sample.Scalar(rhoHash.Digest(), r.Group())
+	var rho map[party.ID]curve.Scalar
+	if cs := r.variant.ciphersuite(); cs != nil {
+		// RFC 9591 computes the binding values with H₁, over encodings of the public key,
+		// the message, and the list of commitments, see:
+		// https://datatracker.ietf.org/doc/html/rfc9591#section-4.4
+		var err error
+		rho, err = bindingFactors(cs, r.Y, r.PartyIDs(), r.D, r.E, r.M)
+		if err != nil {
+			return r, err
+		}
+	} else {
+		rho = make(map[party.ID]curve.Scalar)
+		// This calculates H(m, B), allowing us to avoid re-hashing this data for
+		// each extra party l.
+		rhoPreHash := hash.New()
+		_ = rhoPreHash.WriteAny(r.M)
+		for _, l := range r.PartyIDs() {
+			_ = rhoPreHash.WriteAny(r.D[l], r.E[l])
+		}
+		for _, l := range r.PartyIDs() {
+			rhoHash := rhoPreHash.Clone()
+			_ = rhoHash.WriteAny(l)
+			rho[l] = sample.Scalar(rhoHash.Digest(), r.Group())
+		}
 	}
 
 	R := r.Group().NewPoint()
@@ -114,7 +126,8 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 		R = R.Add(RShares[l])
 	}
 	var c curve.Scalar
-	if r.taproot {
+	switch r.variant {
+	case Taproot:
 		// BIP-340 adjustment: We need R to have an even y coordinate. This means
 		// conditionally negating k = ∑ᵢ (dᵢ + (eᵢ ρᵢ)), which we can accomplish
 		// by negating our dᵢ, eᵢ, if necessary. This entails negating the RShares
@@ -134,7 +147,16 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 		PBytes := r.Y.(*curve.Secp256k1Point).XBytes()
 		cHash := taproot.TaggedHash("BIP0340/challenge", RBytes, PBytes, r.M)
 		c = r.Group().NewScalar().SetNat(new(saferith.Nat).SetBytes(cHash))
-	} else {
+	case Secp256k1SHA256, Ed25519SHA512:
+		// RFC 9591 adjustment: the challenge is H₂(R || Y || m), using the encodings
+		// of the ciphersuite, see:
+		// https://datatracker.ietf.org/doc/html/rfc9591#section-4.6
+		var err error
+		c, err = challenge(r.variant.ciphersuite(), R, r.Y, r.M)
+		if err != nil {
+			return r, err
+		}
+	default:
 		cHash := hash.New()
 		_ = cHash.WriteAny(R, r.Y, r.M)
 		c = sample.Scalar(cHash.Digest(), r.Group())
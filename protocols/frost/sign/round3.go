@@ -70,7 +70,7 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 	actual := body.Z_i.ActOnBase()
 
 	if !actual.Equal(expected) {
-		return fmt.Errorf("failed to verify response from %v", from)
+		return fmt.Errorf("failed to verify response from %v: %w", from, round.ErrInvalidShare)
 	}
 
 	r.z[from] = body.Z_i
@@ -94,8 +94,9 @@ func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
 		z.Add(z_l)
 	}
 
-	// The format of our signature depends on using taproot, naturally
-	if r.taproot {
+	// The format of our signature depends on the variant, naturally
+	switch r.variant {
+	case Taproot:
 		sig := taproot.Signature(make([]byte, 0, taproot.SignatureLen))
 		sig = append(sig, r.R.(*curve.Secp256k1Point).XBytes()...)
 		zBytes, err := z.MarshalBinary()
@@ -111,7 +112,16 @@ func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
 		}
 
 		return r.ResultRound(sig), nil
-	} else {
+	case Secp256k1SHA256, Ed25519SHA512:
+		sig, err := encodeSignature(r.variant.ciphersuite(), r.R, z)
+		if err != nil {
+			return r, err
+		}
+		if !VerifyRFC9591(r.variant, r.Y, r.M, sig) {
+			return r.AbortRound(fmt.Errorf("generated signature failed to verify")), nil
+		}
+		return r.ResultRound(sig), nil
+	default:
 		sig := Signature{
 			R: r.R,
 			z: z,
@@ -1,9 +1,12 @@
 package sign
 
 import (
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
 	"github.com/taurusgroup/multi-party-sig/protocols/frost/keygen"
@@ -13,11 +16,44 @@ const (
 	// Frost Sign with Threshold.
 	protocolID        = "frost/sign-threshold"
 	protocolIDTaproot = "frost/sign-threshold-taproot"
+	// RFC 9591 ciphersuites.
+	protocolIDSecp256k1SHA256 = "frost/sign-threshold-secp256k1-sha256"
+	protocolIDEd25519SHA512   = "frost/sign-threshold-ed25519-sha512"
 	// This protocol has 3 concrete rounds.
 	protocolRounds round.Number = 3
 )
 
-func StartSignCommon(taproot bool, result *keygen.Config, signers []party.ID, messageHash []byte) protocol.StartFunc {
+// Variant determines which kind of Schnorr signature the protocol produces.
+type Variant uint8
+
+const (
+	// Generic produces a Signature, with a challenge computed using hash.Hash.
+	Generic Variant = iota
+	// Taproot produces a taproot.Signature, as specified in BIP-340.
+	//
+	// This requires the group to be curve.Secp256k1.
+	Taproot
+	// Ed25519 produces a 64 byte signature, as specified in RFC 8032, which can be verified using crypto/ed25519.
+	//
+	// Deprecated: this is now the same as Ed25519SHA512, whose signatures are valid RFC 8032 signatures.
+	Ed25519
+	// Secp256k1SHA256 follows the FROST(secp256k1, SHA-256) ciphersuite of RFC 9591,
+	// producing a 65 byte signature R || z, which can be verified using VerifyRFC9591.
+	//
+	// This requires the group to be curve.Secp256k1. The message is signed directly, instead of a hash.
+	//
+	// See: https://datatracker.ietf.org/doc/html/rfc9591#section-6.5
+	Secp256k1SHA256
+	// Ed25519SHA512 follows the FROST(Ed25519, SHA-512) ciphersuite of RFC 9591,
+	// producing a 64 byte signature, as specified in RFC 8032, which can be verified using crypto/ed25519.
+	//
+	// This requires the group to be curve.Ed25519. The message is signed directly, instead of a hash.
+	//
+	// See: https://datatracker.ietf.org/doc/html/rfc9591#section-6.1
+	Ed25519SHA512
+)
+
+func StartSignCommon(variant Variant, result *keygen.Config, signers []party.ID, messageHash []byte, rand io.Reader) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		info := round.Info{
 			FinalRoundNumber: protocolRounds,
@@ -25,11 +61,29 @@ func StartSignCommon(taproot bool, result *keygen.Config, signers []party.ID, me
 			PartyIDs:         signers,
 			Threshold:        result.Threshold,
 			Group:            result.PublicKey.Curve(),
+			Rand:             rand,
 		}
-		if taproot {
-			info.ProtocolID = protocolIDTaproot
-		} else {
+		switch variant {
+		case Generic:
 			info.ProtocolID = protocolID
+		case Taproot:
+			if _, ok := info.Group.(curve.Secp256k1); !ok {
+				return nil, errors.New("sign.StartSign: taproot signatures require secp256k1")
+			}
+			info.ProtocolID = protocolIDTaproot
+		case Secp256k1SHA256:
+			if _, ok := info.Group.(curve.Secp256k1); !ok {
+				return nil, errors.New("sign.StartSign: the secp256k1 ciphersuite requires secp256k1")
+			}
+			info.ProtocolID = protocolIDSecp256k1SHA256
+		case Ed25519, Ed25519SHA512:
+			variant = Ed25519SHA512
+			if _, ok := info.Group.(curve.Ed25519); !ok {
+				return nil, errors.New("sign.StartSign: the ed25519 ciphersuite requires the ed25519 group")
+			}
+			info.ProtocolID = protocolIDEd25519SHA512
+		default:
+			return nil, fmt.Errorf("sign.StartSign: unknown variant %d", variant)
 		}
 
 		helper, err := round.NewSession(info, sessionID, nil)
@@ -38,7 +92,7 @@ func StartSignCommon(taproot bool, result *keygen.Config, signers []party.ID, me
 		}
 		return &round1{
 			Helper:  helper,
-			taproot: taproot,
+			variant: variant,
 			M:       messageHash,
 			Y:       result.PublicKey,
 			YShares: result.VerificationShares.Points,
@@ -10,6 +10,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/internal/params"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -37,7 +38,7 @@ func TestSign(t *testing.T) {
 	partyIDs := test.PartyIDs(N)
 
 	secret := sample.Scalar(rand.Reader, group)
-	f := polynomial.NewPolynomial(group, threshold, secret)
+	f := polynomial.NewPolynomial(rand.Reader, group, threshold, secret)
 	publicKey := secret.ActOnBase()
 	steak := []byte{0xDE, 0xAD, 0xBE, 0xEF}
 	chainKey := make([]byte, params.SecBytes)
@@ -68,7 +69,7 @@ func TestSign(t *testing.T) {
 		if newPublicKey == nil {
 			newPublicKey = result.PublicKey
 		}
-		r, err := StartSignCommon(false, result, partyIDs, steak)(nil)
+		r, err := StartSignCommon(Generic, result, partyIDs, steak, rand.Reader)(nil)
 		require.NoError(t, err, "round creation should not result in an error")
 		rounds = append(rounds, r)
 	}
@@ -106,7 +107,7 @@ func TestSignTaproot(t *testing.T) {
 	if !publicPoint.(*curve.Secp256k1Point).HasEvenY() {
 		secret.Negate()
 	}
-	f := polynomial.NewPolynomial(group, threshold, secret)
+	f := polynomial.NewPolynomial(rand.Reader, group, threshold, secret)
 	publicKey := taproot.PublicKey(publicPoint.(*curve.Secp256k1Point).XBytes())
 	steakHash := sha256.New()
 	_, _ = steakHash.Write([]byte{0xDE, 0xAD, 0xBE, 0xEF})
@@ -151,7 +152,7 @@ func TestSignTaproot(t *testing.T) {
 			PublicKey:          tapRootPublicKey,
 			VerificationShares: party.NewPointMap(genericVerificationShares),
 		}
-		r, err := StartSignCommon(true, normalResult, partyIDs, steak)(nil)
+		r, err := StartSignCommon(Taproot, normalResult, partyIDs, steak, rand.Reader)(nil)
 		require.NoError(t, err, "round creation should not result in an error")
 		rounds = append(rounds, r)
 	}
@@ -166,3 +167,38 @@ func TestSignTaproot(t *testing.T) {
 
 	checkOutputTaproot(t, rounds, newPublicKey, steak)
 }
+
+func TestSignatureMarshal(t *testing.T) {
+	m := []byte("hello")
+	for _, group := range []curve.Curve{curve.Secp256k1{}, curve.Ed25519{}} {
+		x := sample.Scalar(rand.Reader, group)
+		X := x.ActOnBase()
+		k := sample.Scalar(rand.Reader, group)
+		R := k.ActOnBase()
+		challengeHash := hash.New()
+		require.NoError(t, challengeHash.WriteAny(R, X, messageHash(m)))
+		challenge := sample.Scalar(challengeHash.Digest(), group)
+		sig := Signature{R: R, z: challenge.Mul(x).Add(k)}
+		require.True(t, sig.Verify(X, m))
+
+		data, err := sig.MarshalBinary()
+		require.NoError(t, err)
+
+		sig2 := EmptySignature(group)
+		require.NoError(t, sig2.UnmarshalBinary(data))
+		assert.True(t, sig2.Verify(X, m), "unmarshalled signature should verify")
+		assert.True(t, sig2.R.Equal(sig.R))
+		assert.True(t, sig2.Z().Equal(sig.Z()))
+		assert.Equal(t, group.Name(), sig2.Curve().Name())
+
+		sig3 := new(Signature)
+		require.NoError(t, sig3.UnmarshalBinary(data))
+		assert.True(t, sig3.Verify(X, m), "unmarshalled signature should verify")
+
+		other := EmptySignature(curve.P256{})
+		assert.Error(t, other.UnmarshalBinary(data), "unmarshalling with another curve should fail")
+	}
+
+	_, err := Signature{}.MarshalBinary()
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+package sign
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/snapshot"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// state is the serialized form of the rounds of this protocol.
+//
+// The fields of round1 are set by the StartFunc, so only the values computed afterwards are included.
+type state struct {
+	Hash     []hash.BytesWithDomain
+	D_i, E_i []byte
+	D, E     map[party.ID][]byte
+	R        []byte
+	RShares  map[party.ID][]byte
+	C        []byte
+	Z        map[party.ID][]byte
+	Lambda   map[party.ID][]byte
+}
+
+// snapshotter is implemented by each round, which adds its own fields to those of the previous round.
+type snapshotter interface {
+	snapshot(s *state, e *snapshot.Encoder)
+}
+
+func (r *round1) snapshot(s *state, _ *snapshot.Encoder) {
+	s.Hash = r.HashUpdates()
+}
+
+func (r *round2) snapshot(s *state, e *snapshot.Encoder) {
+	r.round1.snapshot(s, e)
+	s.D_i = e.Scalar(r.d_i)
+	s.E_i = e.Scalar(r.e_i)
+	s.D = e.Points(r.D)
+	s.E = e.Points(r.E)
+}
+
+func (r *round3) snapshot(s *state, e *snapshot.Encoder) {
+	r.round2.snapshot(s, e)
+	s.R = e.Point(r.R)
+	s.RShares = e.Points(r.RShares)
+	s.C = e.Scalar(r.c)
+	s.Z = e.Scalars(r.z)
+	s.Lambda = e.Scalars(r.Lambda)
+}
+
+// Snapshot implements round.Restorer.
+func (r *round1) Snapshot(current round.Session) ([]byte, error) {
+	c, ok := current.(snapshotter)
+	if !ok {
+		return nil, round.ErrNoSnapshot
+	}
+	var (
+		s state
+		e snapshot.Encoder
+	)
+	c.snapshot(&s, &e)
+	if err := e.Err(); err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(&s)
+}
+
+// Restore implements round.Restorer.
+func (r *round1) Restore(number round.Number, data []byte) (round.Session, error) {
+	var s state
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if err := r.RestoreHashUpdates(s.Hash); err != nil {
+		return nil, err
+	}
+	d := snapshot.NewDecoder(r.Group())
+	r2 := &round2{
+		round1: r,
+		d_i:    d.Scalar(s.D_i),
+		e_i:    d.Scalar(s.E_i),
+		D:      d.Points(s.D),
+		E:      d.Points(s.E),
+	}
+	r3 := &round3{
+		round2:  r2,
+		R:       d.Point(s.R),
+		RShares: d.Points(s.RShares),
+		c:       d.Scalar(s.C),
+		z:       d.Scalars(s.Z),
+		Lambda:  d.Scalars(s.Lambda),
+	}
+	if err := d.Err(); err != nil {
+		return nil, err
+	}
+	rounds := []round.Session{r, r2, r3}
+	if number < 1 || int(number) > len(rounds) {
+		return nil, fmt.Errorf("sign: invalid round %d", number)
+	}
+	return rounds[number-1], nil
+}
@@ -1,8 +1,11 @@
 package sign
 
 import (
+	"errors"
+	"fmt"
 	"io"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -56,3 +59,64 @@ func (sig Signature) Verify(public curve.Point, m []byte) bool {
 
 	return expected.Equal(actual)
 }
+
+// EmptySignature returns a new signature with a given curve, ready to be unmarshalled.
+//
+// Since the encoding produced by MarshalBinary contains the name of the curve,
+// UnmarshalBinary can also be called on new(Signature) directly.
+func EmptySignature(group curve.Curve) Signature {
+	return Signature{R: group.NewPoint(), z: group.NewScalar()}
+}
+
+// Z returns the response scalar of the signature.
+func (sig Signature) Z() curve.Scalar {
+	return sig.z
+}
+
+// Curve returns the Elliptic Curve Group associated with this signature.
+func (sig Signature) Curve() curve.Curve {
+	return sig.R.Curve()
+}
+
+type signatureMarshal struct {
+	// Group is the name of the curve, see curve.ByName.
+	Group string
+	R     curve.Point
+	Z     curve.Scalar
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (sig Signature) MarshalBinary() ([]byte, error) {
+	if sig.R == nil || sig.z == nil {
+		return nil, errors.New("frost signature: uninitialized signature")
+	}
+	return cbor.Marshal(&signatureMarshal{
+		Group: sig.Curve().Name(),
+		R:     sig.R,
+		Z:     sig.z,
+	})
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (sig *Signature) UnmarshalBinary(data []byte) error {
+	var header struct{ Group string }
+	if err := cbor.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("frost signature: %w", err)
+	}
+	group, err := curve.ByName(header.Group)
+	if err != nil {
+		return fmt.Errorf("frost signature: %w", err)
+	}
+	if sig.R != nil && sig.Curve().Name() != group.Name() {
+		return fmt.Errorf("frost signature: expected curve %s, found %s", sig.Curve().Name(), group.Name())
+	}
+	sm := &signatureMarshal{
+		R: group.NewPoint(),
+		Z: group.NewScalar(),
+	}
+	if err := cbor.Unmarshal(data, sm); err != nil {
+		return fmt.Errorf("frost signature: %w", err)
+	}
+	*sig = Signature{R: sm.R, z: sm.Z}
+	return nil
+}
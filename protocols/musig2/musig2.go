@@ -0,0 +1,53 @@
+// Package musig2 implements the MuSig2 multi-signature scheme, as specified in BIP-327.
+//
+// Each signer has its own key pair, and the public keys are aggregated into a single
+// public key, for which all the signers together produce BIP-340 Schnorr signatures,
+// which are indistinguishable from the signatures of a single party.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki
+package musig2
+
+import (
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/musig2/sign"
+)
+
+type (
+	Config        = sign.Config
+	Tweak         = sign.Tweak
+	KeyAggContext = sign.KeyAggContext
+	PublicNonce   = sign.PublicNonce
+)
+
+// ParsePublicKey decodes an individual public key, in the 33 byte compressed format used by BIP-327.
+func ParsePublicKey(data []byte) (*curve.Secp256k1Point, error) {
+	return sign.ParsePublicKey(data)
+}
+
+// KeySort sorts public keys by their encoding, so that their aggregation doesn't depend on the order
+// in which they were given.
+func KeySort(publicKeys []*curve.Secp256k1Point) []*curve.Secp256k1Point {
+	return sign.KeySort(publicKeys)
+}
+
+// KeyAgg aggregates the public keys of the signers into a single public key Q = ∑ᵢ aᵢ⋅Pᵢ,
+// where the coefficients aᵢ depend on all the keys.
+func KeyAgg(publicKeys []*curve.Secp256k1Point) (*KeyAggContext, error) {
+	return sign.KeyAgg(publicKeys)
+}
+
+// Sign initiates the MuSig2 protocol for producing a signature of a message.
+//
+// config contains our private key, and the public keys of all the signers, every one of which
+// must take part. The result is a taproot.Signature, valid for the public key given by config.PublicKey().
+//
+// The protocol has two rounds of communication. In the first, each signer broadcasts the
+// public nonce committing to its two secret nonces. Once it has received all of them,
+// each signer broadcasts its partial signature, and the partial signatures are then
+// verified and aggregated into the final signature.
+//
+// The message is signed directly, and can have any length.
+func Sign(config *Config, message []byte, opts ...protocol.StartOption) protocol.StartFunc {
+	return sign.StartSign(config, message, protocol.Rand(opts...))
+}
@@ -0,0 +1,78 @@
+package musig2
+
+import (
+	"crypto/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+)
+
+func do(t *testing.T, config *Config, message []byte, n *test.Network, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	publicKey, err := config.PublicKey()
+	require.NoError(t, err)
+
+	h, err := protocol.NewMultiHandler(Sign(config, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(config.ID, h, n)
+
+	signResult, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, taproot.Signature{}, signResult)
+	signature := signResult.(taproot.Signature)
+	assert.True(t, publicKey.Verify(signature, message))
+
+	// Spend a Taproot output without a script path, with the aggregated key as internal key.
+	tweaked, err := config.TweakTaproot(nil)
+	require.NoError(t, err)
+	outputKey, err := publicKey.Tweak(nil)
+	require.NoError(t, err)
+	tweakedKey, err := tweaked.PublicKey()
+	require.NoError(t, err)
+	require.Equal(t, outputKey, tweakedKey)
+
+	h, err = protocol.NewMultiHandler(Sign(tweaked, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(config.ID, h, n)
+
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, taproot.Signature{}, signResult)
+	signature = signResult.(taproot.Signature)
+	assert.True(t, outputKey.Verify(signature, message))
+}
+
+func TestMuSig2(t *testing.T) {
+	N := 3
+	partyIDs := test.PartyIDs(N)
+	message := []byte("hello")
+
+	privateKeys := make(map[party.ID]*curve.Secp256k1Scalar, N)
+	publicKeys := make(map[party.ID]*curve.Secp256k1Point, N)
+	for _, id := range partyIDs {
+		privateKeys[id] = sample.Scalar(rand.Reader, curve.Secp256k1{}).(*curve.Secp256k1Scalar)
+		publicKeys[id] = privateKeys[id].ActOnBase().(*curve.Secp256k1Point)
+	}
+
+	n := test.NewNetwork(partyIDs)
+	var wg sync.WaitGroup
+	for _, id := range partyIDs {
+		wg.Add(1)
+		config := &Config{
+			ID:         id,
+			PrivateKey: privateKeys[id],
+			PublicKeys: publicKeys,
+		}
+		go do(t, config, message, n, &wg)
+	}
+	wg.Wait()
+}
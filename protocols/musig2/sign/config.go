@@ -0,0 +1,105 @@
+package sign
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+)
+
+// Tweak is a tweak t applied to the aggregated public key Q, see KeyAggContext.ApplyTweak.
+type Tweak struct {
+	// Tweak is the 32 byte encoding of t.
+	Tweak []byte
+	// XOnly indicates whether t is added to the x-only key of Q, as in Taproot, or to Q itself.
+	XOnly bool
+}
+
+// Config contains the keys of the signers in a MuSig2 session.
+//
+// Unlike the threshold protocols, every party has its own independent key pair,
+// and all of them are needed to sign.
+type Config struct {
+	// ID is the identifier of this party.
+	ID party.ID
+	// PrivateKey is the secret key of this party.
+	PrivateKey *curve.Secp256k1Scalar
+	// PublicKeys contains the public key of every signer, including this party.
+	PublicKeys map[party.ID]*curve.Secp256k1Point
+	// Tweaks are applied in order to the aggregated public key.
+	Tweaks []Tweak
+}
+
+// KeyAgg aggregates the public keys of the signers, after sorting them with KeySort,
+// and applies the tweaks of the config.
+func (c *Config) KeyAgg() (*KeyAggContext, error) {
+	publicKeys := make([]*curve.Secp256k1Point, 0, len(c.PublicKeys))
+	for _, P := range c.PublicKeys {
+		publicKeys = append(publicKeys, P)
+	}
+	ctx, err := KeyAgg(KeySort(publicKeys))
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range c.Tweaks {
+		if ctx, err = ctx.ApplyTweak(t.Tweak, t.XOnly); err != nil {
+			return nil, err
+		}
+	}
+	return ctx, nil
+}
+
+// PublicKey returns the x-only public key for which the signers produce signatures.
+func (c *Config) PublicKey() (taproot.PublicKey, error) {
+	ctx, err := c.KeyAgg()
+	if err != nil {
+		return nil, err
+	}
+	return ctx.PublicKey(), nil
+}
+
+// Signers returns the identifiers of all the signers.
+func (c *Config) Signers() party.IDSlice {
+	ids := make([]party.ID, 0, len(c.PublicKeys))
+	for id := range c.PublicKeys {
+		ids = append(ids, id)
+	}
+	return party.NewIDSlice(ids)
+}
+
+// TweakTaproot returns a copy of the config, whose public key is the output key of a Taproot output,
+// with the current public key as its internal key, and the given script tree.
+//
+// merkleRoot is the root of the script tree, or empty for an output without a script path, as in BIP-86.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki#constructing-and-spending-taproot-outputs
+func (c *Config) TweakTaproot(merkleRoot []byte) (*Config, error) {
+	internal, err := c.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	t, err := taproot.TweakScalar(internal, merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	tBytes, _ := t.MarshalBinary()
+	tweaked := *c
+	tweaked.Tweaks = append(append([]Tweak{}, c.Tweaks...), Tweak{Tweak: tBytes, XOnly: true})
+	return &tweaked, nil
+}
+
+// validate checks that the private key matches the public key of this party.
+func (c *Config) validate() error {
+	if c.PrivateKey == nil || c.PrivateKey.IsZero() {
+		return errors.New("musig2: missing private key")
+	}
+	P, ok := c.PublicKeys[c.ID]
+	if !ok || P == nil {
+		return errors.New("musig2: missing public key of this party")
+	}
+	if !c.PrivateKey.ActOnBase().Equal(P) {
+		return errors.New("musig2: private key doesn't match public key")
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package sign
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+)
+
+// PublicKeyLength is the number of bytes in the encoding of an individual public key.
+const PublicKeyLength = 33
+
+// ParsePublicKey decodes an individual public key, in the 33 byte compressed format used by BIP-327.
+//
+// This is the cpoint function of BIP-327.
+func ParsePublicKey(data []byte) (*curve.Secp256k1Point, error) {
+	if len(data) != PublicKeyLength || (data[0] != 2 && data[0] != 3) {
+		return nil, errors.New("musig2: invalid public key encoding")
+	}
+	P := new(curve.Secp256k1Point)
+	if err := P.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("musig2: invalid public key: %w", err)
+	}
+	return P, nil
+}
+
+// KeySort sorts public keys by their encoding, so that their aggregation doesn't depend on the order
+// in which they were given.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki#key-sorting
+func KeySort(publicKeys []*curve.Secp256k1Point) []*curve.Secp256k1Point {
+	sorted := make([]*curve.Secp256k1Point, len(publicKeys))
+	copy(sorted, publicKeys)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return bytes.Compare(encodePoint(sorted[i]), encodePoint(sorted[j])) < 0
+	})
+	return sorted
+}
+
+// KeyAggContext is the result of the aggregation of the public keys of the signers,
+// along with the tweaks which were applied to it.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki#key-generation-and-aggregation
+type KeyAggContext struct {
+	// q = Q is the aggregated public key, with all tweaks applied.
+	q *curve.Secp256k1Point
+	// gacc and tacc accumulate the negations and the tweaks applied to Q.
+	gacc, tacc curve.Scalar
+	// keys are the encodings of the public keys, in the order in which they were aggregated.
+	keys [][]byte
+	// secondKey is the first key which differs from keys[0], or nil.
+	secondKey []byte
+	// listHash = L is the hash of all the keys.
+	listHash []byte
+}
+
+// KeyAgg aggregates the public keys of the signers into a single public key Q = ∑ᵢ aᵢ⋅Pᵢ,
+// where the coefficients aᵢ depend on all the keys.
+//
+// The result depends on the order of the keys, which can be normalized with KeySort.
+func KeyAgg(publicKeys []*curve.Secp256k1Point) (*KeyAggContext, error) {
+	if len(publicKeys) == 0 {
+		return nil, errors.New("musig2: no public keys")
+	}
+	ctx := &KeyAggContext{
+		gacc: curve.Secp256k1{}.NewScalar().SetNat(new(saferith.Nat).SetUint64(1)),
+		tacc: curve.Secp256k1{}.NewScalar(),
+		keys: make([][]byte, 0, len(publicKeys)),
+	}
+	for _, P := range publicKeys {
+		if P == nil || P.IsIdentity() {
+			return nil, errors.New("musig2: invalid public key")
+		}
+		ctx.keys = append(ctx.keys, encodePoint(P))
+	}
+	ctx.listHash = taproot.TaggedHash("KeyAgg list", ctx.keys...)
+	for _, key := range ctx.keys[1:] {
+		if !bytes.Equal(key, ctx.keys[0]) {
+			ctx.secondKey = key
+			break
+		}
+	}
+
+	Q := curve.Secp256k1{}.NewPoint()
+	for i, P := range publicKeys {
+		Q = Q.Add(ctx.coefficient(ctx.keys[i]).Act(P))
+	}
+	if Q.IsIdentity() {
+		return nil, errors.New("musig2: aggregated public key is the identity")
+	}
+	ctx.q = Q.(*curve.Secp256k1Point)
+	return ctx, nil
+}
+
+// coefficient returns the coefficient of the public key with the given encoding,
+// which must be one of the aggregated keys.
+func (ctx *KeyAggContext) coefficient(key []byte) curve.Scalar {
+	if bytes.Equal(key, ctx.secondKey) {
+		return curve.Secp256k1{}.NewScalar().SetNat(new(saferith.Nat).SetUint64(1))
+	}
+	return hashToScalar(taproot.TaggedHash("KeyAgg coefficient", ctx.listHash, key))
+}
+
+// contains checks whether the public key with the given encoding was aggregated.
+func (ctx *KeyAggContext) contains(key []byte) bool {
+	for _, k := range ctx.keys {
+		if bytes.Equal(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyTweak returns the context for the aggregated key tweaked by t⋅G.
+//
+// A plain tweak is added to Q, as in BIP-32 derivation.
+// An x-only tweak is added to the point with an even y coordinate and the same x coordinate as Q,
+// as in the Taproot output keys of BIP-341.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki#tweaking-definition
+func (ctx *KeyAggContext) ApplyTweak(tweak []byte, xOnly bool) (*KeyAggContext, error) {
+	t := new(curve.Secp256k1Scalar)
+	if err := t.UnmarshalBinary(tweak); err != nil {
+		return nil, fmt.Errorf("musig2: invalid tweak: %w", err)
+	}
+	group := curve.Secp256k1{}
+	g := group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1))
+	if xOnly && !ctx.q.HasEvenY() {
+		g.Negate()
+	}
+	Q := g.Act(ctx.q).Add(t.ActOnBase())
+	if Q.IsIdentity() {
+		return nil, errors.New("musig2: tweaked public key is the identity")
+	}
+	tweaked := *ctx
+	tweaked.q = Q.(*curve.Secp256k1Point)
+	tweaked.gacc = group.NewScalar().Set(g).Mul(ctx.gacc)
+	tweaked.tacc = group.NewScalar().Set(g).Mul(ctx.tacc).Add(t)
+	return &tweaked, nil
+}
+
+// Point returns the aggregated public key Q, including tweaks.
+func (ctx *KeyAggContext) Point() *curve.Secp256k1Point {
+	return ctx.q
+}
+
+// PublicKey returns the x-only aggregated public key, for which the signatures are valid.
+func (ctx *KeyAggContext) PublicKey() taproot.PublicKey {
+	return ctx.q.XBytes()
+}
+
+// encodePoint returns the 33 byte compressed encoding of P, or 33 zero bytes for the identity.
+//
+// This is the cbytes_ext function of BIP-327.
+func encodePoint(P *curve.Secp256k1Point) []byte {
+	if P.IsIdentity() {
+		return make([]byte, PublicKeyLength)
+	}
+	data, _ := P.MarshalBinary()
+	return data
+}
+
+// decodePoint is the inverse of encodePoint, which accepts the identity.
+//
+// This is the cpoint_ext function of BIP-327.
+func decodePoint(data []byte) (*curve.Secp256k1Point, error) {
+	if bytes.Equal(data, make([]byte, PublicKeyLength)) {
+		return curve.Secp256k1{}.NewPoint().(*curve.Secp256k1Point), nil
+	}
+	return ParsePublicKey(data)
+}
+
+// hashToScalar interprets a 32 byte hash as an integer modulo the order of the group.
+func hashToScalar(h []byte) curve.Scalar {
+	return curve.Secp256k1{}.NewScalar().SetNat(new(saferith.Nat).SetBytes(h))
+}
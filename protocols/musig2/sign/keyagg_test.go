@@ -0,0 +1,465 @@
+package sign
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// The test vectors come from BIP-327:
+//
+//	https://github.com/bitcoin/bips/tree/master/bip-0327/vectors
+
+func fromHex(t *testing.T, s string) []byte {
+	data, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return data
+}
+
+func parseKeys(t *testing.T, keys []string, indices []int) []*curve.Secp256k1Point {
+	points := make([]*curve.Secp256k1Point, 0, len(indices))
+	for _, i := range indices {
+		P, err := ParsePublicKey(fromHex(t, keys[i]))
+		require.NoError(t, err)
+		points = append(points, P)
+	}
+	return points
+}
+
+func TestKeyAggVectors(t *testing.T) {
+	keys := []string{
+		"02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+		"03DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"023590A94E768F8E1815C2F24B4D80A8E3149316C3518CE7B7AD338368D038CA66",
+	}
+	tests := []struct {
+		indices  []int
+		expected string
+	}{
+		{[]int{0, 1, 2}, "90539EEDE565F5D054F32CC0C220126889ED1E5D193BAF15AEF344FE59D4610C"},
+		{[]int{2, 1, 0}, "6204DE8B083426DC6EAF9502D27024D53FC826BF7D2012148A0575435DF54B2B"},
+		{[]int{0, 0, 0}, "B436E3BAD62B8CD409969A224731C193D051162D8C5AE8B109306127DA3AA935"},
+		{[]int{0, 0, 1, 1}, "69BC22BFA5D106306E48A20679DE1D7389386124D07571D0D872686028C26A3E"},
+	}
+	for _, tt := range tests {
+		ctx, err := KeyAgg(parseKeys(t, keys, tt.indices))
+		require.NoError(t, err)
+		assert.Equal(t, fromHex(t, tt.expected), []byte(ctx.PublicKey()), "indices %v", tt.indices)
+	}
+
+	// The aggregation doesn't depend on the order of the keys once they're sorted.
+	ctx1, err := KeyAgg(KeySort(parseKeys(t, keys, []int{0, 1, 2})))
+	require.NoError(t, err)
+	ctx2, err := KeyAgg(KeySort(parseKeys(t, keys, []int{2, 1, 0})))
+	require.NoError(t, err)
+	assert.True(t, ctx1.Point().Equal(ctx2.Point()))
+
+	invalid := []string{
+		// Not on the curve.
+		"020000000000000000000000000000000000000000000000000000000000000005",
+		// Larger than the field size.
+		"02FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30",
+		// Invalid prefix.
+		"04F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+	}
+	for _, key := range invalid {
+		_, err := ParsePublicKey(fromHex(t, key))
+		assert.Error(t, err, key)
+	}
+}
+
+func TestTweakVectors(t *testing.T) {
+	sk := new(curve.Secp256k1Scalar)
+	require.NoError(t, sk.UnmarshalBinary(fromHex(t, "7FB9E0E687ADA1EEBF7ECFE2F21E73EBDB51A7D450948DFE8D76D7F2D1007671")))
+	keys := []string{
+		"03935F972DA013F80AE011890FA89B67A27B7BE6CCB24D3274D18B2D4067F261A9",
+		"02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+		"02DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+	}
+	secnonce := fromHex(t, "508B81A611F100A6B2B6B29656590898AF488BCF2E1F55CF22E5CFB84421FE61FA27FD49B1D50085B481285E1CA205D55C82CC1B31FF5CD54A489829355901F7")
+	pubnonces := []string{
+		"0337C87821AFD50A8644D820A8F3E02E499C931865C2360FB43D0A0D20DAFE07EA0287BF891D2A6DEAEBADC909352AA9405D1428C15F4B75F04DAE642A95C2548480",
+		"0279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F817980279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798",
+		"032DE2662628C90B03F5E720284EB52FF7D71F4284F627B68A853D78C78E1FFE9303E4C5524E83FFE1493B9077CF1CA6BEB2090C93D930321071AD40B2F44E599046",
+	}
+	aggnonce, err := parseAggregateNonce(fromHex(t, "028465FCF0BBDBCF443AABCCE533D42B4B5A10966AC09A49655E8C42DAAB8FCD61037496A3CC86926D452CAFCFD55D25972CA1675D549310DE296BFF42F72EEEA8C9"))
+	require.NoError(t, err)
+	m := fromHex(t, "F95466D086770E689964664219266FE5ED215C92AE20BAB5C9D79ADDDDF3C0CF")
+	tweak := fromHex(t, "E8F791FF9225A2AF0102AFFF4A9A723D9612A682A25EBE79802B263CDFCD83BB")
+
+	nonces := make([]PublicNonce, 0, len(pubnonces))
+	for _, n := range pubnonces {
+		nonce, err := ParsePublicNonce(fromHex(t, n))
+		require.NoError(t, err)
+		nonces = append(nonces, nonce)
+	}
+	assert.Equal(t, aggnonce.Bytes(), nonceAgg(nonces).Bytes())
+
+	tests := []struct {
+		xOnly    bool
+		expected string
+	}{
+		{true, "E28A5C66E61E178C2BA19DB77B6CF9F7E2F0F56C17918CD13135E60CC848FE91"},
+		{false, "38B0767798252F21BF5702C48028B095428320F73A4B14DB1E25DE58543D2D2D"},
+	}
+	for _, tt := range tests {
+		ctx, err := KeyAgg(parseKeys(t, keys, []int{1, 2, 0}))
+		require.NoError(t, err)
+		ctx, err = ctx.ApplyTweak(tweak, tt.xOnly)
+		require.NoError(t, err)
+
+		k1, k2 := new(curve.Secp256k1Scalar), new(curve.Secp256k1Scalar)
+		require.NoError(t, k1.UnmarshalBinary(secnonce[:32]))
+		require.NoError(t, k2.UnmarshalBinary(secnonce[32:]))
+		s := newSession(ctx, aggnonce, m)
+		psig, err := s.sign(&secretNonce{k1: k1, k2: k2, pk: fromHex(t, keys[0])}, sk)
+		require.NoError(t, err)
+		psigBytes, _ := psig.MarshalBinary()
+		assert.Equal(t, fromHex(t, tt.expected), psigBytes, "x-only %v", tt.xOnly)
+
+		P := parseKeys(t, keys, []int{0})[0]
+		assert.True(t, s.verifyPartial(psig, nonces[0], P))
+		assert.False(t, s.verifyPartial(psig, nonces[1], P))
+	}
+}
+
+func TestNonceGen(t *testing.T) {
+	sk := append(make([]byte, 31), 1)
+	pk := encodePoint(parseKeys(t, []string{"0279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798"}, []int{0})[0])
+
+	// The optional inputs make the nonces unique, even with the same randomness.
+	rand := make([]byte, 32)
+	sec1, pub1, err := nonceGen(bytes.NewReader(rand), sk, pk, nil, nil, nil)
+	require.NoError(t, err)
+	_, pub2, err := nonceGen(bytes.NewReader(rand), sk, pk, nil, []byte{}, nil)
+	require.NoError(t, err)
+	_, pub3, err := nonceGen(bytes.NewReader(rand), sk, pk, nil, nil, []byte{1})
+	require.NoError(t, err)
+	assert.NotEqual(t, pub1.Bytes(), pub2.Bytes())
+	assert.NotEqual(t, pub1.Bytes(), pub3.Bytes())
+	assert.True(t, sec1.k1.ActOnBase().Equal(pub1.R1))
+	assert.True(t, sec1.k2.ActOnBase().Equal(pub1.R2))
+
+	parsed, err := ParsePublicNonce(pub1.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, pub1.Bytes(), parsed.Bytes())
+	_, err = ParsePublicNonce(make([]byte, PublicNonceLength))
+	assert.Error(t, err, "the nonce of a signer can't be the identity")
+}
+
+func parseScalar(t *testing.T, s string) (*curve.Secp256k1Scalar, error) {
+	x := new(curve.Secp256k1Scalar)
+	return x, x.UnmarshalBinary(fromHex(t, s))
+}
+
+func parseNonces(t *testing.T, nonces []string, indices []int) []PublicNonce {
+	parsed := make([]PublicNonce, 0, len(indices))
+	for _, i := range indices {
+		n, err := ParsePublicNonce(fromHex(t, nonces[i]))
+		require.NoError(t, err)
+		parsed = append(parsed, n)
+	}
+	return parsed
+}
+
+func TestNonceGenVectors(t *testing.T) {
+	tests := []struct {
+		sk, pk, aggpk, msg, extra []byte
+		expected                  string
+	}{
+		{
+			sk:       bytes.Repeat([]byte{0x02}, 32),
+			pk:       fromHex(t, "024D4B6CD1361032CA9BD2AEB9D900AA4D45D9EAD80AC9423374C451A7254D0766"),
+			aggpk:    bytes.Repeat([]byte{0x07}, 32),
+			msg:      bytes.Repeat([]byte{0x01}, 32),
+			extra:    bytes.Repeat([]byte{0x08}, 32),
+			expected: "227243DCB40EF2A13A981DB188FA433717B506BDFA14B1AE47D5DC027C9C3B9EF2370B2AD206E724243215137C86365699361126991E6FEC816845F837BDDAC3",
+		},
+		{
+			sk:       bytes.Repeat([]byte{0x02}, 32),
+			pk:       fromHex(t, "024D4B6CD1361032CA9BD2AEB9D900AA4D45D9EAD80AC9423374C451A7254D0766"),
+			aggpk:    bytes.Repeat([]byte{0x07}, 32),
+			msg:      []byte{},
+			extra:    bytes.Repeat([]byte{0x08}, 32),
+			expected: "CD0F47FE471D6788FF3243F47345EA0A179AEF69476BE8348322EF39C2723318870C2065AFB52DEDF02BF4FDBF6D2F442E608692F50C2374C08FFFE57042A61C",
+		},
+		{
+			sk:       bytes.Repeat([]byte{0x02}, 32),
+			pk:       fromHex(t, "024D4B6CD1361032CA9BD2AEB9D900AA4D45D9EAD80AC9423374C451A7254D0766"),
+			aggpk:    bytes.Repeat([]byte{0x07}, 32),
+			msg:      bytes.Repeat([]byte{0x26}, 38),
+			extra:    bytes.Repeat([]byte{0x08}, 32),
+			expected: "011F8BC60EF061DEEF4D72A0A87200D9994B3F0CD9867910085C38D5366E3E6B9FF03BC0124E56B24069E91EC3F162378983F194E8BD0ED89BE3059649EAE262",
+		},
+		{
+			pk:       fromHex(t, "02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9"),
+			expected: "890E83616A3BC4640AB9B6374F21C81FF89CDDDBAFAA7475AE2A102A92E3EDB29FD7E874E23342813A60D9646948242646B7951CA046B4B36D7D6078506D3C94",
+		},
+	}
+	for i, tt := range tests {
+		secnonce, pubnonce, err := nonceGen(bytes.NewReader(make([]byte, 32)), tt.sk, tt.pk, tt.aggpk, tt.msg, tt.extra)
+		require.NoError(t, err)
+		k1, _ := secnonce.k1.MarshalBinary()
+		k2, _ := secnonce.k2.MarshalBinary()
+		assert.Equal(t, fromHex(t, tt.expected), append(k1, k2...), "case %d", i)
+		assert.Equal(t, tt.pk, secnonce.pk, "case %d", i)
+		assert.True(t, secnonce.k1.ActOnBase().Equal(pubnonce.R1), "case %d", i)
+		assert.True(t, secnonce.k2.ActOnBase().Equal(pubnonce.R2), "case %d", i)
+	}
+}
+
+func TestNonceAggVectors(t *testing.T) {
+	pnonces := []string{
+		"020151C80F435648DF67A22B749CD798CE54E0321D034B92B709B567D60A42E66603BA47FBC1834437B3212E89A84D8425E7BF12E0245D98262268EBDCB385D50641",
+		"03FF406FFD8ADB9CD29877E4985014F66A59F6CD01C0E88CAA8E5F3166B1F676A60248C264CDD57D3C24D79990B0F865674EB62A0F9018277A95011B41BFC193B833",
+		"020151C80F435648DF67A22B749CD798CE54E0321D034B92B709B567D60A42E6660279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798",
+		"03FF406FFD8ADB9CD29877E4985014F66A59F6CD01C0E88CAA8E5F3166B1F676A60379BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798",
+	}
+	tests := []struct {
+		indices  []int
+		expected string
+	}{
+		{[]int{0, 1}, "035FE1873B4F2967F52FEA4A06AD5A8ECCBE9D0FD73068012C894E2E87CCB5804B024725377345BDE0E9C33AF3C43C0A29A9249F2F2956FA8CFEB55C8573D0262DC8"},
+		// The second points sum to the identity, which is encoded as 33 zero bytes.
+		{[]int{2, 3}, "035FE1873B4F2967F52FEA4A06AD5A8ECCBE9D0FD73068012C894E2E87CCB5804B000000000000000000000000000000000000000000000000000000000000000000"},
+	}
+	for _, tt := range tests {
+		aggnonce := nonceAgg(parseNonces(t, pnonces, tt.indices))
+		assert.Equal(t, fromHex(t, tt.expected), aggnonce.Bytes(), "indices %v", tt.indices)
+		parsed, err := parseAggregateNonce(aggnonce.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, aggnonce.Bytes(), parsed.Bytes())
+	}
+
+	invalid := []string{
+		// Wrong tag in the first half.
+		"04FF406FFD8ADB9CD29877E4985014F66A59F6CD01C0E88CAA8E5F3166B1F676A60248C264CDD57D3C24D79990B0F865674EB62A0F9018277A95011B41BFC193B833",
+		// The second half is not the x coordinate of a point.
+		"03FF406FFD8ADB9CD29877E4985014F66A59F6CD01C0E88CAA8E5F3166B1F676A60248C264CDD57D3C24D79990B0F865674EB62A0F9018277A95011B41BFC193B831",
+		// The second half is larger than the field size.
+		"03FF406FFD8ADB9CD29877E4985014F66A59F6CD01C0E88CAA8E5F3166B1F676A602FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30",
+	}
+	for _, nonce := range invalid {
+		_, err := ParsePublicNonce(fromHex(t, nonce))
+		assert.Error(t, err, nonce)
+	}
+}
+
+func TestSignVerifyVectors(t *testing.T) {
+	sk, err := parseScalar(t, "7FB9E0E687ADA1EEBF7ECFE2F21E73EBDB51A7D450948DFE8D76D7F2D1007671")
+	require.NoError(t, err)
+	keys := []string{
+		"03935F972DA013F80AE011890FA89B67A27B7BE6CCB24D3274D18B2D4067F261A9",
+		"02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+		"02DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA661",
+	}
+	secnonces := []string{
+		"508B81A611F100A6B2B6B29656590898AF488BCF2E1F55CF22E5CFB84421FE61FA27FD49B1D50085B481285E1CA205D55C82CC1B31FF5CD54A489829355901F7",
+		// A nonce which was already used, and erased.
+		"00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	}
+	pnonces := []string{
+		"0337C87821AFD50A8644D820A8F3E02E499C931865C2360FB43D0A0D20DAFE07EA0287BF891D2A6DEAEBADC909352AA9405D1428C15F4B75F04DAE642A95C2548480",
+		"0279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F817980279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798",
+		"032DE2662628C90B03F5E720284EB52FF7D71F4284F627B68A853D78C78E1FFE9303E4C5524E83FFE1493B9077CF1CA6BEB2090C93D930321071AD40B2F44E599046",
+		"0237C87821AFD50A8644D820A8F3E02E499C931865C2360FB43D0A0D20DAFE07EA0387BF891D2A6DEAEBADC909352AA9405D1428C15F4B75F04DAE642A95C2548480",
+	}
+	aggnonces := []string{
+		"028465FCF0BBDBCF443AABCCE533D42B4B5A10966AC09A49655E8C42DAAB8FCD61037496A3CC86926D452CAFCFD55D25972CA1675D549310DE296BFF42F72EEEA8C9",
+		"000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	}
+	m := fromHex(t, "F95466D086770E689964664219266FE5ED215C92AE20BAB5C9D79ADDDDF3C0CF")
+
+	newSecnonce := func(i int) *secretNonce {
+		data := fromHex(t, secnonces[i])
+		k1, k2 := new(curve.Secp256k1Scalar), new(curve.Secp256k1Scalar)
+		require.NoError(t, k1.UnmarshalBinary(data[:32]))
+		require.NoError(t, k2.UnmarshalBinary(data[32:]))
+		return &secretNonce{k1: k1, k2: k2, pk: fromHex(t, keys[0])}
+	}
+
+	tests := []struct {
+		keyIndices, nonceIndices []int
+		aggnonceIndex            int
+		signerIndex              int
+		expected                 string
+	}{
+		{[]int{0, 1, 2}, []int{0, 1, 2}, 0, 0, "012ABBCB52B3016AC03AD82395A1A415C48B93DEF78718E62A7A90052FE224FB"},
+		{[]int{1, 0, 2}, []int{1, 0, 2}, 0, 1, "9FF2F7AAA856150CC8819254218D3ADEEB0535269051897724F9DB3789513A52"},
+		{[]int{1, 2, 0}, []int{1, 2, 0}, 0, 2, "FA23C359F6FAC4E7796BB93BC9F0532A95468C539BA20FF86D7C76ED92227900"},
+		// Both halves of the aggregate nonce are the identity.
+		{[]int{0, 1}, []int{0, 3}, 1, 0, "AE386064B26105404798F75DE2EB9AF5EDA5387B064B83D049CB7C5E08879531"},
+	}
+	for i, tt := range tests {
+		points := parseKeys(t, keys, tt.keyIndices)
+		ctx, err := KeyAgg(points)
+		require.NoError(t, err)
+		nonces := parseNonces(t, pnonces, tt.nonceIndices)
+		aggnonce, err := parseAggregateNonce(fromHex(t, aggnonces[tt.aggnonceIndex]))
+		require.NoError(t, err)
+		assert.Equal(t, aggnonce.Bytes(), nonceAgg(nonces).Bytes(), "case %d", i)
+
+		s := newSession(ctx, aggnonce, m)
+		psig, err := s.sign(newSecnonce(0), sk)
+		require.NoError(t, err)
+		psigBytes, _ := psig.MarshalBinary()
+		assert.Equal(t, fromHex(t, tt.expected), psigBytes, "case %d", i)
+		assert.True(t, s.verifyPartial(psig, nonces[tt.signerIndex], points[tt.signerIndex]), "case %d", i)
+	}
+
+	aggnonce, err := parseAggregateNonce(fromHex(t, aggnonces[0]))
+	require.NoError(t, err)
+
+	// The signer's public key must be one of the aggregated keys.
+	ctx, err := KeyAgg(parseKeys(t, keys, []int{1, 2}))
+	require.NoError(t, err)
+	_, err = newSession(ctx, aggnonce, m).sign(newSecnonce(0), sk)
+	assert.Error(t, err)
+
+	// The secret nonce must not have been erased.
+	ctx, err = KeyAgg(parseKeys(t, keys, []int{0, 1, 2}))
+	require.NoError(t, err)
+	s := newSession(ctx, aggnonce, m)
+	_, err = s.sign(newSecnonce(1), sk)
+	assert.Error(t, err)
+
+	// The aggregate nonce must be a valid encoding.
+	invalidAggnonces := []string{
+		// Wrong tag in the first half.
+		"048465FCF0BBDBCF443AABCCE533D42B4B5A10966AC09A49655E8C42DAAB8FCD61037496A3CC86926D452CAFCFD55D25972CA1675D549310DE296BFF42F72EEEA8C9",
+		// The second half is not the x coordinate of a point.
+		"028465FCF0BBDBCF443AABCCE533D42B4B5A10966AC09A49655E8C42DAAB8FCD61020000000000000000000000000000000000000000000000000000000000000009",
+		// The second half is larger than the field size.
+		"028465FCF0BBDBCF443AABCCE533D42B4B5A10966AC09A49655E8C42DAAB8FCD6102FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30",
+	}
+	for _, n := range invalidAggnonces {
+		_, err := parseAggregateNonce(fromHex(t, n))
+		assert.Error(t, err, n)
+	}
+
+	// Invalid public keys and nonces of the other signers are rejected.
+	_, err = ParsePublicKey(fromHex(t, "020000000000000000000000000000000000000000000000000000000000000007"))
+	assert.Error(t, err)
+	_, err = ParsePublicNonce(fromHex(t, "020000000000000000000000000000000000000000000000000000000000000009"))
+	assert.Error(t, err)
+
+	nonces := parseNonces(t, pnonces, []int{0, 1, 2})
+	points := parseKeys(t, keys, []int{0, 1, 2})
+	failures := []struct {
+		sig         string
+		signerIndex int
+	}{
+		// The negation of a valid partial signature.
+		{"97AC833ADCB1AFA42EBF9E0725616F3C9A0D5B614F6FE283CEAAA37A8FFAF406", 0},
+		// A valid partial signature, checked against the wrong signer.
+		{"68537CC5234E505BD14061F8DA9E90C220A181855FD8BDB7F127BB12403B4D3B", 1},
+	}
+	for _, tt := range failures {
+		psig, err := parseScalar(t, tt.sig)
+		require.NoError(t, err)
+		assert.False(t, s.verifyPartial(psig, nonces[tt.signerIndex], points[tt.signerIndex]), tt.sig)
+	}
+	_, err = parseScalar(t, "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141")
+	assert.Error(t, err, "the partial signature exceeds the group size")
+}
+
+func TestSigAggVectors(t *testing.T) {
+	keys := []string{
+		"03935F972DA013F80AE011890FA89B67A27B7BE6CCB24D3274D18B2D4067F261A9",
+		"02D2DC6F5DF7C56ACF38C7FA0AE7A759AE30E19B37359DFDE015872324C7EF6E05",
+		"03C7FB101D97FF930ACD0C6760852EF64E69083DE0B06AC6335724754BB4B0522C",
+		"02352433B21E7E05D3B452B81CAE566E06D2E003ECE16D1074AABA4289E0E3D581",
+	}
+	pnonces := []string{
+		"036E5EE6E28824029FEA3E8A9DDD2C8483F5AF98F7177C3AF3CB6F47CAF8D94AE902DBA67E4A1F3680826172DA15AFB1A8CA85C7C5CC88900905C8DC8C328511B53E",
+		"03E4F798DA48A76EEC1C9CC5AB7A880FFBA201A5F064E627EC9CB0031D1D58FC5103E06180315C5A522B7EC7C08B69DCD721C313C940819296D0A7AB8E8795AC1F00",
+		"02C0068FD25523A31578B8077F24F78F5BD5F2422AFF47C1FADA0F36B3CEB6C7D202098A55D1736AA5FCC21CF0729CCE852575C06C081125144763C2C4C4A05C09B6",
+		"031F5C87DCFBFCF330DEE4311D85E8F1DEA01D87A6F1C14CDFC7E4F1D8C441CFA40277BF176E9F747C34F81B0D9F072B1B404A86F402C2D86CF9EA9E9C69876EA3B9",
+		"023F7042046E0397822C4144A17F8B63D78748696A46C3B9F0A901D296EC3406C302022B0B464292CF9751D699F10980AC764E6F671EFCA15069BBE62B0D1C62522A",
+	}
+	tweaks := []string{
+		"B511DA492182A91B0FFB9A98020D55F260AE86D7ECBD0399C7383D59A5F2AF7C",
+		"A815FE049EE3C5AAB66310477FBC8BCCCAC2F3395F59F921C364ACD78A2F48DC",
+		"75448A87274B056468B977BE06EB1E9F657577B7320B0A3376EA51FD420D18A8",
+	}
+	psigs := []string{
+		"B15D2CD3C3D22B04DAE438CE653F6B4ECF042F42CFDED7C41B64AAF9B4AF53FB",
+		"6193D6AC61B354E9105BBDC8937A3454A6D705B6D57322A5A472A02CE99FCB64",
+		"9A87D3B79EC67228CB97878B76049B15DBD05B8158D17B5B9114D3C226887505",
+		"66F82EA90923689B855D36C6B7E032FB9970301481B99E01CDB4D6AC7C347A15",
+		"4F5AEE41510848A6447DCD1BBC78457EF69024944C87F40250D3EF2C25D33EFE",
+		"DDEF427BBB847CC027BEFF4EDB01038148917832253EBC355FC33F4A8E2FCCE4",
+		"97B890A26C981DA8102D3BC294159D171D72810FDF7C6A691DEF02F0F7AF3FDC",
+		"53FA9E08BA5243CBCB0D797C5EE83BC6728E539EB76C2D0BF0F971EE4E909971",
+	}
+	m := fromHex(t, "599C67EA410D005B9DA90817CF03ED3B1C868E4DA4EDF00A5880B0082C237869")
+
+	tests := []struct {
+		aggnonce                               string
+		nonceIndices, keyIndices, tweakIndices []int
+		xOnly                                  []bool
+		psigIndices                            []int
+		expected                               string
+	}{
+		{
+			aggnonce:     "0341432722C5CD0268D829C702CF0D1CBCE57033EED201FD335191385227C3210C03D377F2D258B64AADC0E16F26462323D701D286046A2EA93365656AFD9875982B",
+			nonceIndices: []int{0, 1},
+			keyIndices:   []int{0, 1},
+			psigIndices:  []int{0, 1},
+			expected:     "041DA22223CE65C92C9A0D6C2CAC828AAF1EEE56304FEC371DDF91EBB2B9EF0912F1038025857FEDEB3FF696F8B99FA4BB2C5812F6095A2E0004EC99CE18DE1E",
+		},
+		{
+			aggnonce:     "0224AFD36C902084058B51B5D36676BBA4DC97C775873768E58822F87FE437D792028CB15929099EEE2F5DAE404CD39357591BA32E9AF4E162B8D3E7CB5EFE31CB20",
+			nonceIndices: []int{0, 2},
+			keyIndices:   []int{0, 2},
+			psigIndices:  []int{2, 3},
+			expected:     "1069B67EC3D2F3C7C08291ACCB17A9C9B8F2819A52EB5DF8726E17E7D6B52E9F01800260A7E9DAC450F4BE522DE4CE12BA91AEAF2B4279219EF74BE1D286ADD9",
+		},
+		{
+			aggnonce:     "0208C5C438C710F4F96A61E9FF3C37758814B8C3AE12BFEA0ED2C87FF6954FF186020B1816EA104B4FCA2D304D733E0E19CEAD51303FF6420BFD222335CAA402916D",
+			nonceIndices: []int{0, 3},
+			keyIndices:   []int{0, 2},
+			tweakIndices: []int{0},
+			xOnly:        []bool{false},
+			psigIndices:  []int{4, 5},
+			expected:     "5C558E1DCADE86DA0B2F02626A512E30A22CF5255CAEA7EE32C38E9A71A0E9148BA6C0E6EC7683B64220F0298696F1B878CD47B107B81F7188812D593971E0CC",
+		},
+		{
+			aggnonce:     "02B5AD07AFCD99B6D92CB433FBD2A28FDEB98EAE2EB09B6014EF0F8197CD58403302E8616910F9293CF692C49F351DB86B25E352901F0E237BAFDA11F1C1CEF29FFD",
+			nonceIndices: []int{0, 4},
+			keyIndices:   []int{0, 3},
+			tweakIndices: []int{0, 1, 2},
+			xOnly:        []bool{true, false, true},
+			psigIndices:  []int{6, 7},
+			expected:     "839B08820B681DBA8DAF4CC7B104E8F2638F9388F8D7A555DC17B6E6971D7426CE07BF6AB01F1DB50E4E33719295F4094572B79868E440FB3DEFD3FAC1DB589E",
+		},
+	}
+	for i, tt := range tests {
+		ctx, err := KeyAgg(parseKeys(t, keys, tt.keyIndices))
+		require.NoError(t, err)
+		for j, k := range tt.tweakIndices {
+			ctx, err = ctx.ApplyTweak(fromHex(t, tweaks[k]), tt.xOnly[j])
+			require.NoError(t, err)
+		}
+		aggnonce, err := parseAggregateNonce(fromHex(t, tt.aggnonce))
+		require.NoError(t, err)
+		assert.Equal(t, aggnonce.Bytes(), nonceAgg(parseNonces(t, pnonces, tt.nonceIndices)).Bytes(), "case %d", i)
+
+		partials := make([]curve.Scalar, 0, len(tt.psigIndices))
+		for _, k := range tt.psigIndices {
+			psig, err := parseScalar(t, psigs[k])
+			require.NoError(t, err)
+			partials = append(partials, psig)
+		}
+		sig := newSession(ctx, aggnonce, m).aggregate(partials)
+		assert.Equal(t, fromHex(t, tt.expected), []byte(sig), "case %d", i)
+		assert.True(t, ctx.PublicKey().Verify(sig, m), "case %d", i)
+	}
+
+	_, err := parseScalar(t, "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141")
+	assert.Error(t, err, "the partial signature exceeds the group size")
+}
@@ -0,0 +1,60 @@
+package sign
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// This round generates our nonces, and broadcasts the corresponding public nonce,
+// following the NonceGen algorithm of BIP-327:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki#nonce-generation
+type round1 struct {
+	*round.Helper
+	// ctx is the aggregation of the public keys of all signers, including tweaks.
+	ctx *KeyAggContext
+	// M is the message we're signing.
+	M []byte
+	// sk is our secret key.
+	sk *curve.Secp256k1Scalar
+	// publicKeys contains the individual public key of each signer.
+	publicKeys map[party.ID]*curve.Secp256k1Point
+}
+
+// VerifyMessage implements round.Round.
+func (round1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round1) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round.
+func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	// All the optional inputs of NonceGen are provided, which makes the nonces unique
+	// even if our randomness is bad. The SSID binds them to this session.
+	skBytes, err := r.sk.MarshalBinary()
+	if err != nil {
+		return r, err
+	}
+	pk := encodePoint(r.publicKeys[r.SelfID()])
+	secnonce, pubnonce, err := nonceGen(r.Rand(), skBytes, pk, r.ctx.PublicKey(), r.M, r.SSID())
+	if err != nil {
+		return r, err
+	}
+
+	err = r.BroadcastMessage(out, &broadcast2{R1: pubnonce.R1, R2: pubnonce.R2})
+	if err != nil {
+		return r, err
+	}
+	return &round2{
+		round1:   r,
+		secnonce: secnonce,
+		nonces:   map[party.ID]PublicNonce{r.SelfID(): pubnonce},
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (round1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round1) Number() round.Number { return 1 }
@@ -0,0 +1,100 @@
+package sign
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// This round aggregates the public nonces of all signers, and computes our partial signature,
+// following the NonceAgg and Sign algorithms of BIP-327:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki#signing
+type round2 struct {
+	*round1
+	// secnonce contains our nonces (k₁, k₂), which are erased once we've signed.
+	secnonce *secretNonce
+	// nonces[i] = (Rᵢ₁, Rᵢ₂) is the public nonce of each signer, ourself included.
+	nonces map[party.ID]PublicNonce
+}
+
+type broadcast2 struct {
+	round.ReliableBroadcastContent
+	// R1 is the first point of the public nonce of the sender of this message.
+	R1 curve.Point
+	// R2 is the second point of the public nonce of the sender of this message.
+	R2 curve.Point
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+func (r *round2) StoreBroadcastMessage(msg round.Message) error {
+	body, ok := msg.Content.(*broadcast2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.R1 == nil || body.R2 == nil {
+		return round.ErrNilFields
+	}
+	R1, ok1 := body.R1.(*curve.Secp256k1Point)
+	R2, ok2 := body.R2.(*curve.Secp256k1Point)
+	if !ok1 || !ok2 {
+		return round.ErrInvalidContent
+	}
+	// BIP-327 requires the points of an individual nonce to be valid points, see cpoint.
+	if R1.IsIdentity() || R2.IsIdentity() {
+		return fmt.Errorf("nonce is the identity point: %w", round.ErrMalformed)
+	}
+
+	r.nonces[msg.From] = PublicNonce{R1: R1, R2: R2}
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (round2) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round2) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round.
+func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
+	nonces := make([]PublicNonce, 0, len(r.nonces))
+	for _, l := range r.PartyIDs() {
+		nonces = append(nonces, r.nonces[l])
+	}
+	s := newSession(r.ctx, nonceAgg(nonces), r.M)
+
+	// This erases our nonces, so that they are never used twice.
+	psig, err := s.sign(r.secnonce, r.sk)
+	if err != nil {
+		return r, err
+	}
+
+	err = r.BroadcastMessage(out, &broadcast3{S: psig})
+	if err != nil {
+		return r, err
+	}
+	return &round3{
+		round2:  r,
+		session: s,
+		psigs:   map[party.ID]curve.Scalar{r.SelfID(): psig},
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (round2) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast2) RoundNumber() round.Number { return 2 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *round2) BroadcastContent() round.BroadcastContent {
+	return &broadcast2{
+		R1: r.Group().NewPoint(),
+		R2: r.Group().NewPoint(),
+	}
+}
+
+// Number implements round.Round.
+func (round2) Number() round.Number { return 2 }
@@ -0,0 +1,82 @@
+package sign
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// This round verifies the partial signatures of the other signers, and aggregates them,
+// following the PartialSigVerify and PartialSigAgg algorithms of BIP-327:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki#partial-signature-verification
+type round3 struct {
+	*round2
+	// session contains the values derived from the aggregated nonce.
+	session *session
+	// psigs[i] = sᵢ is the partial signature of each signer, ourself included.
+	psigs map[party.ID]curve.Scalar
+}
+
+type broadcast3 struct {
+	round.NormalBroadcastContent
+	// S is the partial signature of the sender of this message.
+	S curve.Scalar
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+func (r *round3) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast3)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.S == nil {
+		return round.ErrNilFields
+	}
+
+	if !r.session.verifyPartial(body.S, r.nonces[from], r.publicKeys[from]) {
+		return fmt.Errorf("failed to verify partial signature from %v: %w", from, round.ErrInvalidShare)
+	}
+
+	r.psigs[from] = body.S
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (round3) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round3) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round.
+func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
+	psigs := make([]curve.Scalar, 0, len(r.psigs))
+	for _, l := range r.PartyIDs() {
+		psigs = append(psigs, r.psigs[l])
+	}
+	sig := r.session.aggregate(psigs)
+
+	if !r.ctx.PublicKey().Verify(sig, r.M) {
+		return r.AbortRound(fmt.Errorf("generated signature failed to verify")), nil
+	}
+	return r.ResultRound(sig), nil
+}
+
+// MessageContent implements round.Round.
+func (round3) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast3) RoundNumber() round.Number { return 3 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *round3) BroadcastContent() round.BroadcastContent {
+	return &broadcast3{
+		S: r.Group().NewScalar(),
+	}
+}
+
+// Number implements round.Round.
+func (round3) Number() round.Number { return 3 }
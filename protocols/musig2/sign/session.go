@@ -0,0 +1,237 @@
+package sign
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+)
+
+// PublicNonceLength is the number of bytes in the encoding of a PublicNonce.
+const PublicNonceLength = 2 * PublicKeyLength
+
+// PublicNonce is the pair of points (R₁, R₂) committing to the nonces of a signer,
+// or the aggregate of the public nonces of all signers.
+type PublicNonce struct {
+	R1, R2 *curve.Secp256k1Point
+}
+
+// Bytes returns the 66 byte encoding of the nonce used by BIP-327.
+func (n PublicNonce) Bytes() []byte {
+	return append(encodePoint(n.R1), encodePoint(n.R2)...)
+}
+
+// ParsePublicNonce decodes the nonce of a signer.
+func ParsePublicNonce(data []byte) (PublicNonce, error) {
+	if len(data) != PublicNonceLength {
+		return PublicNonce{}, errors.New("musig2: invalid public nonce length")
+	}
+	R1, err := ParsePublicKey(data[:PublicKeyLength])
+	if err != nil {
+		return PublicNonce{}, err
+	}
+	R2, err := ParsePublicKey(data[PublicKeyLength:])
+	if err != nil {
+		return PublicNonce{}, err
+	}
+	return PublicNonce{R1: R1, R2: R2}, nil
+}
+
+// parseAggregateNonce decodes an aggregated nonce, whose points may be the identity.
+func parseAggregateNonce(data []byte) (PublicNonce, error) {
+	if len(data) != PublicNonceLength {
+		return PublicNonce{}, errors.New("musig2: invalid aggregate nonce length")
+	}
+	R1, err := decodePoint(data[:PublicKeyLength])
+	if err != nil {
+		return PublicNonce{}, err
+	}
+	R2, err := decodePoint(data[PublicKeyLength:])
+	if err != nil {
+		return PublicNonce{}, err
+	}
+	return PublicNonce{R1: R1, R2: R2}, nil
+}
+
+// secretNonce contains the nonces (k₁, k₂) of a signer, and its public key.
+//
+// It must only be used once, and is erased by sign.
+type secretNonce struct {
+	k1, k2 curve.Scalar
+	pk     []byte
+}
+
+// nonceGen generates the nonces of a signer with public key pk, as specified in BIP-327.
+//
+// The optional values, which can be nil, make the nonces more robust against bad randomness:
+// sk is the secret key of the signer, aggpk the x-only aggregated public key, m the message,
+// and extra any additional data. A nil m is different from an empty message.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki#nonce-generation
+func nonceGen(rand io.Reader, sk, pk, aggpk, m, extra []byte) (*secretNonce, PublicNonce, error) {
+	randBytes := make([]byte, 32)
+	if _, err := io.ReadFull(rand, randBytes); err != nil {
+		return nil, PublicNonce{}, err
+	}
+	if sk != nil {
+		aux := taproot.TaggedHash("MuSig/aux", randBytes)
+		for i := range randBytes {
+			randBytes[i] = sk[i] ^ aux[i]
+		}
+	}
+
+	var mPrefixed []byte
+	if m == nil {
+		mPrefixed = []byte{0}
+	} else {
+		mPrefixed = make([]byte, 9, 9+len(m))
+		mPrefixed[0] = 1
+		binary.BigEndian.PutUint64(mPrefixed[1:], uint64(len(m)))
+		mPrefixed = append(mPrefixed, m...)
+	}
+	extraLen := make([]byte, 4)
+	binary.BigEndian.PutUint32(extraLen, uint32(len(extra)))
+
+	k := make([]curve.Scalar, 2)
+	for i := range k {
+		k[i] = hashToScalar(taproot.TaggedHash("MuSig/nonce",
+			randBytes,
+			[]byte{byte(len(pk))}, pk,
+			[]byte{byte(len(aggpk))}, aggpk,
+			mPrefixed,
+			extraLen, extra,
+			[]byte{byte(i)},
+		))
+		if k[i].IsZero() {
+			return nil, PublicNonce{}, errors.New("musig2: nonce is zero")
+		}
+	}
+	secnonce := &secretNonce{k1: k[0], k2: k[1], pk: pk}
+	pubnonce := PublicNonce{
+		R1: k[0].ActOnBase().(*curve.Secp256k1Point),
+		R2: k[1].ActOnBase().(*curve.Secp256k1Point),
+	}
+	return secnonce, pubnonce, nil
+}
+
+// nonceAgg sums the public nonces of all the signers.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki#nonce-aggregation
+func nonceAgg(nonces []PublicNonce) PublicNonce {
+	R1 := curve.Secp256k1{}.NewPoint()
+	R2 := curve.Secp256k1{}.NewPoint()
+	for _, n := range nonces {
+		R1 = R1.Add(n.R1)
+		R2 = R2.Add(n.R2)
+	}
+	return PublicNonce{R1: R1.(*curve.Secp256k1Point), R2: R2.(*curve.Secp256k1Point)}
+}
+
+// session contains the values shared by all signers, once the nonces have been aggregated.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki#session-context
+type session struct {
+	ctx *KeyAggContext
+	m   []byte
+	// b is the coefficient of the second nonces.
+	b curve.Scalar
+	// R is the final nonce, and e the challenge of the signature.
+	R *curve.Secp256k1Point
+	e curve.Scalar
+}
+
+// newSession computes the values of GetSessionValues in BIP-327.
+func newSession(ctx *KeyAggContext, aggnonce PublicNonce, m []byte) *session {
+	b := hashToScalar(taproot.TaggedHash("MuSig/noncecoef", aggnonce.Bytes(), ctx.q.XBytes(), m))
+	R := aggnonce.R1.Add(b.Act(aggnonce.R2))
+	if R.IsIdentity() {
+		R = curve.Secp256k1{}.NewBasePoint()
+	}
+	RSecp := R.(*curve.Secp256k1Point)
+	e := hashToScalar(taproot.TaggedHash("BIP0340/challenge", RSecp.XBytes(), ctx.q.XBytes(), m))
+	return &session{ctx: ctx, m: m, b: b, R: RSecp, e: e}
+}
+
+// g returns 1 if Q has an even y coordinate, and -1 otherwise.
+func (s *session) g() curve.Scalar {
+	g := hashToScalar([]byte{1})
+	if !s.ctx.q.HasEvenY() {
+		g.Negate()
+	}
+	return g
+}
+
+// sign returns the partial signature s = k₁ + b⋅k₂ + e⋅a⋅d of a signer with secret key sk,
+// and erases secnonce, so that it can't be reused.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki#signing
+func (s *session) sign(secnonce *secretNonce, sk curve.Scalar) (curve.Scalar, error) {
+	group := curve.Secp256k1{}
+	if secnonce.k1 == nil || secnonce.k2 == nil {
+		return nil, errors.New("musig2: secret nonce was already used")
+	}
+	k1 := group.NewScalar().Set(secnonce.k1)
+	k2 := group.NewScalar().Set(secnonce.k2)
+	secnonce.k1, secnonce.k2 = nil, nil
+	if k1.IsZero() || k2.IsZero() {
+		return nil, errors.New("musig2: invalid secret nonce")
+	}
+	if !s.R.HasEvenY() {
+		k1.Negate()
+		k2.Negate()
+	}
+
+	if sk.IsZero() {
+		return nil, errors.New("musig2: invalid secret key")
+	}
+	P := sk.ActOnBase().(*curve.Secp256k1Point)
+	pk := encodePoint(P)
+	if string(pk) != string(secnonce.pk) {
+		return nil, errors.New("musig2: public key doesn't match the secret nonce")
+	}
+	if !s.ctx.contains(pk) {
+		return nil, errors.New("musig2: public key is not one of the signers")
+	}
+	a := s.ctx.coefficient(pk)
+	d := s.g().Mul(s.ctx.gacc).Mul(sk)
+
+	psig := a.Mul(d).Mul(s.e)
+	psig.Add(k1)
+	psig.Add(k2.Mul(s.b))
+	return psig, nil
+}
+
+// verifyPartial checks the partial signature of the signer with public key P and public nonce pubnonce.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki#partial-signature-verification
+func (s *session) verifyPartial(psig curve.Scalar, pubnonce PublicNonce, P *curve.Secp256k1Point) bool {
+	pk := encodePoint(P)
+	if !s.ctx.contains(pk) {
+		return false
+	}
+	Re := pubnonce.R1.Add(s.b.Act(pubnonce.R2))
+	if !s.R.HasEvenY() {
+		Re = Re.Negate()
+	}
+	g := s.g().Mul(s.ctx.gacc)
+	expected := Re.Add(s.e.Act(s.ctx.coefficient(pk).Mul(g).Act(P)))
+	return psig.ActOnBase().Equal(expected)
+}
+
+// aggregate returns the BIP-340 signature (R, ∑ᵢ sᵢ + e⋅g⋅tacc) made of the partial signatures.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki#partial-signature-aggregation
+func (s *session) aggregate(psigs []curve.Scalar) taproot.Signature {
+	z := curve.Secp256k1{}.NewScalar()
+	for _, psig := range psigs {
+		z.Add(psig)
+	}
+	z.Add(s.g().Mul(s.e).Mul(s.ctx.tacc))
+	zBytes, _ := z.MarshalBinary()
+	sig := make([]byte, 0, taproot.SignatureLen)
+	sig = append(sig, s.R.XBytes()...)
+	sig = append(sig, zBytes...)
+	return sig
+}
@@ -0,0 +1,53 @@
+package sign
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+const (
+	// MuSig2 n-of-n signing.
+	protocolID = "musig2/sign"
+	// This protocol has 3 concrete rounds.
+	protocolRounds round.Number = 3
+)
+
+// StartSign starts a MuSig2 session for the config, signing the message m.
+func StartSign(config *Config, m []byte, rand io.Reader) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if err := config.validate(); err != nil {
+			return nil, fmt.Errorf("sign.StartSign: %w", err)
+		}
+		ctx, err := config.KeyAgg()
+		if err != nil {
+			return nil, fmt.Errorf("sign.StartSign: %w", err)
+		}
+
+		signers := config.Signers()
+		info := round.Info{
+			ProtocolID:       protocolID,
+			FinalRoundNumber: protocolRounds,
+			SelfID:           config.ID,
+			PartyIDs:         signers,
+			// All the signers are needed.
+			Threshold: len(signers) - 1,
+			Group:     curve.Secp256k1{},
+			Rand:      rand,
+		}
+		helper, err := round.NewSession(info, sessionID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("sign.StartSign: %w", err)
+		}
+		return &round1{
+			Helper:     helper,
+			ctx:        ctx,
+			M:          m,
+			sk:         config.PrivateKey,
+			publicKeys: config.PublicKeys,
+		}, nil
+	}
+}
@@ -0,0 +1,155 @@
+package sign
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+)
+
+func generateConfigs(partyIDs []party.ID) map[party.ID]*Config {
+	group := curve.Secp256k1{}
+	privateKeys := make(map[party.ID]*curve.Secp256k1Scalar, len(partyIDs))
+	publicKeys := make(map[party.ID]*curve.Secp256k1Point, len(partyIDs))
+	for _, id := range partyIDs {
+		privateKeys[id] = sample.Scalar(rand.Reader, group).(*curve.Secp256k1Scalar)
+		publicKeys[id] = privateKeys[id].ActOnBase().(*curve.Secp256k1Point)
+	}
+	configs := make(map[party.ID]*Config, len(partyIDs))
+	for _, id := range partyIDs {
+		configs[id] = &Config{
+			ID:         id,
+			PrivateKey: privateKeys[id],
+			PublicKeys: publicKeys,
+		}
+	}
+	return configs
+}
+
+func checkOutput(t *testing.T, rounds []round.Session, public taproot.PublicKey, m []byte) {
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		resultRound := r.(*round.Output)
+		require.IsType(t, taproot.Signature{}, resultRound.Result, "expected taproot signature result")
+		signature := resultRound.Result.(taproot.Signature)
+		assert.True(t, public.Verify(signature, m), "expected valid signature")
+	}
+}
+
+func TestSign(t *testing.T) {
+	N := 4
+	partyIDs := test.PartyIDs(N)
+	configs := generateConfigs(partyIDs)
+	// Messages can have any length.
+	steak := []byte{0xDE, 0xAD, 0xBE, 0xEF}
+
+	for _, tweaks := range [][]Tweak{
+		nil,
+		{
+			{Tweak: append(make([]byte, 31), 1), XOnly: false},
+			{Tweak: append(make([]byte, 31), 2), XOnly: true},
+		},
+	} {
+		var publicKey taproot.PublicKey
+		rounds := make([]round.Session, 0, N)
+		for _, id := range partyIDs {
+			config := *configs[id]
+			config.Tweaks = tweaks
+			if publicKey == nil {
+				var err error
+				publicKey, err = config.PublicKey()
+				require.NoError(t, err)
+			}
+			r, err := StartSign(&config, steak, rand.Reader)(nil)
+			require.NoError(t, err, "round creation should not result in an error")
+			rounds = append(rounds, r)
+		}
+
+		for {
+			err, done := test.Rounds(rounds, nil)
+			require.NoError(t, err, "failed to process round")
+			if done {
+				break
+			}
+		}
+
+		checkOutput(t, rounds, publicKey, steak)
+	}
+}
+
+func TestSignInvalidConfig(t *testing.T) {
+	partyIDs := test.PartyIDs(2)
+	configs := generateConfigs(partyIDs)
+
+	config := *configs[partyIDs[0]]
+	config.PrivateKey = configs[partyIDs[1]].PrivateKey
+	_, err := StartSign(&config, nil, rand.Reader)(nil)
+	assert.Error(t, err, "the private key doesn't match the public key")
+
+	config = *configs[partyIDs[0]]
+	config.ID = "unknown"
+	_, err = StartSign(&config, nil, rand.Reader)(nil)
+	assert.Error(t, err, "the party isn't one of the signers")
+}
+
+// tamperRule corrupts the partial signature sent by the party "a".
+type tamperRule struct{}
+
+func (tamperRule) ModifyBefore(round.Session) {}
+
+func (tamperRule) ModifyAfter(round.Session) {}
+
+func (tamperRule) ModifyContent(rNext round.Session, _ party.ID, content round.Content) {
+	if rNext.SelfID() != "a" {
+		return
+	}
+	if c, ok := content.(*broadcast3); ok {
+		c.S = rNext.Group().NewScalar().Set(c.S).Add(hashToScalar([]byte{1}))
+	}
+}
+
+func TestSignInvalidPartialSignature(t *testing.T) {
+	partyIDs := test.PartyIDs(3)
+	configs := generateConfigs(partyIDs)
+
+	rounds := make([]round.Session, 0, len(partyIDs))
+	for _, id := range partyIDs {
+		r, err := StartSign(configs[id], []byte("message"), rand.Reader)(nil)
+		require.NoError(t, err)
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, tamperRule{})
+		if err != nil {
+			assert.True(t, errors.Is(err, round.ErrInvalidShare), "expected an invalid share, got %v", err)
+			return
+		}
+		require.False(t, done, "the protocol should not finish with an invalid partial signature")
+	}
+}
+
+func TestSecretNonceReuse(t *testing.T) {
+	partyIDs := test.PartyIDs(2)
+	configs := generateConfigs(partyIDs)
+	config := configs[partyIDs[0]]
+	ctx, err := config.KeyAgg()
+	require.NoError(t, err)
+
+	pk := encodePoint(config.PublicKeys[config.ID])
+	secnonce, pubnonce, err := nonceGen(rand.Reader, nil, pk, nil, nil, nil)
+	require.NoError(t, err)
+	s := newSession(ctx, pubnonce, []byte("message"))
+	_, err = s.sign(secnonce, config.PrivateKey)
+	require.NoError(t, err)
+	_, err = s.sign(secnonce, config.PrivateKey)
+	assert.Error(t, err, "signing twice with the same nonces should fail")
+}
@@ -0,0 +1,121 @@
+// This example runs cmp.Keygen and cmp.Sign between several libp2p hosts listening on the loopback interface.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+	p2p "github.com/taurusgroup/multi-party-sig/transport/libp2p"
+)
+
+// node is a party with its own libp2p host.
+type node struct {
+	id   party.ID
+	host host.Host
+	ps   *pubsub.PubSub
+}
+
+// run executes the protocol created by create for the party of n, in session.
+func (n *node) run(ctx context.Context, session string, parties party.IDSlice, peers map[party.ID]peer.ID, create protocol.StartFunc) (interface{}, error) {
+	sessionPeers := make(map[party.ID]peer.ID, len(parties))
+	for _, id := range parties {
+		sessionPeers[id] = peers[id]
+	}
+	t, err := p2p.New(ctx, n.host, n.ps, session, n.id, sessionPeers)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+	h, err := protocol.NewMultiHandler(create, []byte(session))
+	if err != nil {
+		return nil, err
+	}
+	return transport.Run(ctx, h, t)
+}
+
+func (n *node) keygenSign(ctx context.Context, ids, signers party.IDSlice, threshold int, peers map[party.ID]peer.ID, message []byte) error {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	r, err := n.run(ctx, "keygen", ids, peers, cmp.Keygen(curve.Secp256k1{}, n.id, ids, threshold, pl))
+	if err != nil {
+		return fmt.Errorf("keygen: %w", err)
+	}
+	config := r.(*cmp.Config)
+	if !signers.Contains(n.id) {
+		return nil
+	}
+
+	r, err = n.run(ctx, "sign", signers, peers, cmp.Sign(config, signers, message, pl))
+	if err != nil {
+		return fmt.Errorf("sign: %w", err)
+	}
+	if !r.(*ecdsa.Signature).Verify(config.PublicPoint(), message) {
+		return errors.New("sign: invalid signature")
+	}
+	return nil
+}
+
+func main() {
+	ids := party.IDSlice{"a", "b", "c"}
+	threshold := 1
+	signers := ids[:threshold+1]
+	message := []byte("hello")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	nodes := make([]*node, 0, len(ids))
+	peers := make(map[party.ID]peer.ID, len(ids))
+	for _, id := range ids {
+		h, err := libp2p.New(libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer h.Close()
+		ps, err := pubsub.NewGossipSub(ctx, h, pubsub.WithFloodPublish(true))
+		if err != nil {
+			log.Fatal(err)
+		}
+		nodes = append(nodes, &node{id: id, host: h, ps: ps})
+		peers[id] = h.ID()
+	}
+
+	// connect all hosts to each other
+	for i, a := range nodes {
+		for _, b := range nodes[i+1:] {
+			if err := a.host.Connect(ctx, peer.AddrInfo{ID: b.host.ID(), Addrs: b.host.Addrs()}); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n *node) {
+			defer wg.Done()
+			if err := n.keygenSign(ctx, ids, signers, threshold, peers, message); err != nil {
+				log.Printf("%s: %v", n.id, err)
+				return
+			}
+			log.Printf("%s: done", n.id)
+		}(n)
+	}
+	wg.Wait()
+}
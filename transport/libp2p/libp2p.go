@@ -0,0 +1,251 @@
+// Package libp2p implements transport.Transport over libp2p.
+//
+// Reliably broadcast messages are published on a gossipsub topic for the session,
+// and all other messages are sent directly to each recipient over a new stream.
+// Each party is identified by the peer.ID of its host, so that the sender of every message is authenticated by libp2p.
+package libp2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	libp2pprotocol "github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+)
+
+const (
+	// maxMessageSize is the largest message accepted over a stream.
+	maxMessageSize = 32 << 20
+	// joinPollInterval is how often New checks whether all parties have joined the topic.
+	joinPollInterval = 50 * time.Millisecond
+)
+
+var _ transport.Transport = (*Transport)(nil)
+
+// Transport is the transport.Transport of a single party in a single session, over a libp2p host.
+type Transport struct {
+	host     host.Host
+	session  string
+	self     party.ID
+	peers    map[party.ID]peer.ID
+	parties  map[peer.ID]party.ID
+	topic    *pubsub.Topic
+	sub      *pubsub.Subscription
+	incoming chan *protocol.Message
+	// done is closed by Close, to stop delivering messages.
+	done   <-chan struct{}
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// New joins session on h, where each party is identified by the peer.ID of its host in peers,
+// which must include self.
+//
+// It blocks until all other parties have joined the session's gossipsub topic on ps,
+// so that no broadcast message is lost, or until ctx is done.
+// This requires ps to be created with pubsub.WithFloodPublish(true),
+// since otherwise messages are only published to the mesh, which may not include every party yet.
+// The hosts should already be connected, or be able to discover each other.
+// Close must be called once the protocol is done.
+func New(ctx context.Context, h host.Host, ps *pubsub.PubSub, session string, self party.ID, peers map[party.ID]peer.ID) (*Transport, error) {
+	if peers[self] != h.ID() {
+		return nil, fmt.Errorf("libp2p: %s is not the peer of %s", h.ID(), self)
+	}
+	parties := make(map[peer.ID]party.ID, len(peers))
+	peersCopy := make(map[party.ID]peer.ID, len(peers))
+	for id, p := range peers {
+		if _, ok := parties[p]; ok {
+			return nil, fmt.Errorf("libp2p: peer %s used by several parties", p)
+		}
+		parties[p] = id
+		peersCopy[id] = p
+	}
+
+	subCtx, cancel := context.WithCancel(context.Background())
+	t := &Transport{
+		host:     h,
+		session:  session,
+		self:     self,
+		peers:    peersCopy,
+		parties:  parties,
+		incoming: make(chan *protocol.Message, 4*len(peers)*len(peers)),
+		done:     subCtx.Done(),
+		cancel:   cancel,
+	}
+	// the stream handler is set before joining the topic, since the other parties start sending once we joined
+	h.SetStreamHandler(protocolID(session), t.handleStream)
+	topic, err := ps.Join(topicName(session))
+	if err != nil {
+		h.RemoveStreamHandler(protocolID(session))
+		cancel()
+		return nil, fmt.Errorf("libp2p: failed to join topic: %w", err)
+	}
+	sub, err := topic.Subscribe()
+	if err != nil {
+		h.RemoveStreamHandler(protocolID(session))
+		cancel()
+		_ = topic.Close()
+		return nil, fmt.Errorf("libp2p: failed to subscribe: %w", err)
+	}
+	t.topic, t.sub = topic, sub
+	go t.readTopic(subCtx)
+
+	if err = t.waitForPeers(ctx); err != nil {
+		t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// Close leaves the session.
+func (t *Transport) Close() {
+	t.once.Do(func() {
+		t.host.RemoveStreamHandler(protocolID(t.session))
+		t.cancel()
+		t.sub.Cancel()
+		_ = t.topic.Close()
+	})
+}
+
+// Send implements transport.Transport.
+func (t *Transport) Send(ctx context.Context, msg *protocol.Message) error {
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if msg.Broadcast {
+		return t.topic.Publish(ctx, data)
+	}
+	for _, id := range t.recipients(msg) {
+		if err = t.sendDirect(ctx, id, data); err != nil {
+			return fmt.Errorf("libp2p: failed to send to %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// Receive implements transport.Transport.
+func (t *Transport) Receive(ctx context.Context) (*protocol.Message, error) {
+	select {
+	case msg := <-t.incoming:
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// recipients returns the parties to which msg should be sent directly.
+func (t *Transport) recipients(msg *protocol.Message) []party.ID {
+	if msg.To != "" {
+		return []party.ID{msg.To}
+	}
+	ids := make([]party.ID, 0, len(t.peers))
+	for id := range t.peers {
+		if id != t.self {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// sendDirect opens a new stream to the host of id, and writes data to it.
+func (t *Transport) sendDirect(ctx context.Context, id party.ID, data []byte) error {
+	p, ok := t.peers[id]
+	if !ok {
+		return errors.New("unknown party")
+	}
+	s, err := t.host.NewStream(ctx, p, protocolID(t.session))
+	if err != nil {
+		return err
+	}
+	if _, err = s.Write(data); err != nil {
+		_ = s.Reset()
+		return err
+	}
+	return s.Close()
+}
+
+// handleStream reads a message sent directly by another party.
+func (t *Transport) handleStream(s network.Stream) {
+	defer s.Close()
+	data, err := io.ReadAll(io.LimitReader(s, maxMessageSize))
+	if err != nil {
+		_ = s.Reset()
+		return
+	}
+	t.deliver(s.Conn().RemotePeer(), data)
+}
+
+// readTopic reads the messages broadcast by the other parties.
+func (t *Transport) readTopic(ctx context.Context) {
+	for {
+		m, err := t.sub.Next(ctx)
+		if err != nil {
+			return
+		}
+		if m.GetFrom() == t.host.ID() {
+			continue
+		}
+		t.deliver(m.GetFrom(), m.Data)
+	}
+}
+
+// deliver queues data as a message, if it was really sent by the party of the peer from.
+func (t *Transport) deliver(from peer.ID, data []byte) {
+	id, ok := t.parties[from]
+	if !ok {
+		return
+	}
+	var msg protocol.Message
+	if err := msg.UnmarshalBinary(data); err != nil || msg.From != id {
+		return
+	}
+	select {
+	case t.incoming <- &msg:
+	case <-t.done:
+	}
+}
+
+// waitForPeers blocks until all other parties are subscribed to the topic.
+func (t *Transport) waitForPeers(ctx context.Context) error {
+	ticker := time.NewTicker(joinPollInterval)
+	defer ticker.Stop()
+	for {
+		joined := make(map[peer.ID]bool)
+		for _, p := range t.topic.ListPeers() {
+			joined[p] = true
+		}
+		missing := 0
+		for id, p := range t.peers {
+			if id != t.self && !joined[p] {
+				missing++
+			}
+		}
+		if missing == 0 {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("libp2p: waiting for %d parties to join: %w", missing, ctx.Err())
+		}
+	}
+}
+
+func topicName(session string) string {
+	return "/multi-party-sig/session/" + session
+}
+
+func protocolID(session string) libp2pprotocol.ID {
+	return libp2pprotocol.ID("/multi-party-sig/1.0.0/" + session)
+}
@@ -0,0 +1,85 @@
+package libp2p_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+	p2p "github.com/taurusgroup/multi-party-sig/transport/libp2p"
+)
+
+// newHosts creates a host listening on the loopback interface for each party, with all hosts connected to each other.
+func newHosts(ctx context.Context, t *testing.T, ids party.IDSlice) (map[party.ID]host.Host, map[party.ID]*pubsub.PubSub, map[party.ID]peer.ID) {
+	hosts := make(map[party.ID]host.Host, len(ids))
+	pubsubs := make(map[party.ID]*pubsub.PubSub, len(ids))
+	peers := make(map[party.ID]peer.ID, len(ids))
+	for _, id := range ids {
+		h, err := libp2p.New(libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = h.Close() })
+		ps, err := pubsub.NewGossipSub(ctx, h, pubsub.WithFloodPublish(true))
+		require.NoError(t, err)
+		hosts[id], pubsubs[id], peers[id] = h, ps, h.ID()
+	}
+	for i, a := range ids {
+		for _, b := range ids[i+1:] {
+			require.NoError(t, hosts[a].Connect(ctx, peer.AddrInfo{ID: peers[b], Addrs: hosts[b].Addrs()}))
+		}
+	}
+	return hosts, pubsubs, peers
+}
+
+func TestLoopback(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	ids := party.IDSlice{"a", "b", "c"}
+	threshold := 1
+	message := []byte("hello")
+	hosts, pubsubs, peers := newHosts(ctx, t, ids)
+
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id party.ID) {
+			defer wg.Done()
+			run := func(session string, create protocol.StartFunc) interface{} {
+				tr, err := p2p.New(ctx, hosts[id], pubsubs[id], session, id, peers)
+				require.NoError(t, err)
+				defer tr.Close()
+				h, err := protocol.NewMultiHandler(create, []byte(session))
+				require.NoError(t, err)
+				result, err := transport.Run(ctx, h, tr)
+				require.NoError(t, err)
+				return result
+			}
+
+			config := run("keygen", frost.Keygen(curve.Secp256k1{}, id, ids, threshold)).(*frost.Config)
+			signature := run("sign", frost.Sign(config, ids, message)).(frost.Signature)
+			assert.True(t, signature.Verify(config.PublicKey, message))
+		}(id)
+	}
+	wg.Wait()
+}
+
+func TestNewWrongPeer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	ids := party.IDSlice{"a", "b"}
+	hosts, pubsubs, peers := newHosts(ctx, t, ids)
+	_, err := p2p.New(ctx, hosts["a"], pubsubs["a"], "session", "b", peers)
+	assert.Error(t, err)
+}